@@ -2,11 +2,13 @@
 package all
 
 import (
-	_ "github.com/c2fo/vfs/v6/backend/azure" // register azure backend
-	_ "github.com/c2fo/vfs/v6/backend/ftp"   // register sftp backend
-	_ "github.com/c2fo/vfs/v6/backend/gs"    // register gs backend
-	_ "github.com/c2fo/vfs/v6/backend/mem"   // register mem backend
-	_ "github.com/c2fo/vfs/v6/backend/os"    // register os backend
-	_ "github.com/c2fo/vfs/v6/backend/s3"    // register s3 backend
-	_ "github.com/c2fo/vfs/v6/backend/sftp"  // register sftp backend
+	_ "github.com/c2fo/vfs/v6/backend/azure"  // register azure backend
+	_ "github.com/c2fo/vfs/v6/backend/ftp"    // register sftp backend
+	_ "github.com/c2fo/vfs/v6/backend/gs"     // register gs backend
+	_ "github.com/c2fo/vfs/v6/backend/http"   // register http backend
+	_ "github.com/c2fo/vfs/v6/backend/mem"    // register mem backend
+	_ "github.com/c2fo/vfs/v6/backend/os"     // register os backend
+	_ "github.com/c2fo/vfs/v6/backend/s3"     // register s3 backend
+	_ "github.com/c2fo/vfs/v6/backend/sftp"   // register sftp backend
+	_ "github.com/c2fo/vfs/v6/backend/webdav" // register webdav backend
 )
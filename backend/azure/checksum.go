@@ -0,0 +1,75 @@
+package azure
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Checksum returns the checksum of the file's contents for algorithm, vfs.ChecksumCRC32C, vfs.ChecksumMD5 or
+// vfs.ChecksumSHA256.  The Content-MD5 stored by Azure is returned when there is one, otherwise the blob is streamed
+// to compute the checksum.
+func (f *File) Checksum(algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	if _, err := utils.NewChecksumHash(algorithm); err != nil {
+		return nil, err
+	}
+
+	props, err := f.properties()
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == vfs.ChecksumMD5 && props.ContentMD5 != nil {
+		return props.ContentMD5, nil
+	}
+
+	// stream a copy of the file so its cursor and any pending writes are left alone
+	return utils.ComputeChecksum(&File{
+		fileSystem: f.fileSystem,
+		container:  f.container,
+		name:       f.name,
+		versionID:  f.versionID,
+	}, algorithm)
+}
+
+// uploadChecksum returns the MD5 of content to send with an upload of file, or nil if file's file system has no
+// ChecksumAlgorithm.  content is rewound afterwards.
+func uploadChecksum(file vfs.File, content io.ReadSeeker) ([]byte, error) {
+	f, ok := file.(*File)
+	if !ok || f.fileSystem.options.ChecksumAlgorithm == "" {
+		return nil, nil
+	}
+	if algorithm := f.fileSystem.options.ChecksumAlgorithm; algorithm != vfs.ChecksumMD5 {
+		return nil, fmt.Errorf("%w: azure only verifies %s uploads", vfs.ErrChecksumAlgorithm, vfs.ChecksumMD5)
+	}
+
+	h, _ := utils.NewChecksumHash(vfs.ChecksumMD5)
+	if _, err := io.Copy(h, content); err != nil {
+		return nil, err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// verifiedReader wraps reader, a download of the whole blob, so reading it to the end returns vfs.ErrChecksumMismatch
+// if the blob's contents don't match its stored Content-MD5.  reader is returned as is when the file system has no
+// ChecksumAlgorithm or the blob has no Content-MD5.
+func (f *File) verifiedReader(reader io.ReadCloser) (io.ReadCloser, error) {
+	if f.fileSystem.options.ChecksumAlgorithm == "" {
+		return reader, nil
+	}
+
+	props, err := f.properties()
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	if props.ContentMD5 == nil {
+		return reader, nil
+	}
+	h, _ := utils.NewChecksumHash(vfs.ChecksumMD5)
+	return utils.NewChecksumReader(reader, h, props.ContentMD5), nil
+}
@@ -0,0 +1,90 @@
+package azure
+
+import (
+	"crypto/md5" //nolint:gosec // MD5 is used for integrity checks, not security
+	"crypto/sha256"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type ChecksumTestSuite struct {
+	suite.Suite
+}
+
+func (s *ChecksumTestSuite) TestChecksum() {
+	s.Implements((*vfs.Checksummer)(nil), &File{}, "Does not implement the vfs.Checksummer interface")
+
+	md5Sum := md5.Sum([]byte("Hello World!")) //nolint:gosec // MD5 is used for integrity checks, not security
+	client := MockAzureClient{PropertiesResult: &BlobProperties{Size: 12, ContentMD5: md5Sum[:]}}
+	fs := NewFileSystem().WithClient(&client)
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	sum, err := f.(*File).Checksum(vfs.ChecksumMD5)
+	s.NoError(err)
+	s.Equal(md5Sum[:], sum, "the stored Content-MD5 should be returned")
+
+	// Azure only stores MD5s, so the blob is streamed
+	client.ExpectedResult = io.NopCloser(strings.NewReader("Hello World!"))
+	sha := sha256.Sum256([]byte("Hello World!"))
+	sum, err = f.(*File).Checksum(vfs.ChecksumSHA256)
+	s.NoError(err)
+	s.Equal(sha[:], sum)
+
+	_, err = f.(*File).Checksum("CRC64")
+	s.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+}
+
+func (s *ChecksumTestSuite) TestUploadChecksum() {
+	fs := NewFileSystem().WithClient(&MockAzureClient{})
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+	content := strings.NewReader("Hello World!")
+
+	sum, err := uploadChecksum(f, content)
+	s.NoError(err)
+	s.Nil(sum, "no checksum is sent without a ChecksumAlgorithm")
+
+	fs.options.ChecksumAlgorithm = vfs.ChecksumMD5
+	sum, err = uploadChecksum(f, content)
+	s.NoError(err)
+	md5Sum := md5.Sum([]byte("Hello World!")) //nolint:gosec // MD5 is used for integrity checks, not security
+	s.Equal(md5Sum[:], sum)
+	rest, err := io.ReadAll(content)
+	s.NoError(err)
+	s.Equal("Hello World!", string(rest), "content should be rewound")
+
+	fs.options.ChecksumAlgorithm = vfs.ChecksumCRC32C
+	_, err = uploadChecksum(f, content)
+	s.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+}
+
+func (s *ChecksumTestSuite) TestReadVerifies() {
+	md5Sum := md5.Sum([]byte("Hello World!")) //nolint:gosec // MD5 is used for integrity checks, not security
+	client := MockAzureClient{
+		PropertiesResult: &BlobProperties{Size: 12, ContentMD5: md5Sum[:]},
+		ExpectedResult:   io.NopCloser(strings.NewReader("Hello World!")),
+	}
+	fs := NewFileSystem().WithClient(&client)
+	fs.options.ChecksumAlgorithm = vfs.ChecksumMD5
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	contents, err := io.ReadAll(f)
+	s.NoError(err)
+	s.Equal("Hello World!", string(contents))
+	s.NoError(f.Close())
+
+	client.ExpectedResult = io.NopCloser(strings.NewReader("Hello W0rld!"))
+	_, err = io.ReadAll(f)
+	s.ErrorIs(err, vfs.ErrChecksumMismatch)
+}
+
+func TestChecksum(t *testing.T) {
+	suite.Run(t, new(ChecksumTestSuite))
+}
@@ -1,10 +1,14 @@
 package azure
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -46,6 +50,84 @@ type Client interface {
 	DeleteAllVersions(file vfs.File) error
 }
 
+// ErrNotSupported is returned by operations that need the file system's Client to implement an optional interface,
+// such as VersionLister, that it doesn't.
+var ErrNotSupported = errors.New("azure client doesn't support the operation")
+
+// VersionLister is an optional interface of a Client that lists the versions of blobs, used by File.Versions.
+type VersionLister interface {
+	// ListVersions should return all versions of the blob specified by the parameter file, newest first.
+	ListVersions(file vfs.File) ([]BlobVersion, error)
+}
+
+// TierSetter is an optional interface of a Client that moves blobs between access tiers, used by File.SetAccessTier and
+// File.Rehydrate.
+type TierSetter interface {
+	// SetTier should move the blob specified by the parameter file to tier, rehydrating an archived blob with priority.
+	SetTier(file vfs.File, tier azblob.AccessTierType, priority azblob.RehydratePriorityType) error
+}
+
+// Tagger is an optional interface of a Client that reads, writes and queries blob index tags, used by File.Tags,
+// File.SetTags and Location.FindBlobsByTags.
+type Tagger interface {
+	// GetTags should return the blob index tags of the blob specified by the parameter file.
+	GetTags(file vfs.File) (map[string]string, error)
+
+	// SetTags should replace the blob index tags of the blob specified by the parameter file with tags.
+	SetTags(file vfs.File, tags map[string]string) error
+
+	// FindBlobsByTags should return the full names of the blobs in the specified location, and below it, whose tags
+	// match the where expression.
+	FindBlobsByTags(l vfs.Location, where string) ([]string, error)
+}
+
+// Leaser is an optional interface of a Client that manages leases on blobs, used by File.AcquireLease, File.RenewLease,
+// File.ReleaseLease and File.BreakLease.
+type Leaser interface {
+	// AcquireLease should acquire a lease for duration on the blob specified by the parameter file, returning its ID.
+	AcquireLease(file vfs.File, duration time.Duration) (string, error)
+
+	// RenewLease should renew the lease with leaseID on the blob specified by the parameter file.
+	RenewLease(file vfs.File, leaseID string) error
+
+	// ReleaseLease should release the lease with leaseID on the blob specified by the parameter file.
+	ReleaseLease(file vfs.File, leaseID string) error
+
+	// BreakLease should break the lease on the blob specified by the parameter file after breakPeriod, returning how
+	// long the lease remains.
+	BreakLease(file vfs.File, breakPeriod time.Duration) (time.Duration, error)
+}
+
+// DFSClient is an optional interface of a Client that uses the Data Lake Storage endpoint of storage accounts with a
+// hierarchical namespace, used by the HierarchicalNamespace option, File.AccessControl and the directory operations of
+// Location.
+type DFSClient interface {
+	// HierarchicalNamespace should return whether the storage account of the specified location has a hierarchical
+	// namespace.
+	HierarchicalNamespace(l vfs.Location) (bool, error)
+
+	// Rename should atomically rename the blob specified by srcFile to the one specified by tgtFile.
+	Rename(srcFile, tgtFile vfs.File) error
+
+	// RenameDirectory should atomically rename the directory specified by src, and everything in it, to tgt.
+	RenameDirectory(src, tgt vfs.Location) error
+
+	// CreateDirectory should create the directory specified by the parameter l.
+	CreateDirectory(l vfs.Location) error
+
+	// DeleteDirectory should delete the directory specified by the parameter l and everything in it.
+	DeleteDirectory(l vfs.Location) error
+
+	// GetAccessControl should return the AccessControl of the file or directory specified by containerURI and path.
+	GetAccessControl(containerURI, path string) (*AccessControl, error)
+}
+
+// notSupported returns an error matching ErrNotSupported for a client that doesn't implement the optional interface
+// named iface.
+func notSupported(client Client, iface string) error {
+	return fmt.Errorf("%w: %T doesn't implement azure.%s", ErrNotSupported, client, iface)
+}
+
 // DefaultClient is the main implementation that actually makes the calls to Azure Blob Storage
 type DefaultClient struct {
 	pipeline pipeline.Pipeline
@@ -108,11 +190,24 @@ func (a *DefaultClient) Upload(file vfs.File, content io.ReadSeeker) error {
 		return err
 	}
 
+	contentMD5, err := uploadChecksum(file, content)
+	if err != nil {
+		return err
+	}
+
 	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
 	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
-	_, err = blobURL.Upload(context.Background(), content, azblob.BlobHTTPHeaders{}, azblob.Metadata{},
-		azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, nil, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
-	return err
+	resp, err := blobURL.Upload(context.Background(), content, azblob.BlobHTTPHeaders{ContentMD5: contentMD5}, azblob.Metadata{},
+		accessConditions(file), accessTier(file), nil, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+	if err != nil {
+		return err
+	}
+
+	// Azure returns the MD5 of the contents it received
+	if contentMD5 != nil && resp.ContentMD5() != nil && !bytes.Equal(contentMD5, resp.ContentMD5()) {
+		return vfs.ErrChecksumMismatch
+	}
+	return nil
 }
 
 // SetMetadata sets the given metadata for the blob
@@ -124,7 +219,8 @@ func (a *DefaultClient) SetMetadata(file vfs.File, metadata map[string]string) e
 
 	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
 	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
-	_, err = blobURL.SetMetadata(context.Background(), metadata, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	_, err = blobURL.SetMetadata(context.Background(), metadata, azblob.BlobAccessConditions{LeaseAccessConditions: leaseConditions(file)},
+		azblob.ClientProvidedKeyOptions{})
 	return err
 }
 
@@ -136,7 +232,7 @@ func (a *DefaultClient) Download(file vfs.File) (io.ReadCloser, error) {
 	}
 
 	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
-	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
+	blobURL := versionedBlobURL(containerURL, file)
 	get, err := blobURL.Download(context.Background(), 0, 0, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
 	if err != nil {
 		return nil, err
@@ -144,12 +240,9 @@ func (a *DefaultClient) Download(file vfs.File) (io.ReadCloser, error) {
 	return get.Body(azblob.RetryReaderOptions{}), nil
 }
 
-// Copy copies srcFile to the destination tgtFile within Azure Blob Storage.  Note that in the case where we get
-// encoded spaces in the file name (i.e. %20) the '%' must be encoded or the copy command will return a not found
-// error.
+// Copy copies srcFile to the destination tgtFile within Azure Blob Storage.
 func (a *DefaultClient) Copy(srcFile, tgtFile vfs.File) error {
-	// Can't use url.PathEscape here since that will escape everything (even the directory separators)
-	srcURL, err := url.Parse(strings.Replace(srcFile.URI(), "%", "%25", -1))
+	srcURL, err := copySourceURL(srcFile)
 	if err != nil {
 		return err
 	}
@@ -162,8 +255,8 @@ func (a *DefaultClient) Copy(srcFile, tgtFile vfs.File) error {
 	containerURL := azblob.NewContainerURL(*tgtURL, a.pipeline)
 	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(tgtFile.Path()))
 	ctx := context.Background()
-	resp, err := blobURL.StartCopyFromURL(ctx, *srcURL, azblob.Metadata{}, azblob.ModifiedAccessConditions{},
-		azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, nil)
+	resp, err := blobURL.StartCopyFromURL(ctx, srcURL, azblob.Metadata{}, azblob.ModifiedAccessConditions{},
+		azblob.BlobAccessConditions{LeaseAccessConditions: leaseConditions(tgtFile)}, accessTier(tgtFile), nil)
 	if err != nil {
 		return err
 	}
@@ -179,6 +272,163 @@ func (a *DefaultClient) Copy(srcFile, tgtFile vfs.File) error {
 	return fmt.Errorf("copy failed ERROR[%s]", resp.ErrorCode())
 }
 
+// SetTier moves the given file's blob to tier, rehydrating an archived blob with priority.
+func (a *DefaultClient) SetTier(file vfs.File, tier azblob.AccessTierType, priority azblob.RehydratePriorityType) error {
+	URL, err := url.Parse(file.Location().(*Location).ContainerURL())
+	if err != nil {
+		return err
+	}
+
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
+	_, err = blobURL.SetTier(context.Background(), tier, leaseConditions(file), priority)
+	return err
+}
+
+// GetTags returns the blob index tags of the given file's blob, or of the version it refers to.
+func (a *DefaultClient) GetTags(file vfs.File) (map[string]string, error) {
+	URL, err := url.Parse(file.Location().(*Location).ContainerURL())
+	if err != nil {
+		return nil, err
+	}
+
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	resp, err := versionedBlobURL(containerURL, file).GetTags(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(resp.BlobTagSet))
+	for _, tag := range resp.BlobTagSet {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+// SetTags replaces the blob index tags of the given file's blob, or of the version it refers to, with tags.
+func (a *DefaultClient) SetTags(file vfs.File, tags map[string]string) error {
+	URL, err := url.Parse(file.Location().(*Location).ContainerURL())
+	if err != nil {
+		return err
+	}
+
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	_, err = versionedBlobURL(containerURL, file).SetTags(context.Background(), nil, nil, nil, tags)
+	return err
+}
+
+// FindBlobsByTags returns the full names of the blobs in the given location, and below it, whose blob index tags match
+// the where expression.  Azure searches the whole storage account, so the query is limited to the location's container
+// and the results to its path.
+func (a *DefaultClient) FindBlobsByTags(l vfs.Location, where string) ([]string, error) {
+	URL, err := url.Parse(l.(*Location).ContainerURL())
+	if err != nil {
+		return nil, err
+	}
+	// the service is one level above the container, keeping any emulator account in the path
+	URL.Path = path.Dir(strings.TrimSuffix(URL.Path, "/"))
+
+	serviceURL := azblob.NewServiceURL(*URL, a.pipeline)
+	query := fmt.Sprintf("@container='%s' AND %s", l.Volume(), where)
+	prefix := utils.RemoveLeadingSlash(l.Path())
+	ctx := context.Background()
+	var list []string
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		segment, err := serviceURL.FindBlobsByTags(ctx, nil, nil, &query, marker, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		marker = azblob.Marker{Val: segment.NextMarker}
+
+		for i := range segment.Blobs {
+			if strings.HasPrefix(segment.Blobs[i].Name, prefix) {
+				list = append(list, segment.Blobs[i].Name)
+			}
+		}
+	}
+	return list, nil
+}
+
+// AcquireLease acquires a lease for duration, or azure.LeaseInfinite, on the given file's blob and returns its ID.
+func (a *DefaultClient) AcquireLease(file vfs.File, duration time.Duration) (string, error) {
+	blobURL, err := a.blobURL(file)
+	if err != nil {
+		return "", err
+	}
+
+	seconds := int32(-1)
+	if duration != LeaseInfinite {
+		seconds = int32(duration / time.Second)
+	}
+	resp, err := blobURL.AcquireLease(context.Background(), "", seconds, azblob.ModifiedAccessConditions{})
+	if err != nil {
+		return "", err
+	}
+	return resp.LeaseID(), nil
+}
+
+// RenewLease renews the lease with leaseID on the given file's blob.
+func (a *DefaultClient) RenewLease(file vfs.File, leaseID string) error {
+	blobURL, err := a.blobURL(file)
+	if err != nil {
+		return err
+	}
+	_, err = blobURL.RenewLease(context.Background(), leaseID, azblob.ModifiedAccessConditions{})
+	return err
+}
+
+// ReleaseLease releases the lease with leaseID on the given file's blob.
+func (a *DefaultClient) ReleaseLease(file vfs.File, leaseID string) error {
+	blobURL, err := a.blobURL(file)
+	if err != nil {
+		return err
+	}
+	_, err = blobURL.ReleaseLease(context.Background(), leaseID, azblob.ModifiedAccessConditions{})
+	return err
+}
+
+// BreakLease breaks the lease on the given file's blob after breakPeriod and returns how long the lease remains.
+func (a *DefaultClient) BreakLease(file vfs.File, breakPeriod time.Duration) (time.Duration, error) {
+	blobURL, err := a.blobURL(file)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := blobURL.BreakLease(context.Background(), int32(breakPeriod/time.Second), azblob.ModifiedAccessConditions{})
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(resp.LeaseTime()) * time.Second, nil
+}
+
+// blobURL returns the BlockBlobURL of the given file's blob.
+func (a *DefaultClient) blobURL(file vfs.File) (azblob.BlockBlobURL, error) {
+	URL, err := url.Parse(file.Location().(*Location).ContainerURL())
+	if err != nil {
+		return azblob.BlockBlobURL{}, err
+	}
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	return containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path())), nil
+}
+
+// copySourceURL returns the URL Azure copies srcFile from: its blob URL on the blob endpoint of its own file system,
+// with its shared access signature if it has one, and the version it refers to.
+func copySourceURL(srcFile vfs.File) (url.URL, error) {
+	containerURL, err := url.Parse(srcFile.Location().(*Location).ContainerURL())
+	if err != nil {
+		return url.URL{}, err
+	}
+	// the blob URL escapes the blob name, including any '%' in it, without escaping the directory separators
+	srcURL := azblob.NewContainerURL(*containerURL, nil).NewBlockBlobURL(utils.RemoveLeadingSlash(srcFile.Path())).URL()
+	if f, ok := srcFile.(*File); ok && f.versionID != "" {
+		if srcURL.RawQuery != "" {
+			srcURL.RawQuery += "&"
+		}
+		srcURL.RawQuery += url.Values{"versionid": {f.versionID}}.Encode()
+	}
+	return srcURL, nil
+}
+
 // List will return a listing of the contents of the given location.  Each item in the list will contain the full key
 // as specified by the azure blob (incliding the virtual 'path').
 func (a *DefaultClient) List(l vfs.Location) ([]string, error) {
@@ -192,7 +442,7 @@ func (a *DefaultClient) List(l vfs.Location) ([]string, error) {
 	var list []string
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		listBlob, err := containerURL.ListBlobsHierarchySegment(ctx, marker, "/",
-			azblob.ListBlobsSegmentOptions{Prefix: utils.RemoveLeadingSlash(l.Path())})
+			azblob.ListBlobsSegmentOptions{Prefix: utils.RemoveLeadingSlash(l.Path()), Details: azblob.BlobListingDetails{Metadata: true}})
 		if err != nil {
 			return []string{}, err
 		}
@@ -200,6 +450,10 @@ func (a *DefaultClient) List(l vfs.Location) ([]string, error) {
 		marker = listBlob.NextMarker
 
 		for i := range listBlob.Segment.BlobItems {
+			// directories of accounts with a hierarchical namespace are listed as blobs too
+			if isDirectory(listBlob.Segment.BlobItems[i].Metadata) {
+				continue
+			}
 			list = append(list, listBlob.Segment.BlobItems[i].Name)
 		}
 	}
@@ -214,8 +468,8 @@ func (a *DefaultClient) Delete(file vfs.File) error {
 	}
 
 	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
-	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
-	_, err = blobURL.Delete(context.Background(), azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+	blobURL := versionedBlobURL(containerURL, file)
+	_, err = blobURL.Delete(context.Background(), azblob.DeleteSnapshotsOptionNone, accessConditions(file))
 	return err
 }
 
@@ -269,3 +523,58 @@ func (a *DefaultClient) getBlobVersions(containerURL azblob.ContainerURL, blobNa
 	}
 	return versions, nil
 }
+
+// ListVersions returns all versions of the given file's blob, newest first.
+func (a *DefaultClient) ListVersions(file vfs.File) ([]BlobVersion, error) {
+	URL, err := url.Parse(file.Location().(*Location).ContainerURL())
+	if err != nil {
+		return nil, err
+	}
+
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	blobName := utils.RemoveLeadingSlash(file.Path())
+	ctx := context.Background()
+	versions := make([]BlobVersion, 0)
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		listBlob, err := containerURL.ListBlobsFlatSegment(ctx, marker,
+			azblob.ListBlobsSegmentOptions{Prefix: blobName, Details: azblob.BlobListingDetails{Versions: true}})
+		if err != nil {
+			return nil, err
+		}
+
+		marker = listBlob.NextMarker
+
+		for i := range listBlob.Segment.BlobItems {
+			blobItem := listBlob.Segment.BlobItems[i]
+			// the prefix also matches other blobs whose names start with this one's, ie "file.txt.bak"
+			if blobItem.Name != blobName || blobItem.VersionID == nil {
+				continue
+			}
+			version := BlobVersion{
+				VersionID:    *blobItem.VersionID,
+				LastModified: blobItem.Properties.LastModified,
+				IsLatest:     blobItem.IsCurrentVersion != nil && *blobItem.IsCurrentVersion,
+			}
+			if blobItem.Properties.ContentLength != nil {
+				version.Size = uint64(*blobItem.Properties.ContentLength)
+			}
+			versions = append(versions, version)
+		}
+	}
+
+	// version IDs are timestamps, so they sort chronologically
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].VersionID > versions[j].VersionID
+	})
+
+	return versions, nil
+}
+
+// versionedBlobURL returns the BlockBlobURL of the given file's blob, or of the version it refers to.
+func versionedBlobURL(containerURL azblob.ContainerURL, file vfs.File) azblob.BlockBlobURL {
+	blobURL := containerURL.NewBlockBlobURL(utils.RemoveLeadingSlash(file.Path()))
+	if f, ok := file.(*File); ok && f.versionID != "" {
+		return blobURL.WithVersionID(f.versionID)
+	}
+	return blobURL
+}
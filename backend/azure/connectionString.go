@@ -0,0 +1,86 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// The well-known account, key and blob endpoint of the Azurite storage emulator, used by "UseDevelopmentStorage=true".
+const (
+	devStoreAccountName  = "devstoreaccount1"
+	devStoreAccountKey   = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	devStoreBlobEndpoint = "http://127.0.0.1:10000/devstoreaccount1"
+)
+
+// connectionString holds the blob storage settings of an Azure Storage connection string.
+type connectionString struct {
+	accountName  string
+	accountKey   string
+	sasToken     string
+	blobEndpoint string
+}
+
+// parseConnectionString parses the semicolon separated key=value settings of an Azure Storage connection string.  The
+// blob endpoint is the BlobEndpoint setting, or is built from DefaultEndpointsProtocol, AccountName and
+// EndpointSuffix.  Without an AccountName, the account is taken from the BlobEndpoint: the first label of
+// <account>.blob.<suffix> hosts, or the first path segment of emulator endpoints such as Azurite's.
+func parseConnectionString(s string) (connectionString, error) {
+	settings := map[string]string{}
+	for _, setting := range strings.Split(s, ";") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(setting, "=")
+		if !ok {
+			return connectionString{}, fmt.Errorf("azure connection string setting %q isn't a key=value pair", setting)
+		}
+		settings[strings.ToLower(key)] = value
+	}
+
+	if strings.EqualFold(settings["usedevelopmentstorage"], "true") {
+		return connectionString{
+			accountName:  devStoreAccountName,
+			accountKey:   devStoreAccountKey,
+			blobEndpoint: devStoreBlobEndpoint,
+		}, nil
+	}
+
+	cs := connectionString{
+		accountName:  settings["accountname"],
+		accountKey:   settings["accountkey"],
+		sasToken:     strings.TrimPrefix(settings["sharedaccesssignature"], "?"),
+		blobEndpoint: strings.TrimSuffix(settings["blobendpoint"], "/"),
+	}
+	if cs.blobEndpoint == "" {
+		if cs.accountName == "" {
+			return connectionString{}, errors.New("azure connection string requires an AccountName or a BlobEndpoint")
+		}
+		protocol, suffix := settings["defaultendpointsprotocol"], settings["endpointsuffix"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		if suffix == "" {
+			suffix = "core.windows.net"
+		}
+		cs.blobEndpoint = fmt.Sprintf("%s://%s.blob.%s", protocol, cs.accountName, suffix)
+	}
+
+	if cs.accountName == "" {
+		endpoint, err := url.Parse(cs.blobEndpoint)
+		if err != nil {
+			return connectionString{}, fmt.Errorf("azure connection string BlobEndpoint: %w", err)
+		}
+		if account, _, ok := strings.Cut(endpoint.Hostname(), ".blob."); ok {
+			cs.accountName = account
+		} else {
+			cs.accountName, _, _ = strings.Cut(strings.TrimPrefix(endpoint.Path, "/"), "/")
+		}
+	}
+	if cs.accountKey == "" && cs.sasToken == "" {
+		return connectionString{}, errors.New("azure connection string requires an AccountKey or a SharedAccessSignature")
+	}
+	return cs, nil
+}
@@ -0,0 +1,206 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// serviceCodePathAlreadyExists is returned by the Data Lake Storage endpoint when an If-None-Match condition fails.
+const serviceCodePathAlreadyExists azblob.ServiceCodeType = "PathAlreadyExists"
+
+// dfsError is an error response of the Data Lake Storage endpoint.  It implements azblob.StorageError, like the
+// errors of the blob endpoint.
+type dfsError struct {
+	response *http.Response
+	code     azblob.ServiceCodeType
+}
+
+func (e *dfsError) Error() string {
+	return fmt.Sprintf("azure dfs %s %s: %s %s", e.response.Request.Method, e.response.Request.URL.Path, e.response.Status, e.code)
+}
+
+// ServiceCode returns the error code of the response.
+func (e *dfsError) ServiceCode() azblob.ServiceCodeType {
+	return e.code
+}
+
+// Response returns the error response.
+func (e *dfsError) Response() *http.Response {
+	return e.response
+}
+
+// Timeout returns false
+func (e *dfsError) Timeout() bool {
+	return false
+}
+
+// Temporary returns false
+func (e *dfsError) Temporary() bool {
+	return false
+}
+
+// HierarchicalNamespace returns whether the storage account of the given location has a hierarchical namespace.
+func (a *DefaultClient) HierarchicalNamespace(l vfs.Location) (bool, error) {
+	URL, err := url.Parse(l.(*Location).ContainerURL())
+	if err != nil {
+		return false, err
+	}
+
+	containerURL := azblob.NewContainerURL(*URL, a.pipeline)
+	resp, err := containerURL.GetAccountInfo(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(resp.Response().Header.Get("x-ms-is-hns-enabled"), "true"), nil
+}
+
+// Rename atomically renames the blob of srcFile to the path of tgtFile, within the same storage account.  The target's
+// conditions and both files' leases are passed.
+func (a *DefaultClient) Rename(srcFile, tgtFile vfs.File) error {
+	tgtURL, err := dfsURL(tgtFile.Location().(*Location).ContainerURL(), tgtFile.Path())
+	if err != nil {
+		return err
+	}
+
+	source := (&url.URL{Path: "/" + srcFile.Location().Volume() + srcFile.Path()}).EscapedPath()
+	if sas := srcFile.Location().(*Location).fileSystem.options.sasToken(srcFile.Location().Volume()); sas != "" {
+		source += "?" + sas
+	}
+	header := http.Header{"x-ms-rename-source": {source}}
+	conditions := accessConditions(tgtFile)
+	if conditions.ModifiedAccessConditions.IfMatch != azblob.ETagNone {
+		header.Set("If-Match", string(conditions.ModifiedAccessConditions.IfMatch))
+	}
+	if conditions.ModifiedAccessConditions.IfNoneMatch != azblob.ETagNone {
+		header.Set("If-None-Match", string(conditions.ModifiedAccessConditions.IfNoneMatch))
+	}
+	if id := conditions.LeaseAccessConditions.LeaseID; id != "" {
+		header.Set("x-ms-lease-id", id)
+	}
+	if id := leaseConditions(srcFile).LeaseID; id != "" {
+		header.Set("x-ms-source-lease-id", id)
+	}
+
+	_, err = a.dfsDo(http.MethodPut, tgtURL, nil, header)
+	return err
+}
+
+// RenameDirectory atomically renames the directory of the src location, and everything in it, to the path of the tgt
+// location, within the same storage account.
+func (a *DefaultClient) RenameDirectory(src, tgt vfs.Location) error {
+	tgtURL, err := dfsURL(tgt.(*Location).ContainerURL(), strings.TrimSuffix(tgt.Path(), "/"))
+	if err != nil {
+		return err
+	}
+
+	source := (&url.URL{Path: "/" + src.Volume() + strings.TrimSuffix(src.Path(), "/")}).EscapedPath()
+	if sas := src.(*Location).fileSystem.options.sasToken(src.Volume()); sas != "" {
+		source += "?" + sas
+	}
+	_, err = a.dfsDo(http.MethodPut, tgtURL, nil, http.Header{"x-ms-rename-source": {source}})
+	return err
+}
+
+// CreateDirectory creates the directory of the given location, and any missing parents.
+func (a *DefaultClient) CreateDirectory(l vfs.Location) error {
+	URL, err := dfsURL(l.(*Location).ContainerURL(), strings.TrimSuffix(l.Path(), "/"))
+	if err != nil {
+		return err
+	}
+	_, err = a.dfsDo(http.MethodPut, URL, url.Values{"resource": {"directory"}}, nil)
+	return err
+}
+
+// DeleteDirectory deletes the directory of the given location and everything in it.
+func (a *DefaultClient) DeleteDirectory(l vfs.Location) error {
+	URL, err := dfsURL(l.(*Location).ContainerURL(), strings.TrimSuffix(l.Path(), "/"))
+	if err != nil {
+		return err
+	}
+
+	// directories with many ACLs to check are deleted in several requests
+	query := url.Values{"recursive": {"true"}}
+	for {
+		resp, err := a.dfsDo(http.MethodDelete, URL, query, nil)
+		if err != nil {
+			return err
+		}
+		continuation := resp.Header.Get("x-ms-continuation")
+		if continuation == "" {
+			return nil
+		}
+		query.Set("continuation", continuation)
+	}
+}
+
+// GetAccessControl returns the owner, group, permissions and POSIX ACL of the file or directory at path in the
+// container specified by containerURI.
+func (a *DefaultClient) GetAccessControl(containerURI, path string) (*AccessControl, error) {
+	URL, err := dfsURL(containerURI, path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.dfsDo(http.MethodHead, URL, url.Values{"action": {"getAccessControl"}, "upn": {"false"}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessControl{
+		Owner:       resp.Header.Get("x-ms-owner"),
+		Group:       resp.Header.Get("x-ms-group"),
+		Permissions: resp.Header.Get("x-ms-permissions"),
+		ACL:         resp.Header.Get("x-ms-acl"),
+	}, nil
+}
+
+// dfsDo sends a request to URL with query and header through the client's pipeline, which authenticates it like the
+// requests to the blob endpoint, returning a *dfsError for an error response.
+func (a *DefaultClient) dfsDo(method string, URL url.URL, query url.Values, header http.Header) (*http.Response, error) {
+	values := URL.Query()
+	for k, v := range query {
+		values[k] = v
+	}
+	URL.RawQuery = values.Encode()
+
+	req, err := pipeline.NewRequest(method, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+	req.Header.Set("x-ms-version", azblob.ServiceVersion)
+
+	resp, err := a.pipeline.Do(context.Background(), nil, req)
+	if err != nil {
+		return nil, err
+	}
+	response := resp.Response()
+	_ = response.Body.Close()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return nil, &dfsError{response: response, code: azblob.ServiceCodeType(response.Header.Get("x-ms-error-code"))}
+	}
+	return response, nil
+}
+
+// dfsURL returns the URL of path in the container specified by containerURI on the account's Data Lake Storage
+// endpoint, which is its blob endpoint with "dfs" in place of "blob".  Custom endpoints, such as emulators', are used
+// as they are.
+func dfsURL(containerURI, path string) (url.URL, error) {
+	URL, err := url.Parse(containerURI)
+	if err != nil {
+		return url.URL{}, err
+	}
+	URL.Host = strings.Replace(URL.Host, ".blob.", ".dfs.", 1)
+	URL.Path = utils.EnsureTrailingSlash(URL.Path) + utils.RemoveLeadingSlash(path)
+	return *URL, nil
+}
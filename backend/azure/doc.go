@@ -47,17 +47,132 @@ would have to be cast as azure.Filesystem to use the following:
 	    fs = fs.WithClient(client)
 	}
 
+# Versions
+
+In storage accounts with blob versioning enabled, the versions of a blob can be listed with the azure.File method
+Versions().  AtVersion() returns a read-only File pinned to a version ID, whose reads, Size, LastModified and copies use
+that version, and RestoreVersion() makes an older version current again by copying it over the blob:
+
+	file := vfsFile.(*azure.File)
+	versions, err := file.Versions()
+	...
+	old, err := file.AtVersion(versions[1].VersionID)
+	...
+	err = file.RestoreVersion(versions[1].VersionID)
+
+# Checksums
+
+Setting Options.ChecksumAlgorithm to vfs.ChecksumMD5 enables integrity checks.  Uploads send the Content-MD5 of their
+contents, which Azure stores with the blob, and fail with vfs.ErrChecksumMismatch if the MD5 of the contents Azure
+received differs.  Downloads verify the blob's contents against its Content-MD5.
+
+The azure.File method Checksum() returns the stored Content-MD5, or streams the blob to compute other checksums:
+
+	sum, err := vfsFile.(*azure.File).Checksum(vfs.ChecksumMD5)
+
+# Preconditions
+
+Writers that mustn't overwrite each other's changes can use Azure's ETag conditions.  The azure.File methods IfMatch
+and IfNotExist return a File for the same blob whose uploads only succeed if the blob's ETag is unchanged, or if the
+blob doesn't exist.  Deletes of an IfMatch File are conditional too.  Copies onto a conditional File are streamed
+rather than native.  A rejected request returns a *vfs.PreconditionFailedError, matching vfs.ErrPreconditionFailed:
+
+	eTag, err := azureFile.ETag()
+	conditional := azureFile.IfMatch(eTag)
+	_, err = conditional.Write(updated)
+	if err = conditional.Close(); errors.Is(err, vfs.ErrPreconditionFailed) {
+		// another writer got there first, re-read the file and retry
+	}
+
+# Access Tiers
+
+Blobs are uploaded, and natively copied, in the account's default access tier unless Options.AccessTier names another,
+such as azblob.AccessTierCool, azure.AccessTierCold or azblob.AccessTierArchive.  The azure.File method WithAccessTier
+returns a File for the same blob written in its own tier.  Properties() returns the blob's BlobProperties, whose
+AccessTier and ArchiveStatus report its current tier and any rehydration in progress.
+
+Archived blobs can't be read or copied until they're rehydrated to an online tier, which takes hours.  Reading one
+returns an error matching azure.ErrBlobArchived.  Rehydrate starts moving the blob back, and SetAccessTier moves a blob
+between tiers:
+
+	archive := azureFile.WithAccessTier(azblob.AccessTierArchive)
+	_, err = archive.Write(contents)
+	err = archive.Close()
+	...
+	if _, err = archive.Read(buf); errors.Is(err, azure.ErrBlobArchived) {
+		err = archive.Rehydrate(azblob.AccessTierHot, azblob.RehydratePriorityStandard)
+	}
+
+# Blob Index Tags
+
+Blob index tags, unlike metadata, are indexed by Azure and can be queried.  The azure.File methods Tags() and SetTags()
+read and replace a blob's tags, and BlobProperties.TagCount holds how many it has.  The azure.Location method
+FindBlobsByTags returns the files in the location, and below it, whose tags match an Azure tag filter expression:
+
+	err = azureFile.SetTags(map[string]string{"retention": "1y"})
+	...
+	expired, err := azureLocation.FindBlobsByTags("retention='1y' AND \"created\" < '2024-01-01'")
+
+Tags are indexed asynchronously, so a blob tagged moments ago may not be found yet.
+
+# Leases
+
+Blob leases can coordinate processes on different machines.  The azure.File method AcquireLease takes a lease on an
+existing blob for 15 to 60 seconds, or azure.LeaseInfinite, and returns an error matching azure.ErrLeased if another
+holder has one.  A held lease is renewed in the background until ReleaseLease, and the File's uploads, deletes, Touch
+and native copies onto it pass the lease ID.  RenewLease renews it at once, and BreakLease ends any holder's lease,
+such as one left by a crashed process:
+
+	if _, err := lockFile.AcquireLease(30 * time.Second); errors.Is(err, azure.ErrLeased) {
+		// another process holds the lock
+		return err
+	}
+	defer lockFile.ReleaseLease()
+
+# Hierarchical Namespace
+
+Storage accounts with a hierarchical namespace, Azure Data Lake Storage Gen2, have real directories.  Setting
+Options.HierarchicalNamespace to azure.HierarchicalNamespaceEnabled, or to azure.HierarchicalNamespaceDetect to ask
+Azure when it's first needed, uses the account's DFS endpoint for:
+
+  - moves of files within the account, which are atomic renames rather than a copy and delete
+  - the azure.Location methods CreateDirectory, DeleteDirectory and Rename, which creates, deletes or atomically renames
+    a directory and everything in it
+  - the AccessControl methods of azure.File and azure.Location, which return the owner, group, permissions and POSIX
+    ACL of a file or directory
+
+These return azure.ErrHierarchicalNamespaceRequired for other accounts.  If the namespace can't be detected, they return
+the detection error, while moves are still made by copying and deleting.  Directories aren't listed as files:
+
+	err = azureLocation.CreateDirectory()
+	...
+	err = azureLocation.Rename(archivedLocation)
+	acl, err := azureFile.AccessControl()
+
 # Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
 preferring the first location found:
 
- 1. When the ENV vars VFS_AZURE_ENV_NAME, VFS_AZURE_STORAGE_ACCOUNT, VFS_AZURE_TENANT_ID, VFS_AZURE_CLIENT_ID, and
+ 1. The ENV var VFS_AZURE_CONNECTION_STRING holds a connection string.  The account, blob endpoint and either a
+    shared key or a shared access signature are taken from it.
+ 2. The ENV var VFS_AZURE_SAS_TOKEN holds an account or container shared access signature, appended to every request.
+ 3. When the ENV vars VFS_AZURE_ENV_NAME, VFS_AZURE_STORAGE_ACCOUNT, VFS_AZURE_TENANT_ID, VFS_AZURE_CLIENT_ID, and
     VFS_AZURE_CLIENT_SECRET, authentication is performed using an OAuth Token Authenticator.  This will allow access
     to containers from multiple storage accounts.
- 2. The ENV vars VFS_AZURE_STORAGE_ACCOUNT and VFS_AZURE_STORAGE_KEY, a shared key authenticator is used.  This will
+ 4. The ENV vars VFS_AZURE_STORAGE_ACCOUNT and VFS_AZURE_STORAGE_KEY, a shared key authenticator is used.  This will
     allow access to any containers owned by the designated storage account.
- 3. If none of the above are present, then an anonymous authenticator is created and only publicly accessible blobs
+ 5. If none of the above are present, then an anonymous authenticator is created and only publicly accessible blobs
     will be available
+
+Options.ContainerSASTokens holds shared access signatures by container name, for containers whose signatures were
+issued separately.  A connection string's BlobEndpoint is used for requests, so local emulators such as Azurite can be
+reached with their connection string, or with "UseDevelopmentStorage=true" for Azurite's defaults.  File and Location
+URIs keep naming <account_name>.blob.core.windows.net whichever endpoint is used:
+
+	fs = fs.WithOptions(azure.Options{
+	    ConnectionString: "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=...;" +
+	        "BlobEndpoint=http://localhost:10000/devstoreaccount1;",
+	})
 */
 package azure
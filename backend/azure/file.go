@@ -1,8 +1,11 @@
 package azure
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strings"
@@ -22,8 +25,13 @@ type File struct {
 	fileSystem *FileSystem
 	container  string
 	name       string
+	versionID  string
 	tempFile   *os.File
 	isDirty    bool
+	ifMatch    string
+	ifNotExist bool
+	accessTier azblob.AccessTierType
+	lease      *lease
 }
 
 // Close cleans up all of the backing data structures used for reading/writing files.  This includes, closing the
@@ -47,7 +55,7 @@ func (f *File) Close() error {
 
 		if f.isDirty {
 			if err := client.Upload(f, f.tempFile); err != nil {
-				return err
+				return f.leasedError(f.preconditionError(err))
 			}
 		}
 	}
@@ -77,6 +85,10 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 // Write implements the io.Writer interface.  Writes are performed against a temporary local file.  The temp file is
 // closed and flushed to Azure with f.Close() is called.
 func (f *File) Write(p []byte) (int, error) {
+	if f.versionID != "" {
+		return 0, ErrVersionReadOnly
+	}
+
 	if err := f.checkTempFile(); err != nil {
 		return 0, err
 	}
@@ -98,6 +110,17 @@ func (f *File) String() string {
 
 // Exists returns true/false if the file exists/does not exist on Azure
 func (f *File) Exists() (bool, error) {
+	if f.versionID != "" {
+		_, err := f.versionProperties()
+		if err != nil {
+			if errors.Is(err, vfs.ErrNotExist) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
 	client, err := f.fileSystem.Client()
 	if err != nil {
 		return false, err
@@ -139,19 +162,24 @@ func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 
 // CopyToFile puts the contents of the receiver (f *File) into the passed vfs.File parameter.
 func (f *File) CopyToFile(file vfs.File) error {
+	azFile, ok := file.(*File)
+	if ok && azFile.versionID != "" {
+		return ErrVersionReadOnly
+	}
+
 	// validate seek is at 0,0 before doing copy
 	if err := backend.ValidateCopySeekPosition(f); err != nil {
 		return err
 	}
 
-	azFile, ok := file.(*File)
-	if ok {
+	// StartCopyFromURL isn't subject to the target's conditions, so conditional targets are written by streaming
+	if ok && !azFile.conditional() {
 		if f.isSameAuth(azFile) {
 			client, err := f.fileSystem.Client()
 			if err != nil {
 				return err
 			}
-			return client.Copy(f, file)
+			return f.archivedError(client.Copy(f, file))
 		}
 	}
 
@@ -175,16 +203,21 @@ func (f *File) CopyToFile(file vfs.File) error {
 
 // MoveToLocation copies the receiver to the passed location.  After the copy succeeds, the original is deleted.
 func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
-	newFile, err := f.CopyToLocation(location)
+	newFile, err := location.NewFile(utils.RemoveLeadingSlash(f.Name()))
 	if err != nil {
 		return nil, err
 	}
 
-	return newFile, f.Delete()
+	return newFile, f.MoveToFile(newFile)
 }
 
-// MoveToFile copies the receiver to the specified file and deletes the original file.
+// MoveToFile copies the receiver to the specified file and deletes the original file.  In storage accounts with a
+// hierarchical namespace, files within the account are atomically renamed instead.
 func (f *File) MoveToFile(file vfs.File) error {
+	if renamed, err := f.rename(file); renamed || err != nil {
+		return err
+	}
+
 	if err := f.CopyToFile(file); err != nil {
 		return err
 	}
@@ -216,7 +249,7 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 	}
 
 	if err := client.Delete(f); err != nil {
-		return err
+		return f.leasedError(f.preconditionError(err))
 	}
 
 	if deleteAllVersions {
@@ -228,11 +261,7 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 
 // LastModified returns the last modified time as a time.Time
 func (f *File) LastModified() (*time.Time, error) {
-	client, err := f.fileSystem.Client()
-	if err != nil {
-		return nil, err
-	}
-	props, err := client.Properties(f.Location().(*Location).ContainerURL(), f.Path())
+	props, err := f.properties()
 	if err != nil {
 		return nil, err
 	}
@@ -241,11 +270,7 @@ func (f *File) LastModified() (*time.Time, error) {
 
 // Size returns the size of the blob
 func (f *File) Size() (uint64, error) {
-	client, err := f.fileSystem.Client()
-	if err != nil {
-		return 0, err
-	}
-	props, err := client.Properties(f.Location().(*Location).ContainerURL(), f.Path())
+	props, err := f.properties()
 	if err != nil {
 		return 0, err
 	}
@@ -265,6 +290,10 @@ func (f *File) Name() string {
 // Touch creates a zero-length file on the vfs.File if no File exists.  If the file exists, Touch updates the file's
 // last modified parameter.
 func (f *File) Touch() error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+
 	exists, err := f.Exists()
 	if err != nil {
 		return err
@@ -297,6 +326,64 @@ func (f *File) Touch() error {
 	return nil
 }
 
+// SignedURL returns a URL with a service SAS to download (http.MethodGet) or upload (http.MethodPut) the file, valid
+// for expiry, implementing vfs.URLSigner.  The SAS is signed with the account key, so AccountName and AccountKey must
+// be set in the file system's options.  It's only valid over https unless the blob endpoint is http.
+func (f *File) SignedURL(method string, expiry time.Duration) (string, error) {
+	sasValues := azblob.BlobSASSignatureValues{
+		Protocol:      azblob.SASProtocolHTTPS,
+		ExpiryTime:    time.Now().UTC().Add(expiry),
+		ContainerName: f.container,
+		BlobName:      utils.RemoveLeadingSlash(f.name),
+		BlobVersion:   f.versionID,
+	}
+
+	switch method {
+	case http.MethodGet:
+		sasValues.Permissions = azblob.BlobSASPermissions{Read: true}.String()
+	case http.MethodPut:
+		if f.versionID != "" {
+			return "", ErrVersionReadOnly
+		}
+		sasValues.Permissions = azblob.BlobSASPermissions{Create: true, Write: true}.String()
+	default:
+		return "", vfs.ErrSignedURLMethod
+	}
+
+	opts := f.fileSystem.options
+	if opts != nil {
+		if _, _, err := opts.connectionString(); err != nil {
+			return "", err
+		}
+	}
+	if opts == nil || opts.accountName() == "" || opts.accountKey() == "" {
+		return "", errors.New("azure signed URLs require the AccountName and AccountKey options")
+	}
+	credential, err := azblob.NewSharedKeyCredential(opts.accountName(), opts.accountKey())
+	if err != nil {
+		return "", err
+	}
+
+	// the blob URL is on the blob endpoint of the ConnectionString, if there is one, which may be http, e.g. Azurite's
+	endpoint, err := url.Parse(opts.serviceURL())
+	if err != nil {
+		return "", err
+	}
+	if endpoint.Scheme == "http" {
+		sasValues.Protocol = azblob.SASProtocolHTTPSandHTTP
+	}
+	sas, err := sasValues.NewSASQueryParameters(credential)
+	if err != nil {
+		return "", err
+	}
+	blobURL := url.URL{Path: path.Join("/", endpoint.Path, f.container, f.name)}
+	query := sas.Encode()
+	if f.versionID != "" {
+		query += "&" + url.Values{"versionid": {f.versionID}}.Encode()
+	}
+	return endpoint.Scheme + "://" + endpoint.Host + blobURL.EscapedPath() + "?" + query, nil
+}
+
 // URI returns a full Azure URI for the file
 func (f *File) URI() string {
 	return fmt.Sprintf("%s://%s%s", f.fileSystem.Scheme(), utils.EnsureTrailingSlash(f.fileSystem.Host()), path.Join(f.container, f.name))
@@ -321,6 +408,10 @@ func (f *File) checkTempFile() error {
 			f.tempFile = tf
 		} else {
 			reader, dlErr := client.Download(f)
+			if dlErr != nil {
+				return f.archivedError(dlErr)
+			}
+			reader, dlErr = f.verifiedReader(reader)
 			if dlErr != nil {
 				return dlErr
 			}
@@ -345,8 +436,26 @@ func (f *File) checkTempFile() error {
 	return nil
 }
 
+// properties returns the properties of the blob, or of the version the file refers to.
+func (f *File) properties() (*BlobProperties, error) {
+	if f.versionID != "" {
+		return f.versionProperties()
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	return client.Properties(f.Location().(*Location).ContainerURL(), f.Path())
+}
+
+// isSameAuth returns whether the target is on the same blob endpoint of the same account, authorized the same way, so
+// the source's client can copy or rename blobs onto it.  Otherwise the contents are streamed.
 func (f *File) isSameAuth(target *File) bool {
 	sourceOptions := f.fileSystem.options
 	targetOptions := target.fileSystem.options
-	return sourceOptions.AccountKey == targetOptions.AccountKey
+	return sourceOptions.accountName() == targetOptions.accountName() &&
+		sourceOptions.serviceURL() == targetOptions.serviceURL() &&
+		sourceOptions.accountKey() == targetOptions.accountKey() &&
+		sourceOptions.sasToken(f.container) == targetOptions.sasToken(target.container)
 }
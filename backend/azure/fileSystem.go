@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/c2fo/vfs/v6"
 	"github.com/c2fo/vfs/v6/backend"
@@ -26,6 +27,10 @@ const errNilFileSystemReceiver = "azure.FileSystem receiver pointer must be non-
 type FileSystem struct {
 	options *Options
 	client  Client
+
+	// hns caches whether the storage account has a hierarchical namespace, once it's detected
+	hnsMu sync.Mutex
+	hns   *bool
 }
 
 // NewFileSystem creates a new default FileSystem.  This will set the options options.AccountName and
@@ -37,7 +42,9 @@ func NewFileSystem() *FileSystem {
 // WithOptions allows the caller to override the default options
 func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
 	azureOpts, _ := opts.(Options)
+	azureOpts.parseConnection()
 	fs.options = &azureOpts
+	fs.hns = nil
 	return fs
 }
 
@@ -51,8 +58,10 @@ func (fs *FileSystem) WithClient(client Client) *FileSystem {
 func (fs *FileSystem) Client() (Client, error) {
 	if fs.client == nil {
 		client, err := NewClient(fs.options)
+		if err != nil {
+			return nil, err
+		}
 		fs.client = client
-		return fs.client, err
 	}
 	return fs.client, nil
 }
@@ -109,9 +118,10 @@ func (fs *FileSystem) Scheme() string {
 	return Scheme
 }
 
-// Host returns the host portion of the URI.  For azure this consists of <account_name>.blob.core.windows.net.
+// Host returns the host portion of the URI.  For azure this consists of <account_name>.blob.core.windows.net, with
+// the account of the ConnectionString option if there is one.
 func (fs *FileSystem) Host() string {
-	return fmt.Sprintf("%s.blob.core.windows.net", fs.options.AccountName)
+	return fmt.Sprintf("%s.blob.core.windows.net", fs.options.accountName())
 }
 
 // Retry returns the default retry function.  This is overridable via the WithOptions function.
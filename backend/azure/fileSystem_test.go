@@ -14,11 +14,26 @@ type FileSystemTestSuite struct {
 	suite.Suite
 }
 
+// baseClient implements only the Client interface, none of its optional interfaces.
+type baseClient struct {
+	Client
+}
+
 func (s *FileSystemTestSuite) TestVFSFileSystemImplementor() {
 	fs := FileSystem{}
 	s.Implements((*vfs.FileSystem)(nil), &fs, "Does not implement the vfs.FileSystem interface")
 }
 
+func (s *FileSystemTestSuite) TestClientImplementor() {
+	for _, client := range []Client{&DefaultClient{}, &MockAzureClient{}} {
+		s.Implements((*VersionLister)(nil), client)
+		s.Implements((*TierSetter)(nil), client)
+		s.Implements((*Tagger)(nil), client)
+		s.Implements((*Leaser)(nil), client)
+		s.Implements((*DFSClient)(nil), client)
+	}
+}
+
 func (s *FileSystemTestSuite) TestNewFile() {
 	fs := NewFileSystem().WithOptions(Options{AccountName: "test-container"})
 	file, err := fs.NewFile("", "")
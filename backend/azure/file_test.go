@@ -1,8 +1,11 @@
 package azure
 
 import (
+	"encoding/base64"
 	"errors"
 	"io"
+	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -388,6 +391,124 @@ func (s *FileTestSuite) TestIsSameAuth_DifferentAcctKey() {
 	s.False(sourceFile.isSameAuth(targetFile), "Files were created with different account keys so same auth should be false")
 }
 
+// copyCountingClient counts the native copies made with a MockAzureClient.
+type copyCountingClient struct {
+	*MockAzureClient
+	copies int
+}
+
+func (c *copyCountingClient) Copy(srcFile, tgtFile vfs.File) error {
+	c.copies++
+	return c.MockAzureClient.Copy(srcFile, tgtFile)
+}
+
+func (s *FileTestSuite) TestCopyToFile_DifferentAccounts() {
+	newFile := func(connectionString string, client Client) *File {
+		fs := NewFileSystem().WithOptions(Options{ConnectionString: connectionString}).WithClient(client)
+		f, err := fs.NewFile("test-container", "/foo.txt")
+		s.Require().NoError(err)
+		return f.(*File)
+	}
+	sourceClient := &copyCountingClient{MockAzureClient: &MockAzureClient{}}
+	targetClient := &copyCountingClient{MockAzureClient: &MockAzureClient{}}
+	copyToFile := func(source, target *File) error {
+		sourceClient.ExpectedResult = io.NopCloser(strings.NewReader("blah"))
+		targetClient.ExpectedResult = io.NopCloser(strings.NewReader(""))
+		return source.CopyToFile(target)
+	}
+	source := newFile("BlobEndpoint=https://src.blob.core.windows.net;SharedAccessSignature=sv=1&sig=abc", sourceClient)
+
+	// shared access signatures and connection strings leave AccountKey empty, but name different accounts
+	target := newFile("BlobEndpoint=https://dst.blob.core.windows.net;SharedAccessSignature=sv=1&sig=abc", targetClient)
+	s.False(source.isSameAuth(target))
+	s.NoError(copyToFile(source, target))
+	s.Zero(sourceClient.copies, "copies to another account are streamed")
+
+	other := NewFileSystem().WithOptions(Options{AccountName: "dst", SASToken: "sv=1&sig=def"}).WithClient(targetClient)
+	f, err := other.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+	s.False(source.isSameAuth(f.(*File)))
+	s.NoError(copyToFile(source, f.(*File)))
+	s.Zero(sourceClient.copies, "copies to another account are streamed")
+
+	// the same account and signature are copied natively
+	target = newFile("BlobEndpoint=https://src.blob.core.windows.net;SharedAccessSignature=sv=1&sig=abc", targetClient)
+	s.True(source.isSameAuth(target))
+	s.NoError(copyToFile(source, target))
+	s.Equal(1, sourceClient.copies)
+}
+
+func (s *FileTestSuite) TestSignedURL() {
+	s.Implements((*vfs.URLSigner)(nil), &File{}, "Does not implement the vfs.URLSigner interface")
+
+	fs := NewFileSystem().WithOptions(Options{
+		AccountName: "test-account",
+		AccountKey:  base64.StdEncoding.EncodeToString([]byte("secret")),
+	})
+	f, err := fs.NewFile("test-container", "/some/path/file.txt")
+	s.Require().NoError(err)
+
+	signed, err := utils.SignedURL(f, http.MethodGet, time.Hour)
+	s.Require().NoError(err)
+	u, err := url.Parse(signed)
+	s.Require().NoError(err)
+	s.Equal("test-account.blob.core.windows.net", u.Host)
+	s.Equal("/test-container/some/path/file.txt", u.Path)
+	s.Equal("r", u.Query().Get("sp"))
+	s.Equal("b", u.Query().Get("sr"))
+	s.Equal("https", u.Query().Get("spr"))
+	s.NotEmpty(u.Query().Get("sig"))
+
+	signed, err = f.(*File).SignedURL(http.MethodPut, time.Hour)
+	s.Require().NoError(err)
+	u, err = url.Parse(signed)
+	s.Require().NoError(err)
+	s.Equal("cw", u.Query().Get("sp"))
+
+	version, err := f.(*File).AtVersion("2023-04-05T06:07:08.0000000Z")
+	s.Require().NoError(err)
+	signed, err = version.SignedURL(http.MethodGet, time.Hour)
+	s.Require().NoError(err)
+	u, err = url.Parse(signed)
+	s.Require().NoError(err)
+	s.Equal("bv", u.Query().Get("sr"))
+	s.Equal("2023-04-05T06:07:08.0000000Z", u.Query().Get("versionid"))
+	_, err = version.SignedURL(http.MethodPut, time.Hour)
+	s.ErrorIs(err, ErrVersionReadOnly)
+
+	_, err = f.(*File).SignedURL(http.MethodDelete, time.Hour)
+	s.ErrorIs(err, vfs.ErrSignedURLMethod)
+
+	// http endpoints, such as Azurite's, are signed for http too
+	emulator := NewFileSystem().WithOptions(Options{ConnectionString: "UseDevelopmentStorage=true"})
+	emulated, err := emulator.NewFile("test-container", "/some/path/file.txt")
+	s.Require().NoError(err)
+	signed, err = emulated.(*File).SignedURL(http.MethodGet, time.Hour)
+	s.Require().NoError(err)
+	u, err = url.Parse(signed)
+	s.Require().NoError(err)
+	s.Equal("http", u.Scheme)
+	s.Equal("/devstoreaccount1/test-container/some/path/file.txt", u.Path)
+	s.Equal("https,http", u.Query().Get("spr"))
+
+	// names are escaped once, including ones that look escaped already
+	f, err = fs.NewFile("test-container", "/some/path/100%25 done #1.txt")
+	s.Require().NoError(err)
+	signed, err = f.(*File).SignedURL(http.MethodGet, time.Hour)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(signed,
+		"https://test-account.blob.core.windows.net/test-container/some/path/100%2525%20done%20%231.txt?"), signed)
+	u, err = url.Parse(signed)
+	s.Require().NoError(err)
+	s.Equal("/test-container/some/path/100%25 done #1.txt", u.Path)
+
+	fs = NewFileSystem().WithOptions(Options{AccountName: "test-account"})
+	f, err = fs.NewFile("test-container", "/some/path/file.txt")
+	s.Require().NoError(err)
+	_, err = f.(*File).SignedURL(http.MethodGet, time.Hour)
+	s.EqualError(err, "azure signed URLs require the AccountName and AccountKey options")
+}
+
 func TestAzureFile(t *testing.T) {
 	suite.Run(t, new(FileTestSuite))
 }
@@ -0,0 +1,215 @@
+package azure
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// HierarchicalNamespace determines whether a file system's storage account is treated as having a hierarchical
+// namespace, as Azure Data Lake Storage Gen2 accounts do.  Their directories are real, so they can be created,
+// deleted and renamed, and moves within the account are atomic renames.
+type HierarchicalNamespace string
+
+const (
+	// HierarchicalNamespaceDisabled, the default, treats the account as flat blob storage.
+	HierarchicalNamespaceDisabled HierarchicalNamespace = ""
+	// HierarchicalNamespaceEnabled treats the account as having a hierarchical namespace.
+	HierarchicalNamespaceEnabled HierarchicalNamespace = "enabled"
+	// HierarchicalNamespaceDetect asks Azure whether the account has a hierarchical namespace when it's first needed.
+	HierarchicalNamespaceDetect HierarchicalNamespace = "detect"
+)
+
+// ErrHierarchicalNamespaceRequired is returned by the directory and ACL operations of file systems whose storage
+// account doesn't have a hierarchical namespace.
+var ErrHierarchicalNamespaceRequired = errors.New("azure storage account doesn't have a hierarchical namespace")
+
+// AccessControl holds the owner, group, permissions and POSIX access control list of a file or directory in a
+// storage account with a hierarchical namespace.
+type AccessControl struct {
+	// Owner holds the object ID of the owning user
+	Owner string
+
+	// Group holds the object ID of the owning group
+	Group string
+
+	// Permissions holds the symbolic permissions, such as "rwxr-x---", with a trailing "+" if there's an extended ACL
+	Permissions string
+
+	// ACL holds the comma separated ACL entries, such as "user::rwx,group::r-x,other::---"
+	ACL string
+}
+
+// hierarchicalNamespace returns whether the storage account has a hierarchical namespace, according to the
+// HierarchicalNamespace option.  When it's HierarchicalNamespaceDetect, the account of l is asked until it answers.
+func (fs *FileSystem) hierarchicalNamespace(l vfs.Location) (bool, error) {
+	switch fs.options.HierarchicalNamespace {
+	case HierarchicalNamespaceDisabled:
+		return false, nil
+	case HierarchicalNamespaceEnabled:
+		return true, nil
+	}
+
+	fs.hnsMu.Lock()
+	defer fs.hnsMu.Unlock()
+	if fs.hns == nil {
+		client, err := fs.dfsClient()
+		if err != nil {
+			return false, err
+		}
+		hns, err := client.HierarchicalNamespace(l)
+		if err != nil {
+			return false, err
+		}
+		fs.hns = &hns
+	}
+	return *fs.hns, nil
+}
+
+// requireHierarchicalNamespace returns ErrHierarchicalNamespaceRequired if the storage account of l doesn't have a
+// hierarchical namespace.
+func (fs *FileSystem) requireHierarchicalNamespace(l vfs.Location) error {
+	hns, err := fs.hierarchicalNamespace(l)
+	if err != nil {
+		return err
+	}
+	if !hns {
+		return ErrHierarchicalNamespaceRequired
+	}
+	return nil
+}
+
+// dfsClient returns the file system's client, if it implements DFSClient.
+func (fs *FileSystem) dfsClient() (DFSClient, error) {
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	dfs, ok := client.(DFSClient)
+	if !ok {
+		return nil, notSupported(client, "DFSClient")
+	}
+	return dfs, nil
+}
+
+// rename atomically renames the file's blob to the target file, returning false without renaming it if the move has
+// to be a copy and delete instead: the account doesn't have a hierarchical namespace, the target is in another account
+// or has its own access tier, either file is a version, or the client doesn't implement DFSClient.
+func (f *File) rename(file vfs.File) (bool, error) {
+	target, ok := file.(*File)
+	if !ok || f.versionID != "" || target.versionID != "" || target.accessTier != azblob.AccessTierNone ||
+		!f.isSameAuth(target) {
+		return false, nil
+	}
+	// if the namespace can't be detected, the move is still made by copying and deleting
+	if hns, err := f.fileSystem.hierarchicalNamespace(f.Location()); err != nil || !hns {
+		return false, nil
+	}
+
+	client, err := f.fileSystem.dfsClient()
+	if errors.Is(err, ErrNotSupported) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// pending writes are uploaded before the blob is renamed
+	if err := f.Close(); err != nil {
+		return true, err
+	}
+	if err := client.Rename(f, target); err != nil {
+		return true, target.leasedError(target.preconditionError(err))
+	}
+	return true, nil
+}
+
+// AccessControl returns the owner, group, permissions and POSIX ACL of the file, whose storage account must have a
+// hierarchical namespace.
+func (f *File) AccessControl() (*AccessControl, error) {
+	if err := f.fileSystem.requireHierarchicalNamespace(f.Location()); err != nil {
+		return nil, err
+	}
+	client, err := f.fileSystem.dfsClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetAccessControl(f.Location().(*Location).ContainerURL(), f.Path())
+}
+
+// CreateDirectory creates the location's directory, and any missing parents, in a storage account with a hierarchical
+// namespace.
+func (l *Location) CreateDirectory() error {
+	if err := l.requireDirectory(); err != nil {
+		return err
+	}
+	client, err := l.fileSystem.dfsClient()
+	if err != nil {
+		return err
+	}
+	return client.CreateDirectory(l)
+}
+
+// DeleteDirectory deletes the location's directory and everything in it, in a storage account with a hierarchical
+// namespace.
+func (l *Location) DeleteDirectory() error {
+	if err := l.requireDirectory(); err != nil {
+		return err
+	}
+	client, err := l.fileSystem.dfsClient()
+	if err != nil {
+		return err
+	}
+	return client.DeleteDirectory(l)
+}
+
+// Rename atomically renames the location's directory, and everything in it, to the target location in the same storage
+// account, which must have a hierarchical namespace.  The target's parent directories are created if they're missing.
+func (l *Location) Rename(target vfs.Location) error {
+	if err := l.requireDirectory(); err != nil {
+		return err
+	}
+	tgt, ok := target.(*Location)
+	if !ok || l.fileSystem.options.serviceURL() != tgt.fileSystem.options.serviceURL() {
+		return errors.New("azure directories can only be renamed within the same storage account")
+	}
+	if tgt.path == "/" {
+		return errors.New("azure directories can't be renamed to the root of a container")
+	}
+	client, err := l.fileSystem.dfsClient()
+	if err != nil {
+		return err
+	}
+	return client.RenameDirectory(l, tgt)
+}
+
+// AccessControl returns the owner, group, permissions and POSIX ACL of the location's directory, or of the container's
+// root directory, in a storage account with a hierarchical namespace.
+func (l *Location) AccessControl() (*AccessControl, error) {
+	if err := l.fileSystem.requireHierarchicalNamespace(l); err != nil {
+		return nil, err
+	}
+	client, err := l.fileSystem.dfsClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetAccessControl(l.ContainerURL(), utils.EnsureLeadingSlash(strings.TrimSuffix(l.Path(), "/")))
+}
+
+// requireDirectory returns an error if the location is a container's root, which can't be created, deleted or
+// renamed, or if its storage account doesn't have a hierarchical namespace.
+func (l *Location) requireDirectory() error {
+	if l.path == "/" {
+		return errors.New("the root directory of an azure container can't be created, deleted or renamed")
+	}
+	return l.fileSystem.requireHierarchicalNamespace(l)
+}
+
+// isDirectory returns whether a listed blob with metadata is the placeholder of a directory in a storage account with
+// a hierarchical namespace.
+func isDirectory(metadata azblob.Metadata) bool {
+	return strings.EqualFold(metadata["hdi_isfolder"], "true")
+}
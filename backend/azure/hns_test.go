@@ -0,0 +1,195 @@
+package azure
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type HNSTestSuite struct {
+	suite.Suite
+}
+
+func (s *HNSTestSuite) TestHierarchicalNamespaceRequired() {
+	client := MockAzureClient{}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	l, err := fs.NewLocation("test-container", "/dir/")
+	s.NoError(err)
+	s.ErrorIs(l.(*Location).CreateDirectory(), ErrHierarchicalNamespaceRequired)
+	s.ErrorIs(l.(*Location).DeleteDirectory(), ErrHierarchicalNamespaceRequired)
+	_, err = l.(*Location).AccessControl()
+	s.ErrorIs(err, ErrHierarchicalNamespaceRequired)
+	target, err := fs.NewLocation("test-container", "/other/")
+	s.NoError(err)
+	s.ErrorIs(l.(*Location).Rename(target), ErrHierarchicalNamespaceRequired)
+
+	f, err := l.NewFile("foo.txt")
+	s.NoError(err)
+	_, err = f.(*File).AccessControl()
+	s.ErrorIs(err, ErrHierarchicalNamespaceRequired)
+
+	fs.WithOptions(Options{AccountName: "test-account", HierarchicalNamespace: HierarchicalNamespaceEnabled})
+	root, err := fs.NewLocation("test-container", "/")
+	s.NoError(err)
+	s.Error(root.(*Location).DeleteDirectory(), "a container's root can't be deleted")
+	s.Error(l.(*Location).Rename(root), "a directory can't be renamed to a container's root")
+	s.NoError(l.(*Location).CreateDirectory())
+	s.NoError(l.(*Location).Rename(target))
+
+	fs.WithClient(baseClient{&client})
+	s.ErrorIs(l.(*Location).CreateDirectory(), ErrNotSupported, "clients without DFS support should return ErrNotSupported")
+	_, err = f.(*File).AccessControl()
+	s.ErrorIs(err, ErrNotSupported)
+	moved, err := fs.NewFile("test-container", "/moved/foo.txt")
+	s.NoError(err)
+	client.ExpectedResult = io.NopCloser(strings.NewReader("contents"))
+	s.NoError(f.MoveToFile(moved), "clients without DFS support should move by copying and deleting")
+}
+
+func (s *HNSTestSuite) TestDetectHierarchicalNamespace() {
+	client := MockAzureClient{ExpectedResult: true}
+	detect := Options{AccountName: "test-account", HierarchicalNamespace: HierarchicalNamespaceDetect}
+	fs := NewFileSystem().WithClient(&client).WithOptions(detect)
+
+	l, err := fs.NewLocation("test-container", "/dir/")
+	s.NoError(err)
+	hns, err := fs.hierarchicalNamespace(l)
+	s.NoError(err)
+	s.True(hns)
+
+	client.ExpectedResult = false
+	hns, err = fs.hierarchicalNamespace(l)
+	s.NoError(err)
+	s.True(hns, "the detected namespace should be cached")
+
+	fs.WithOptions(detect)
+	hns, err = fs.hierarchicalNamespace(l)
+	s.NoError(err)
+	s.False(hns, "new options should detect the namespace again")
+
+	client.ExpectedResult = nil
+	client.ExpectedError = errors.New("i always error")
+	fs.WithOptions(detect)
+	_, err = fs.hierarchicalNamespace(l)
+	s.Error(err, "detection errors should be returned")
+	s.ErrorIs(l.(*Location).CreateDirectory(), client.ExpectedError, "operations requiring the namespace should fail")
+
+	f, err := l.NewFile("foo.txt")
+	s.NoError(err)
+	target, err := fs.NewFile("test-container", "/moved/foo.txt")
+	s.NoError(err)
+	renamed, err := f.(*File).rename(target)
+	s.NoError(err, "moves should fall back to copying and deleting when detection fails")
+	s.False(renamed)
+
+	client.ExpectedError = nil
+	client.ExpectedResult = true
+	hns, err = fs.hierarchicalNamespace(l)
+	s.NoError(err)
+	s.True(hns, "detection failures shouldn't be cached")
+}
+
+func (s *HNSTestSuite) TestDefaultClient() {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		query := r.URL.Query()
+		switch {
+		case query.Get("comp") == "properties" && query.Get("restype") == "account":
+			w.Header().Set("x-ms-is-hns-enabled", "true")
+		case r.Header.Get("x-ms-rename-source") != "" && r.Header.Get("If-None-Match") == "*":
+			w.Header().Set("x-ms-error-code", string(serviceCodePathAlreadyExists))
+			w.WriteHeader(http.StatusConflict)
+			return
+		case r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+			return
+		case r.Method == http.MethodDelete && query.Get("continuation") == "":
+			w.Header().Set("x-ms-continuation", "next")
+		case r.Method == http.MethodHead:
+			w.Header().Set("x-ms-owner", "owner-id")
+			w.Header().Set("x-ms-group", "group-id")
+			w.Header().Set("x-ms-permissions", "rwxr-x---+")
+			w.Header().Set("x-ms-acl", "user::rwx,group::r-x,other::---")
+		case query.Get("comp") == "list":
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`+
+				`<Blob><Name>dir/a.txt</Name><Properties /><Metadata /></Blob>`+
+				`<Blob><Name>dir/sub</Name><Properties /><Metadata><hdi_isfolder>true</hdi_isfolder></Metadata></Blob>`+
+				`<BlobPrefix><Name>dir/sub/</Name></BlobPrefix>`+
+				`</Blobs><NextMarker /></EnumerationResults>`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+	fs := NewFileSystem().WithOptions(Options{
+		ConnectionString:      "AccountName=devstoreaccount1;AccountKey=" + key + ";BlobEndpoint=" + server.URL + "/devstoreaccount1;",
+		HierarchicalNamespace: HierarchicalNamespaceDetect,
+	})
+	l, err := fs.NewLocation("bucket", "/dir/")
+	s.Require().NoError(err)
+	src, err := l.NewFile("a b.txt")
+	s.Require().NoError(err)
+	tgt, err := fs.NewFile("other", "/moved/a b.txt")
+	s.Require().NoError(err)
+
+	// the namespace is detected, then the file renamed
+	s.NoError(src.MoveToFile(tgt))
+	s.Require().Len(requests, 2)
+	s.Equal("/devstoreaccount1/bucket/", requests[0].URL.Path)
+	s.Equal(http.MethodPut, requests[1].Method)
+	s.Equal("/devstoreaccount1/other/moved/a b.txt", requests[1].URL.Path)
+	s.Equal("/bucket/dir/a%20b.txt", requests[1].Header.Get("x-ms-rename-source"))
+	s.Contains(requests[1].Header.Get("Authorization"), "SharedKey devstoreaccount1:")
+
+	requests = nil
+	err = src.MoveToFile(tgt.(*File).IfNotExist())
+	s.ErrorIs(err, vfs.ErrPreconditionFailed, "a rename onto an existing file should fail its precondition")
+	s.Len(requests, 1)
+
+	requests = nil
+	s.NoError(l.(*Location).CreateDirectory())
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1/bucket/dir", requests[0].URL.Path)
+	s.Equal("directory", requests[0].URL.Query().Get("resource"))
+
+	requests = nil
+	s.NoError(l.(*Location).DeleteDirectory())
+	s.Require().Len(requests, 2, "deletes should be continued")
+	s.Equal("true", requests[0].URL.Query().Get("recursive"))
+	s.Equal("next", requests[1].URL.Query().Get("continuation"))
+
+	requests = nil
+	target, err := fs.NewLocation("bucket", "/renamed/")
+	s.Require().NoError(err)
+	s.NoError(l.(*Location).Rename(target))
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1/bucket/renamed", requests[0].URL.Path)
+	s.Equal("/bucket/dir", requests[0].Header.Get("x-ms-rename-source"))
+
+	acl, err := src.(*File).AccessControl()
+	s.NoError(err)
+	s.Equal(&AccessControl{Owner: "owner-id", Group: "group-id", Permissions: "rwxr-x---+", ACL: "user::rwx,group::r-x,other::---"}, acl)
+	_, err = l.(*Location).AccessControl()
+	s.NoError(err)
+
+	list, err := l.List()
+	s.NoError(err)
+	s.Equal([]string{"a.txt"}, list, "directories shouldn't be listed as files")
+}
+
+func TestHNS(t *testing.T) {
+	suite.Run(t, new(HNSTestSuite))
+}
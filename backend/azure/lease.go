@@ -0,0 +1,213 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+// LeaseInfinite is the duration of a lease that never expires, so isn't renewed in the background.
+const LeaseInfinite time.Duration = -1
+
+// ErrLeased is returned when acquiring a lease on a blob, or writing or deleting it, while another holder has a lease
+// on it.
+var ErrLeased = errors.New("azure blob is leased by another holder")
+
+// lease is a lease held on a blob, shared by the File that acquired it and the Files derived from it.
+type lease struct {
+	mu sync.Mutex
+	id string
+	// renewErr is the error that stopped the background renewal
+	renewErr error
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// AcquireLease acquires a lease on the file's existing blob, returning its ID.  The duration is between 15 and 60
+// seconds, or LeaseInfinite.  Until the lease is released or broken, it's renewed in the background and the uploads,
+// deletes, Touch and native copies onto the file, and onto the Files returned by its IfMatch, IfNotExist and
+// WithAccessTier methods, pass its ID.  If another holder has a lease on the blob, an error matching ErrLeased is
+// returned, so a lease can be used as a mutex:
+//
+//	if _, err := lockFile.AcquireLease(30 * time.Second); errors.Is(err, azure.ErrLeased) {
+//		// another process holds the lock
+//	}
+//	defer lockFile.ReleaseLease()
+func (f *File) AcquireLease(duration time.Duration) (string, error) {
+	if f.versionID != "" {
+		return "", ErrVersionReadOnly
+	}
+	if f.LeaseID() != "" {
+		return "", errors.New("azure.File already holds a lease")
+	}
+	leaser, err := f.fileSystem.leaser()
+	if err != nil {
+		return "", err
+	}
+
+	id, err := leaser.AcquireLease(f, duration)
+	if err != nil {
+		return "", f.leasedError(err)
+	}
+	f.lease = &lease{id: id}
+	if duration != LeaseInfinite {
+		// renew well before the lease expires
+		f.renewInBackground(leaser, duration/2)
+	}
+	return id, nil
+}
+
+// LeaseID returns the ID of the lease the file holds, or an empty string if it doesn't hold one.
+func (f *File) LeaseID() string {
+	if f.lease == nil {
+		return ""
+	}
+	f.lease.mu.Lock()
+	defer f.lease.mu.Unlock()
+	return f.lease.id
+}
+
+// RenewLease renews the lease the file holds now, rather than waiting for the background renewal.
+func (f *File) RenewLease() error {
+	id := f.LeaseID()
+	if id == "" {
+		return errors.New("azure.File doesn't hold a lease")
+	}
+	leaser, err := f.fileSystem.leaser()
+	if err != nil {
+		return err
+	}
+	return leaser.RenewLease(f, id)
+}
+
+// ReleaseLease stops renewing the lease the file holds and releases it, so another holder can acquire one.  If a
+// background renewal failed while the lease was held, so the lease may have expired, its error is returned too.
+func (f *File) ReleaseLease() error {
+	id := f.LeaseID()
+	if id == "" {
+		return errors.New("azure.File doesn't hold a lease")
+	}
+	leaser, err := f.fileSystem.leaser()
+	if err != nil {
+		return err
+	}
+
+	renewErr := f.lease.stopRenewal()
+	if err := leaser.ReleaseLease(f, id); err != nil {
+		return errors.Join(renewErr, err)
+	}
+	f.lease.clear()
+	return renewErr
+}
+
+// BreakLease breaks the lease on the file's blob, whoever holds it, after period, or at once if it's 0.  It returns how
+// long the lease remains until it's broken.  If the file holds the lease, it's no longer renewed or passed by writes.
+func (f *File) BreakLease(period time.Duration) (time.Duration, error) {
+	if f.versionID != "" {
+		return 0, ErrVersionReadOnly
+	}
+	leaser, err := f.fileSystem.leaser()
+	if err != nil {
+		return 0, err
+	}
+
+	remaining, err := leaser.BreakLease(f, period)
+	if err != nil {
+		return 0, err
+	}
+	if f.lease != nil {
+		_ = f.lease.stopRenewal()
+		f.lease.clear()
+	}
+	return remaining, nil
+}
+
+// renewInBackground renews the file's lease every interval until it's released or broken, or a renewal fails.
+func (f *File) renewInBackground(leaser Leaser, interval time.Duration) {
+	l := f.lease
+	id, stop, done := l.id, make(chan struct{}), make(chan struct{})
+	l.stop, l.done = stop, done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := leaser.RenewLease(f, id); err != nil {
+					l.mu.Lock()
+					l.renewErr = fmt.Errorf("renewing lease on %s: %w", f.URI(), err)
+					l.mu.Unlock()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// stopRenewal stops the background renewal of the lease, if it's running, returning the error of a failed renewal.
+func (l *lease) stopRenewal() error {
+	l.mu.Lock()
+	stop, done := l.stop, l.done
+	l.stop = nil
+	l.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.renewErr
+}
+
+// clear forgets the lease once it's released or broken.
+func (l *lease) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.id = ""
+	l.renewErr = nil
+}
+
+// leaser returns the file system's client, if it implements Leaser.
+func (fs *FileSystem) leaser() (Leaser, error) {
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	leaser, ok := client.(Leaser)
+	if !ok {
+		return nil, notSupported(client, "Leaser")
+	}
+	return leaser, nil
+}
+
+// leaseConditions returns the lease access conditions of a request on file, passing the ID of the lease it holds.
+func leaseConditions(file vfs.File) azblob.LeaseAccessConditions {
+	if f, ok := file.(*File); ok {
+		return azblob.LeaseAccessConditions{LeaseID: f.LeaseID()}
+	}
+	return azblob.LeaseAccessConditions{}
+}
+
+// leasedError returns an error matching ErrLeased if err is Azure rejecting a request on the file's blob because of
+// another holder's lease, otherwise err.
+func (f *File) leasedError(err error) error {
+	var storageErr azblob.StorageError
+	if errors.As(err, &storageErr) {
+		switch storageErr.ServiceCode() {
+		case azblob.ServiceCodeLeaseAlreadyPresent, azblob.ServiceCodeLeaseIDMissing,
+			azblob.ServiceCodeLeaseIDMismatchWithBlobOperation:
+			return fmt.Errorf("%w: %s: %w", ErrLeased, f.URI(), err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package azure
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/stretchr/testify/suite"
+)
+
+type LeaseTestSuite struct {
+	suite.Suite
+}
+
+func (s *LeaseTestSuite) TestAcquireLease() {
+	client := MockAzureClient{ExpectedResult: "lease-id"}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	f, err := fs.NewFile("test-container", "/lock")
+	s.NoError(err)
+	id, err := f.(*File).AcquireLease(LeaseInfinite)
+	s.NoError(err)
+	s.Equal("lease-id", id)
+	s.Equal("lease-id", f.(*File).LeaseID())
+
+	_, err = f.(*File).AcquireLease(LeaseInfinite)
+	s.Error(err, "a file can only hold one lease")
+
+	conditional := f.(*File).IfMatch("0x8D")
+	s.Equal("lease-id", accessConditions(conditional).LeaseAccessConditions.LeaseID, "derived files should pass the lease")
+	s.Equal(azblob.ETag("0x8D"), accessConditions(conditional).ModifiedAccessConditions.IfMatch)
+
+	s.NoError(f.(*File).RenewLease())
+	s.NoError(f.(*File).ReleaseLease())
+	s.Empty(f.(*File).LeaseID())
+	s.Empty(conditional.LeaseID(), "releasing the lease should release it for derived files")
+	s.Empty(accessConditions(f).LeaseAccessConditions.LeaseID)
+	s.Error(f.(*File).ReleaseLease(), "a released lease can't be released again")
+	s.Error(f.(*File).RenewLease(), "a released lease can't be renewed")
+
+	version, err := f.(*File).AtVersion("2023-04-05T06:07:08.0000000Z")
+	s.NoError(err)
+	_, err = version.AcquireLease(LeaseInfinite)
+	s.ErrorIs(err, ErrVersionReadOnly)
+}
+
+func (s *LeaseTestSuite) TestAcquireLease_Leased() {
+	client := MockAzureClient{ExpectedError: MockStorageError{Code: azblob.ServiceCodeLeaseAlreadyPresent}}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	f, err := fs.NewFile("test-container", "/lock")
+	s.NoError(err)
+	_, err = f.(*File).AcquireLease(30 * time.Second)
+	s.ErrorIs(err, ErrLeased, "a lease held by another holder should return ErrLeased")
+	s.Empty(f.(*File).LeaseID())
+
+	client.ExpectedError = MockStorageError{Code: azblob.ServiceCodeLeaseIDMissing}
+	s.ErrorIs(f.Delete(), ErrLeased, "deleting a blob leased by another holder should return ErrLeased")
+
+	client.ExpectedError = errors.New("i always error")
+	_, err = f.(*File).AcquireLease(30 * time.Second)
+	s.Error(err)
+	s.NotErrorIs(err, ErrLeased, "other errors should be returned as is")
+}
+
+func (s *LeaseTestSuite) TestBreakLease() {
+	client := MockAzureClient{ExpectedResult: "lease-id"}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	f, err := fs.NewFile("test-container", "/lock")
+	s.NoError(err)
+	_, err = f.(*File).AcquireLease(LeaseInfinite)
+	s.NoError(err)
+	_, err = f.(*File).BreakLease(0)
+	s.NoError(err)
+	s.Empty(f.(*File).LeaseID(), "a broken lease should no longer be held")
+
+	client.ExpectedError = errors.New("i always error")
+	_, err = f.(*File).BreakLease(0)
+	s.Error(err, "break errors should be returned")
+
+	fs.WithClient(baseClient{&client})
+	_, err = f.(*File).AcquireLease(LeaseInfinite)
+	s.ErrorIs(err, ErrNotSupported, "clients that don't lease blobs should return ErrNotSupported")
+	_, err = f.(*File).BreakLease(0)
+	s.ErrorIs(err, ErrNotSupported)
+}
+
+func (s *LeaseTestSuite) TestRenewInBackground() {
+	var (
+		mu       sync.Mutex
+		renewals int
+		failing  bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Header.Get("x-ms-lease-action") {
+		case "renew":
+			s.Equal("lease-id", r.Header.Get("x-ms-lease-id"))
+			renewals++
+			if failing {
+				w.Header().Set("x-ms-error-code", string(azblob.ServiceCodeLeaseIDMismatchWithLeaseOperation))
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		case "release":
+			s.Equal("lease-id", r.Header.Get("x-ms-lease-id"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+	fs := NewFileSystem().WithOptions(Options{
+		ConnectionString: "AccountName=devstoreaccount1;AccountKey=" + key + ";BlobEndpoint=" + server.URL + "/devstoreaccount1;",
+	})
+	client, err := fs.leaser()
+	s.Require().NoError(err)
+	f, err := fs.NewFile("bucket", "/lock")
+	s.Require().NoError(err)
+
+	renewed := func(n int) func() bool {
+		return func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return renewals >= n
+		}
+	}
+
+	f.(*File).lease = &lease{id: "lease-id"}
+	f.(*File).renewInBackground(client, 10*time.Millisecond)
+	s.Eventually(renewed(2), time.Second, 5*time.Millisecond, "the lease should be renewed in the background")
+	s.NoError(f.(*File).ReleaseLease())
+	s.Empty(f.(*File).LeaseID())
+
+	// a failed renewal is returned when the lease is released
+	mu.Lock()
+	failing = true
+	renewals = 0
+	mu.Unlock()
+	f.(*File).lease = &lease{id: "lease-id"}
+	f.(*File).renewInBackground(client, 10*time.Millisecond)
+	s.Eventually(renewed(1), time.Second, 5*time.Millisecond)
+	err = f.(*File).ReleaseLease()
+	s.ErrorContains(err, "renewing lease on")
+	s.Empty(f.(*File).LeaseID(), "the lease should still be released")
+}
+
+func TestLease(t *testing.T) {
+	suite.Run(t, new(LeaseTestSuite))
+}
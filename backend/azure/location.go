@@ -199,8 +199,12 @@ func (l *Location) URI() string {
 		utils.EnsureTrailingSlash(path.Join(l.container, l.path)))
 }
 
-// ContainerURL returns the URL for the Azure Blob Storage container.
+// ContainerURL returns the URL for the Azure Blob Storage container, on the blob endpoint of the ConnectionString
+// option if there is one, with the container's shared access signature as its query if one is configured.
 func (l *Location) ContainerURL() string {
-	return fmt.Sprintf("%s://%s%s", l.fileSystem.Scheme(), utils.EnsureTrailingSlash(l.fileSystem.Host()),
-		utils.EnsureTrailingSlash(l.container))
+	containerURL := utils.EnsureTrailingSlash(l.fileSystem.options.serviceURL()) + utils.EnsureTrailingSlash(l.container)
+	if sas := l.fileSystem.options.sasToken(l.container); sas != "" {
+		containerURL += "?" + sas
+	}
+	return containerURL
 }
@@ -3,13 +3,14 @@ package azure
 import (
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/Azure/azure-storage-blob-go/azblob"
 
 	"github.com/c2fo/vfs/v6"
 )
 
-// MockAzureClient is a mock implementation of azure.Client.
+// MockAzureClient is a mock implementation of azure.Client and its optional interfaces.
 type MockAzureClient struct {
 	PropertiesError  error
 	PropertiesResult *BlobProperties
@@ -66,13 +67,111 @@ func (a *MockAzureClient) DeleteAllVersions(file vfs.File) error {
 	return a.ExpectedError
 }
 
+// ListVersions returns the value of ExpectedResult if it exists, otherwise it returns ExpectedError.
+func (a *MockAzureClient) ListVersions(file vfs.File) ([]BlobVersion, error) {
+	if a.ExpectedResult != nil {
+		return a.ExpectedResult.([]BlobVersion), nil
+	}
+	return nil, a.ExpectedError
+}
+
+// SetTier returns the value of ExpectedError
+func (a *MockAzureClient) SetTier(file vfs.File, tier azblob.AccessTierType, priority azblob.RehydratePriorityType) error {
+	return a.ExpectedError
+}
+
+// GetTags returns the value of ExpectedResult if it exists, otherwise it returns ExpectedError.
+func (a *MockAzureClient) GetTags(file vfs.File) (map[string]string, error) {
+	if a.ExpectedResult != nil {
+		return a.ExpectedResult.(map[string]string), nil
+	}
+	return nil, a.ExpectedError
+}
+
+// SetTags returns the value of ExpectedError
+func (a *MockAzureClient) SetTags(file vfs.File, tags map[string]string) error {
+	return a.ExpectedError
+}
+
+// FindBlobsByTags returns the value of ExpectedResult if it exists, otherwise it returns ExpectedError.
+func (a *MockAzureClient) FindBlobsByTags(l vfs.Location, where string) ([]string, error) {
+	if a.ExpectedResult != nil {
+		return a.ExpectedResult.([]string), nil
+	}
+	return nil, a.ExpectedError
+}
+
+// AcquireLease returns the value of ExpectedResult if it exists, otherwise it returns ExpectedError.
+func (a *MockAzureClient) AcquireLease(file vfs.File, duration time.Duration) (string, error) {
+	if a.ExpectedResult != nil {
+		return a.ExpectedResult.(string), nil
+	}
+	return "", a.ExpectedError
+}
+
+// RenewLease returns the value of ExpectedError
+func (a *MockAzureClient) RenewLease(file vfs.File, leaseID string) error {
+	return a.ExpectedError
+}
+
+// ReleaseLease returns the value of ExpectedError
+func (a *MockAzureClient) ReleaseLease(file vfs.File, leaseID string) error {
+	return a.ExpectedError
+}
+
+// BreakLease returns the value of ExpectedError
+func (a *MockAzureClient) BreakLease(file vfs.File, breakPeriod time.Duration) (time.Duration, error) {
+	return 0, a.ExpectedError
+}
+
+// HierarchicalNamespace returns the value of ExpectedResult if it's a bool, otherwise it returns ExpectedError.
+func (a *MockAzureClient) HierarchicalNamespace(l vfs.Location) (bool, error) {
+	if hns, ok := a.ExpectedResult.(bool); ok {
+		return hns, nil
+	}
+	return false, a.ExpectedError
+}
+
+// Rename returns the value of ExpectedError
+func (a *MockAzureClient) Rename(srcFile, tgtFile vfs.File) error {
+	return a.ExpectedError
+}
+
+// RenameDirectory returns the value of ExpectedError
+func (a *MockAzureClient) RenameDirectory(src, tgt vfs.Location) error {
+	return a.ExpectedError
+}
+
+// CreateDirectory returns the value of ExpectedError
+func (a *MockAzureClient) CreateDirectory(l vfs.Location) error {
+	return a.ExpectedError
+}
+
+// DeleteDirectory returns the value of ExpectedError
+func (a *MockAzureClient) DeleteDirectory(l vfs.Location) error {
+	return a.ExpectedError
+}
+
+// GetAccessControl returns the value of ExpectedResult if it exists, otherwise it returns ExpectedError.
+func (a *MockAzureClient) GetAccessControl(containerURI, path string) (*AccessControl, error) {
+	if a.ExpectedResult != nil {
+		return a.ExpectedResult.(*AccessControl), nil
+	}
+	return nil, a.ExpectedError
+}
+
 // MockStorageError is a mock for the azblob.StorageError interface
 type MockStorageError struct {
 	azblob.ResponseError
+	// Code is the service code of the error, "BlobNotFound" if empty
+	Code azblob.ServiceCodeType
 }
 
-// ServiceCode always returns "BlobNotFound" to simulate the not found condition
+// ServiceCode returns Code, or "BlobNotFound" to simulate the not found condition if Code is empty
 func (mse MockStorageError) ServiceCode() azblob.ServiceCodeType {
+	if mse.Code != "" {
+		return mse.Code
+	}
 	return "BlobNotFound"
 }
 
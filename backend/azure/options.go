@@ -1,7 +1,9 @@
 package azure
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/c2fo/vfs/v6"
 
@@ -34,13 +36,43 @@ type Options struct {
 	// based authentication.
 	AzureEnvName string
 
+	// ConnectionString holds an Azure Storage connection string, with an AccountKey or a SharedAccessSignature, or
+	// "UseDevelopmentStorage=true" for Azurite.  When set, the account, credentials and blob endpoint are taken from it
+	// rather than from the other fields.
+	ConnectionString string
+
+	// SASToken holds an account or container shared access signature, e.g. "sv=2021-08-06&ss=b&srt=co&sp=rl&sig=...",
+	// appended to the URL of every request instead of authenticating with the other credentials.
+	SASToken string
+
+	// ContainerSASTokens holds container shared access signatures by container name, used for requests to their
+	// container instead of SASToken.
+	ContainerSASTokens map[string]string
+
 	// RetryFunc holds the retry function
 	RetryFunc vfs.Retry
 
 	// Buffer Size In Bytes Used with utils.TouchCopyBuffered
 	FileBufferSize int
 
+	// ChecksumAlgorithm enables integrity checks when set to vfs.ChecksumMD5.  Uploads send the Content-MD5 of their
+	// contents, which Azure stores with the blob, and downloads verify the blob's contents against its Content-MD5.
+	ChecksumAlgorithm vfs.ChecksumAlgorithm
+
+	// AccessTier holds the tier, such as azblob.AccessTierCool, AccessTierCold or azblob.AccessTierArchive, that
+	// uploads and native copies write blobs in.  The account's default tier is used if it's empty.
+	AccessTier azblob.AccessTierType
+
+	// HierarchicalNamespace, HierarchicalNamespaceEnabled or HierarchicalNamespaceDetect, treats the storage account as
+	// an Azure Data Lake Storage Gen2 account, whose directories can be created, deleted and renamed with the account's
+	// DFS endpoint, and whose moves within the account are atomic renames.
+	HierarchicalNamespace HierarchicalNamespace
+
 	tokenCredentialFactory TokenCredentialFactory
+
+	// connection holds the parsed ConnectionString and connectionErr the error parsing it, set by parseConnection
+	connection    *connectionString
+	connectionErr error
 }
 
 // NewOptions creates a new Options struct by populating values from environment variables.
@@ -52,30 +84,59 @@ type Options struct {
 //	  *VFS_AZURE_CLIENT_ID
 //	  *VFS_AZURE_CLIENT_SECRET
 //	  *VFS_AZURE_ENV_NAME
+//	  *VFS_AZURE_CONNECTION_STRING
+//	  *VFS_AZURE_SAS_TOKEN
+//	  *VFS_AZURE_HIERARCHICAL_NAMESPACE
 func NewOptions() *Options {
-	return &Options{
+	opts := &Options{
 		AccountName:            os.Getenv("VFS_AZURE_STORAGE_ACCOUNT"),
 		AccountKey:             os.Getenv("VFS_AZURE_STORAGE_ACCESS_KEY"),
 		TenantID:               os.Getenv("VFS_AZURE_TENANT_ID"),
 		ClientID:               os.Getenv("VFS_AZURE_CLIENT_ID"),
 		ClientSecret:           os.Getenv("VFS_AZURE_CLIENT_SECRET"),
 		AzureEnvName:           os.Getenv("VFS_AZURE_ENV_NAME"),
+		ConnectionString:       os.Getenv("VFS_AZURE_CONNECTION_STRING"),
+		SASToken:               os.Getenv("VFS_AZURE_SAS_TOKEN"),
+		HierarchicalNamespace:  HierarchicalNamespace(os.Getenv("VFS_AZURE_HIERARCHICAL_NAMESPACE")),
 		tokenCredentialFactory: &DefaultTokenCredentialFactory{},
 	}
+	opts.parseConnection()
+	return opts
 }
 
 // Credential returns an azblob.Credential struct based on how options are configured.  Options are checked
 // and evaluated in the following order:
-//  1. If TenantID, ClientID, and ClientSecret are non-empty, return azblob.TokenCredential.  This form of authentication
+//  1. If ConnectionString is non-empty, return azblob.SharedKeyCredential for its AccountKey, or an anonymous
+//     credential if it has a SharedAccessSignature instead, which is appended to request URLs.  An error is returned
+//     if it can't be parsed.
+//  2. If SASToken or ContainerSASTokens are non-empty, return an anonymous credential.  The shared access signatures
+//     are appended to request URLs and grant the access they were issued with.
+//  3. If TenantID, ClientID, and ClientSecret are non-empty, return azblob.TokenCredential.  This form of authentication
 //     is used with service accounts and can be used to access containers across multiple storage accounts.
-//  2. If AccountName, and AccountKey are non-empty, return azblob.SharedKeyCredential.  This form or authentication
+//  4. If AccountName, and AccountKey are non-empty, return azblob.SharedKeyCredential.  This form or authentication
 //     is used with storage accounts and only provides access to a single storage account.
-//  3. Returns an anonymous credential.  This allows access only to public blobs.
+//  5. Returns an anonymous credential.  This allows access only to public blobs.
 func (o *Options) Credential() (azblob.Credential, error) {
 	if o.tokenCredentialFactory == nil {
 		o.tokenCredentialFactory = &DefaultTokenCredentialFactory{}
 	}
 
+	// Check to see if we have a connection string
+	if cs, ok, err := o.connectionString(); ok {
+		if err != nil {
+			return nil, err
+		}
+		if cs.sasToken != "" {
+			return azblob.NewAnonymousCredential(), nil
+		}
+		return azblob.NewSharedKeyCredential(cs.accountName, cs.accountKey)
+	}
+
+	// Check to see if we have shared access signatures
+	if o.SASToken != "" || len(o.ContainerSASTokens) > 0 {
+		return azblob.NewAnonymousCredential(), nil
+	}
+
 	// Check to see if we have service account credentials
 	if o.TenantID != "" && o.ClientID != "" && o.ClientSecret != "" {
 		return o.tokenCredentialFactory.New(o.TenantID, o.ClientID, o.ClientSecret, o.AzureEnvName)
@@ -89,3 +150,70 @@ func (o *Options) Credential() (azblob.Credential, error) {
 	// 3. Return an anonymous credential
 	return azblob.NewAnonymousCredential(), nil
 }
+
+// parseConnection parses the ConnectionString once, when the options are given to a FileSystem, rather than each time
+// it's used.  An error parsing it is returned by Credential, so building a client fails.
+func (o *Options) parseConnection() {
+	o.connection, o.connectionErr = nil, nil
+	if o.ConnectionString == "" {
+		return
+	}
+	cs, err := parseConnectionString(o.ConnectionString)
+	if err != nil {
+		o.connectionErr = err
+		return
+	}
+	o.connection = &cs
+}
+
+// connectionString returns the parsed ConnectionString, and false if there is none.  Options given to a FileSystem are
+// parsed by WithOptions, others, such as literals passed to NewClient, are parsed here.  If it can't be parsed, the
+// error is returned with true, and the accessors below return empty values rather than those of the other fields.
+func (o *Options) connectionString() (connectionString, bool, error) {
+	switch {
+	case o.ConnectionString == "":
+		return connectionString{}, false, nil
+	case o.connection != nil:
+		return *o.connection, true, nil
+	case o.connectionErr != nil:
+		return connectionString{}, true, o.connectionErr
+	}
+	cs, err := parseConnectionString(o.ConnectionString)
+	return cs, true, err
+}
+
+// accountName returns the account of the ConnectionString, or AccountName.
+func (o *Options) accountName() string {
+	if cs, ok, _ := o.connectionString(); ok {
+		return cs.accountName
+	}
+	return o.AccountName
+}
+
+// accountKey returns the account key of the ConnectionString, or AccountKey.
+func (o *Options) accountKey() string {
+	if cs, ok, _ := o.connectionString(); ok {
+		return cs.accountKey
+	}
+	return o.AccountKey
+}
+
+// serviceURL returns the URL of the blob service, the blob endpoint of the ConnectionString or
+// https://<account_name>.blob.core.windows.net.
+func (o *Options) serviceURL() string {
+	if cs, ok, _ := o.connectionString(); ok {
+		return cs.blobEndpoint
+	}
+	return fmt.Sprintf("%s://%s.blob.core.windows.net", Scheme, o.AccountName)
+}
+
+// sasToken returns the shared access signature of requests to container, or an empty string if there is none.
+func (o *Options) sasToken(container string) string {
+	if cs, ok, _ := o.connectionString(); ok {
+		return cs.sasToken
+	}
+	if token, ok := o.ContainerSASTokens[container]; ok {
+		return strings.TrimPrefix(token, "?")
+	}
+	return strings.TrimPrefix(o.SASToken, "?")
+}
@@ -2,8 +2,12 @@ package azure
 
 import (
 	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-storage-blob-go/azblob"
 	"github.com/stretchr/testify/suite"
@@ -19,11 +23,106 @@ func (s *OptionsTestSuite) TearDownTest() {
 	s.NoError(os.Unsetenv("VFS_AZURE_CLIENT_SECRET"))
 	s.NoError(os.Unsetenv("VFS_AZURE_STORAGE_ACCOUNT"))
 	s.NoError(os.Unsetenv("VFS_AZURE_STORAGE_ACCESS_KEY"))
+	s.NoError(os.Unsetenv("VFS_AZURE_CONNECTION_STRING"))
+	s.NoError(os.Unsetenv("VFS_AZURE_SAS_TOKEN"))
 }
 
 func (s *OptionsTestSuite) TestNewOptions() {
 	o := NewOptions()
 	s.NotNil(o, "when NewOptions returns an error we expect to get a nill options struct")
+
+	s.NoError(os.Setenv("VFS_AZURE_CONNECTION_STRING", "UseDevelopmentStorage=true"))
+	s.NoError(os.Setenv("VFS_AZURE_SAS_TOKEN", "sv=2021-08-06&sig=abc"))
+	o = NewOptions()
+	s.Equal("UseDevelopmentStorage=true", o.ConnectionString)
+	s.Equal("sv=2021-08-06&sig=abc", o.SASToken)
+}
+
+func (s *OptionsTestSuite) TestParseConnectionString() {
+	tests := []struct {
+		name     string
+		s        string
+		expected connectionString
+		wantErr  string
+	}{
+		{
+			name: "account key",
+			s:    "DefaultEndpointsProtocol=https;AccountName=myaccount;AccountKey=a2V5;EndpointSuffix=core.windows.net",
+			expected: connectionString{
+				accountName:  "myaccount",
+				accountKey:   "a2V5",
+				blobEndpoint: "https://myaccount.blob.core.windows.net",
+			},
+		},
+		{
+			name: "sovereign cloud",
+			s:    "AccountName=myaccount;AccountKey=a2V5;EndpointSuffix=core.chinacloudapi.cn;",
+			expected: connectionString{
+				accountName:  "myaccount",
+				accountKey:   "a2V5",
+				blobEndpoint: "https://myaccount.blob.core.chinacloudapi.cn",
+			},
+		},
+		{
+			name: "shared access signature",
+			s:    "BlobEndpoint=https://myaccount.blob.core.windows.net/;SharedAccessSignature=?sv=2021-08-06&sp=rl&sig=c2ln",
+			expected: connectionString{
+				accountName:  "myaccount",
+				sasToken:     "sv=2021-08-06&sp=rl&sig=c2ln",
+				blobEndpoint: "https://myaccount.blob.core.windows.net",
+			},
+		},
+		{
+			name: "azurite",
+			s: "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=a2V5;" +
+				"BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;",
+			expected: connectionString{
+				accountName:  "devstoreaccount1",
+				accountKey:   "a2V5",
+				blobEndpoint: "http://127.0.0.1:10000/devstoreaccount1",
+			},
+		},
+		{
+			name: "azurite shared access signature",
+			s:    "BlobEndpoint=http://localhost:10000/devstoreaccount1;SharedAccessSignature=sv=2021-08-06&sig=c2ln",
+			expected: connectionString{
+				accountName:  "devstoreaccount1",
+				sasToken:     "sv=2021-08-06&sig=c2ln",
+				blobEndpoint: "http://localhost:10000/devstoreaccount1",
+			},
+		},
+		{
+			name: "development storage",
+			s:    "UseDevelopmentStorage=true",
+			expected: connectionString{
+				accountName:  devStoreAccountName,
+				accountKey:   devStoreAccountKey,
+				blobEndpoint: devStoreBlobEndpoint,
+			},
+		},
+		{
+			name:    "not key value",
+			s:       "AccountName=myaccount;AccountKey",
+			wantErr: `azure connection string setting "AccountKey" isn't a key=value pair`,
+		},
+		{name: "no account", s: "AccountKey=a2V5", wantErr: "azure connection string requires an AccountName or a BlobEndpoint"},
+		{
+			name:    "no credentials",
+			s:       "AccountName=myaccount",
+			wantErr: "azure connection string requires an AccountKey or a SharedAccessSignature",
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			cs, err := parseConnectionString(tt.s)
+			if tt.wantErr != "" {
+				s.EqualError(err, tt.wantErr)
+				return
+			}
+			s.NoError(err)
+			s.Equal(tt.expected, cs)
+		})
+	}
 }
 
 func (s *OptionsTestSuite) TestCredentials_ServiceAccount() {
@@ -72,6 +171,173 @@ func (s *OptionsTestSuite) TestCredentials_Anon() {
 	s.False(ok)
 }
 
+func (s *OptionsTestSuite) TestCredentials_ConnectionString() {
+	options := Options{
+		AccountName:      "ignored",
+		ConnectionString: "AccountName=foo;AccountKey=" + base64.StdEncoding.EncodeToString([]byte("bar")),
+	}
+	credential, err := options.Credential()
+	s.NoError(err)
+	sharedKey, ok := credential.(*azblob.SharedKeyCredential)
+	s.Require().True(ok, "credential type should be SharedKeyCredential")
+	s.Equal("foo", sharedKey.AccountName())
+
+	options = Options{ConnectionString: "BlobEndpoint=https://foo.blob.core.windows.net;SharedAccessSignature=sv=1&sig=abc"}
+	credential, err = options.Credential()
+	s.NoError(err)
+	_, ok = credential.(*azblob.SharedKeyCredential)
+	s.False(ok, "shared access signatures are sent with an anonymous credential")
+
+	options = Options{ConnectionString: "AccountName=foo"}
+	_, err = options.Credential()
+	s.EqualError(err, "azure connection string requires an AccountKey or a SharedAccessSignature")
+
+	// the connection string is parsed once, when the file system is built, and its error returned building the client
+	fs := NewFileSystem().WithOptions(Options{AccountName: "fallback", ConnectionString: "AccountName"})
+	s.Nil(fs.options.connection)
+	_, err = fs.Client()
+	s.EqualError(err, `azure connection string setting "AccountName" isn't a key=value pair`)
+	_, err = fs.Client()
+	s.Error(err, "a failed client isn't cached")
+	s.Empty(fs.options.serviceURL(), "an invalid connection string doesn't fall back to the default endpoint")
+	file, err := fs.NewFile("bucket", "/file.txt")
+	s.Require().NoError(err)
+	_, err = file.(*File).SignedURL(http.MethodGet, time.Hour)
+	s.EqualError(err, `azure connection string setting "AccountName" isn't a key=value pair`)
+
+	fs = NewFileSystem().WithOptions(Options{ConnectionString: "UseDevelopmentStorage=true"})
+	s.Require().NotNil(fs.options.connection)
+	s.Equal(devStoreBlobEndpoint, fs.options.serviceURL())
+
+	// options that weren't given to WithOptions are parsed when they're used
+	options = Options{AccountName: "ignored", ConnectionString: "UseDevelopmentStorage=true"}
+	s.Equal(devStoreBlobEndpoint, options.serviceURL())
+	s.Equal(devStoreAccountName, options.accountName())
+	s.Equal(devStoreAccountKey, options.accountKey())
+	fs = &FileSystem{options: &options}
+	l, err := fs.NewLocation("bucket", "/")
+	s.Require().NoError(err)
+	s.Equal(devStoreBlobEndpoint+"/bucket/", l.(*Location).ContainerURL())
+}
+
+func (s *OptionsTestSuite) TestCredentials_SASToken() {
+	options := Options{
+		AccountName:            "foo",
+		AccountKey:             base64.StdEncoding.EncodeToString([]byte("bar")),
+		TenantID:               "foo",
+		ClientID:               "foo",
+		ClientSecret:           "foo",
+		SASToken:               "sv=2021-08-06&sig=abc",
+		tokenCredentialFactory: &MockTokenCredentialFactory{},
+	}
+	credential, err := options.Credential()
+	s.NoError(err)
+	_, ok := credential.(azblob.TokenCredential)
+	s.False(ok, "a shared access signature is used instead of the other credentials")
+	_, ok = credential.(*azblob.SharedKeyCredential)
+	s.False(ok, "a shared access signature is used instead of the other credentials")
+}
+
+func (s *OptionsTestSuite) TestContainerURL() {
+	tests := []struct {
+		name     string
+		options  Options
+		expected string
+	}{
+		{name: "account", options: Options{AccountName: "foo"}, expected: "https://foo.blob.core.windows.net/bucket/"},
+		{
+			name:     "account sas",
+			options:  Options{AccountName: "foo", SASToken: "?sv=1&sig=abc"},
+			expected: "https://foo.blob.core.windows.net/bucket/?sv=1&sig=abc",
+		},
+		{
+			name: "container sas",
+			options: Options{
+				AccountName:        "foo",
+				SASToken:           "sv=1&sig=abc",
+				ContainerSASTokens: map[string]string{"bucket": "sv=1&sr=c&sig=def"},
+			},
+			expected: "https://foo.blob.core.windows.net/bucket/?sv=1&sr=c&sig=def",
+		},
+		{
+			name:     "connection string",
+			options:  Options{AccountName: "ignored", ConnectionString: "UseDevelopmentStorage=true"},
+			expected: "http://127.0.0.1:10000/devstoreaccount1/bucket/",
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			fs := NewFileSystem().WithOptions(tt.options)
+			location, err := fs.NewLocation("bucket", "/")
+			s.Require().NoError(err)
+			s.Equal(tt.expected, location.(*Location).ContainerURL())
+		})
+	}
+
+	// URIs keep naming the account, so they identify the same blobs whichever way they're accessed
+	fs := NewFileSystem().WithOptions(Options{ConnectionString: "UseDevelopmentStorage=true"})
+	file, err := fs.NewFile("bucket", "/path/file.txt")
+	s.Require().NoError(err)
+	s.Equal("https://devstoreaccount1.blob.core.windows.net/bucket/path/file.txt", file.URI())
+}
+
+func (s *OptionsTestSuite) TestLocalEndpoint() {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Length", "5")
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// an Azurite connection string, authenticating with the account key
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+	fs := NewFileSystem().WithOptions(Options{
+		ConnectionString: "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=" + key +
+			";BlobEndpoint=" + server.URL + "/devstoreaccount1;",
+	})
+	file, err := fs.NewFile("bucket", "/path/file.txt")
+	s.Require().NoError(err)
+	exists, err := file.Exists()
+	s.NoError(err)
+	s.True(exists)
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1/bucket/path/file.txt", requests[0].URL.Path)
+	s.True(strings.HasPrefix(requests[0].Header.Get("Authorization"), "SharedKey devstoreaccount1:"))
+
+	// a container shared access signature, sent in the query instead
+	requests = nil
+	fs = NewFileSystem().WithOptions(Options{
+		ConnectionString: "BlobEndpoint=" + server.URL + "/devstoreaccount1;SharedAccessSignature=sv=2021-08-06&sr=c&sig=c2ln",
+	})
+	file, err = fs.NewFile("bucket", "/path/file.txt")
+	s.Require().NoError(err)
+	exists, err = file.Exists()
+	s.NoError(err)
+	s.True(exists)
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1/bucket/path/file.txt", requests[0].URL.Path)
+	s.Equal("c2ln", requests[0].URL.Query().Get("sig"))
+	s.Empty(requests[0].Header.Get("Authorization"))
+}
+
+func (s *OptionsTestSuite) TestCopySourceURL() {
+	fs := NewFileSystem().WithOptions(Options{AccountName: "src", ContainerSASTokens: map[string]string{"in": "sv=1&sig=abc"}})
+	file, err := fs.NewFile("in", "/dir/a %20b.txt")
+	s.Require().NoError(err)
+	srcURL, err := copySourceURL(file)
+	s.NoError(err)
+	s.Equal("https://src.blob.core.windows.net/in/dir/a%20%2520b.txt?sv=1&sig=abc", srcURL.String())
+
+	version, err := file.(*File).AtVersion("2024-01-01T00:00:00.0000000Z")
+	s.Require().NoError(err)
+	srcURL, err = copySourceURL(version)
+	s.NoError(err)
+	s.Equal("https://src.blob.core.windows.net/in/dir/a%20%2520b.txt?sv=1&sig=abc&versionid=2024-01-01T00%3A00%3A00.0000000Z",
+		srcURL.String())
+}
+
 func TestOptions(t *testing.T) {
 	suite.Run(t, new(OptionsTestSuite))
 }
@@ -0,0 +1,89 @@
+package azure
+
+import (
+	"errors"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+// ETag returns the ETag of the file's blob, to pass to IfMatch.
+func (f *File) ETag() (string, error) {
+	props, err := f.properties()
+	if err != nil {
+		return "", err
+	}
+	return props.ETag, nil
+}
+
+// IfMatch returns a File for the same blob whose writes and deletes only succeed if the blob's ETag is still eTag.
+// Otherwise a *vfs.PreconditionFailedError is returned.
+func (f *File) IfMatch(eTag string) *File {
+	file := f.withConditions()
+	file.ifMatch = eTag
+	file.ifNotExist = false
+	return file
+}
+
+// IfNotExist returns a File for the same blob whose writes only succeed if the blob doesn't exist.  Otherwise a
+// *vfs.PreconditionFailedError is returned.
+func (f *File) IfNotExist() *File {
+	file := f.withConditions()
+	file.ifNotExist = true
+	file.ifMatch = ""
+	return file
+}
+
+// withConditions returns a copy of the file, without its pending reads and writes, keeping its conditions, access
+// tier and lease.
+func (f *File) withConditions() *File {
+	return &File{
+		fileSystem: f.fileSystem,
+		container:  f.container,
+		name:       f.name,
+		versionID:  f.versionID,
+		ifMatch:    f.ifMatch,
+		ifNotExist: f.ifNotExist,
+		accessTier: f.accessTier,
+		lease:      f.lease,
+	}
+}
+
+// conditional returns whether the file's writes are subject to conditions.
+func (f *File) conditional() bool {
+	return f.ifMatch != "" || f.ifNotExist
+}
+
+// accessConditions returns the access conditions of a write or delete of file, including the lease it holds.
+func accessConditions(file vfs.File) azblob.BlobAccessConditions {
+	f, ok := file.(*File)
+	if !ok {
+		return azblob.BlobAccessConditions{}
+	}
+
+	conditions := azblob.BlobAccessConditions{LeaseAccessConditions: leaseConditions(f)}
+	if f.ifMatch != "" {
+		conditions.ModifiedAccessConditions.IfMatch = azblob.ETag(f.ifMatch)
+	}
+	if f.ifNotExist {
+		conditions.ModifiedAccessConditions.IfNoneMatch = azblob.ETagAny
+	}
+	return conditions
+}
+
+// preconditionError returns a *vfs.PreconditionFailedError if err is Azure rejecting a request because of the file's
+// conditions, otherwise err.
+func (f *File) preconditionError(err error) error {
+	if !f.conditional() {
+		return err
+	}
+	var storageErr azblob.StorageError
+	if errors.As(err, &storageErr) {
+		switch storageErr.ServiceCode() {
+		case azblob.ServiceCodeConditionNotMet, azblob.ServiceCodeBlobAlreadyExists, serviceCodePathAlreadyExists:
+			return &vfs.PreconditionFailedError{URI: f.URI(), Err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type PreconditionTestSuite struct {
+	suite.Suite
+}
+
+func (s *PreconditionTestSuite) TestETag() {
+	client := MockAzureClient{PropertiesResult: &BlobProperties{ETag: `"0x8D9"`}}
+	fs := NewFileSystem().WithClient(&client)
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	eTag, err := f.(*File).ETag()
+	s.NoError(err)
+	s.Equal(`"0x8D9"`, eTag)
+}
+
+func (s *PreconditionTestSuite) TestAccessConditions() {
+	fs := NewFileSystem().WithClient(&MockAzureClient{})
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	s.Equal(azblob.BlobAccessConditions{}, accessConditions(f))
+
+	conditions := accessConditions(f.(*File).IfMatch(`"0x8D9"`))
+	s.Equal(azblob.ETag(`"0x8D9"`), conditions.ModifiedAccessConditions.IfMatch)
+	s.Equal(azblob.ETagNone, conditions.ModifiedAccessConditions.IfNoneMatch)
+
+	conditions = accessConditions(f.(*File).IfMatch(`"0x8D9"`).IfNotExist())
+	s.Equal(azblob.ETagNone, conditions.ModifiedAccessConditions.IfMatch, "IfNotExist and IfMatch are exclusive")
+	s.Equal(azblob.ETagAny, conditions.ModifiedAccessConditions.IfNoneMatch)
+	s.False(f.(*File).conditional(), "the original file should be unconditional")
+}
+
+func (s *PreconditionTestSuite) TestWriteIfNotExist() {
+	client := MockAzureClient{
+		PropertiesError: MockStorageError{},
+		ExpectedError:   MockStorageError{Code: azblob.ServiceCodeBlobAlreadyExists},
+	}
+	fs := NewFileSystem().WithClient(&client)
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	conditional := f.(*File).IfNotExist()
+	_, err = conditional.Write([]byte("worker-2"))
+	s.Require().NoError(err)
+	err = conditional.Close()
+	s.ErrorIs(err, vfs.ErrPreconditionFailed)
+	var preconditionErr *vfs.PreconditionFailedError
+	s.Require().ErrorAs(err, &preconditionErr)
+	s.Equal(f.URI(), preconditionErr.URI)
+}
+
+func (s *PreconditionTestSuite) TestDeleteIfMatch() {
+	client := MockAzureClient{ExpectedError: MockStorageError{Code: azblob.ServiceCodeConditionNotMet}}
+	fs := NewFileSystem().WithClient(&client)
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.Require().NoError(err)
+
+	s.ErrorIs(f.(*File).IfMatch(`"stale"`).Delete(), vfs.ErrPreconditionFailed)
+
+	// other errors, and errors of unconditional files, are returned as is
+	client.ExpectedError = errors.New("boom")
+	s.EqualError(f.(*File).IfMatch(`"0x8D9"`).Delete(), "boom")
+	client.ExpectedError = MockStorageError{Code: azblob.ServiceCodeConditionNotMet}
+	s.NotErrorIs(f.Delete(), vfs.ErrPreconditionFailed)
+}
+
+func TestPreconditionTestSuite(t *testing.T) {
+	suite.Run(t, new(PreconditionTestSuite))
+}
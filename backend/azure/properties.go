@@ -16,14 +16,44 @@ type BlobProperties struct {
 
 	// Metadata holds the Azure metadata
 	Metadata map[string]string
+
+	// ContentMD5 holds the MD5 of the blob's contents, if Azure stores one
+	ContentMD5 []byte
+
+	// ETag holds the ETag of the blob, which changes whenever the blob is written
+	ETag string
+
+	// AccessTier holds the tier of the blob, such as "Hot", "Cool", "Cold" or "Archive"
+	AccessTier string
+
+	// AccessTierInferred is true when the blob has no tier of its own and is in the account's default tier
+	AccessTierInferred bool
+
+	// ArchiveStatus holds the rehydration status of an archived blob, such as "rehydrate-pending-to-hot", if it's being
+	// rehydrated
+	ArchiveStatus string
+
+	// TagCount holds the number of blob index tags on the blob, which File.Tags returns
+	TagCount int64
 }
 
 // NewBlobProperties creates a new BlobProperties from an azblob.BlobGetPropertiesResponse
 func NewBlobProperties(azureProps *azblob.BlobGetPropertiesResponse) *BlobProperties {
 	lastModified := azureProps.LastModified()
+	tagCount := azureProps.TagCount()
+	if tagCount < 0 {
+		// the header is only sent for tagged blobs
+		tagCount = 0
+	}
 	return &BlobProperties{
-		LastModified: &lastModified,
-		Metadata:     azureProps.NewMetadata(),
-		Size:         uint64(azureProps.ContentLength()),
+		LastModified:       &lastModified,
+		Metadata:           azureProps.NewMetadata(),
+		Size:               uint64(azureProps.ContentLength()),
+		ContentMD5:         azureProps.ContentMD5(),
+		ETag:               string(azureProps.ETag()),
+		AccessTier:         azureProps.AccessTier(),
+		ArchiveStatus:      azureProps.ArchiveStatus(),
+		AccessTierInferred: azureProps.AccessTierInferred() == "true",
+		TagCount:           tagCount,
 	}
 }
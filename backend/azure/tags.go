@@ -0,0 +1,61 @@
+package azure
+
+import (
+	"github.com/c2fo/vfs/v6"
+)
+
+// Tags returns the blob index tags of the file's blob, or of the version it refers to.
+func (f *File) Tags() (map[string]string, error) {
+	tagger, err := f.fileSystem.tagger()
+	if err != nil {
+		return nil, err
+	}
+	return tagger.GetTags(f)
+}
+
+// SetTags replaces the blob index tags of the file's existing blob, or of the version it refers to, with tags.  An
+// empty map removes all tags.  Unlike metadata, setting tags doesn't change the blob's ETag or LastModified.
+func (f *File) SetTags(tags map[string]string) error {
+	tagger, err := f.fileSystem.tagger()
+	if err != nil {
+		return err
+	}
+	return tagger.SetTags(f, tags)
+}
+
+// FindBlobsByTags returns the files in the location, and below it, whose blob index tags match where, an Azure tag
+// filter expression such as "retention='1y' AND \"expires\" < '2024-01-01'".  Tags are indexed asynchronously, so a
+// blob tagged moments ago may not be found yet.
+func (l *Location) FindBlobsByTags(where string) ([]vfs.File, error) {
+	tagger, err := l.fileSystem.tagger()
+	if err != nil {
+		return nil, err
+	}
+	names, err := tagger.FindBlobsByTags(l, where)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]vfs.File, 0, len(names))
+	for _, name := range names {
+		file, err := l.fileSystem.NewFile(l.container, "/"+name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// tagger returns the file system's client, if it implements Tagger.
+func (fs *FileSystem) tagger() (Tagger, error) {
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	tagger, ok := client.(Tagger)
+	if !ok {
+		return nil, notSupported(client, "Tagger")
+	}
+	return tagger, nil
+}
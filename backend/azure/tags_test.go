@@ -0,0 +1,122 @@
+package azure
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TagsTestSuite struct {
+	suite.Suite
+}
+
+func (s *TagsTestSuite) TestTags() {
+	tags := map[string]string{"retention": "1y"}
+	client := MockAzureClient{ExpectedResult: tags}
+	fs := NewFileSystem().WithClient(&client)
+
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	actual, err := f.(*File).Tags()
+	s.NoError(err)
+	s.Equal(tags, actual)
+	s.NoError(f.(*File).SetTags(tags))
+
+	client = MockAzureClient{ExpectedError: errors.New("i always error")}
+	_, err = f.(*File).Tags()
+	s.Error(err, "get tags errors should be returned")
+	s.Error(f.(*File).SetTags(tags), "set tags errors should be returned")
+
+	fs.WithClient(baseClient{&client})
+	_, err = f.(*File).Tags()
+	s.ErrorIs(err, ErrNotSupported, "clients that don't tag blobs should return ErrNotSupported")
+	s.ErrorIs(f.(*File).SetTags(tags), ErrNotSupported)
+	_, err = f.Location().(*Location).FindBlobsByTags("retention='1y'")
+	s.ErrorIs(err, ErrNotSupported)
+}
+
+func (s *TagsTestSuite) TestFindBlobsByTags() {
+	client := MockAzureClient{ExpectedResult: []string{"logs/a.txt", "logs/2024/b.txt"}}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	l, err := fs.NewLocation("test-container", "/logs/")
+	s.NoError(err)
+	files, err := l.(*Location).FindBlobsByTags("retention='1y'")
+	s.NoError(err)
+	s.Require().Len(files, 2)
+	s.Equal("https://test-account.blob.core.windows.net/test-container/logs/a.txt", files[0].URI())
+	s.Equal("/logs/2024/b.txt", files[1].Path())
+
+	client = MockAzureClient{ExpectedError: errors.New("i always error")}
+	_, err = l.(*Location).FindBlobsByTags("retention='1y'")
+	s.Error(err, "find errors should be returned")
+}
+
+func (s *TagsTestSuite) TestDefaultClient() {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/xml")
+		if r.Method == http.MethodHead {
+			w.Header().Set("x-ms-tag-count", "1")
+			return
+		}
+		switch r.URL.Query().Get("comp") {
+		case "blobs":
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`+
+				`<Blob><Name>logs/a.txt</Name><ContainerName>bucket</ContainerName></Blob>`+
+				`<Blob><Name>other/b.txt</Name><ContainerName>bucket</ContainerName></Blob>`+
+				`</Blobs><NextMarker /></EnumerationResults>`)
+		case "tags":
+			if r.Method == http.MethodPut {
+				body, _ := io.ReadAll(r.Body)
+				s.Contains(string(body), "<Tag><Key>retention</Key><Value>1y</Value></Tag>")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?><Tags><TagSet>`+
+				`<Tag><Key>retention</Key><Value>1y</Value></Tag></TagSet></Tags>`)
+		}
+	}))
+	defer server.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("key"))
+	fs := NewFileSystem().WithOptions(Options{
+		ConnectionString: "AccountName=devstoreaccount1;AccountKey=" + key + ";BlobEndpoint=" + server.URL + "/devstoreaccount1;",
+	})
+	client, err := fs.tagger()
+	s.Require().NoError(err)
+
+	file, err := fs.NewFile("bucket", "/logs/a.txt")
+	s.Require().NoError(err)
+	tags, err := client.GetTags(file)
+	s.NoError(err)
+	s.Equal(map[string]string{"retention": "1y"}, tags)
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1/bucket/logs/a.txt", requests[0].URL.Path)
+
+	s.NoError(client.SetTags(file, map[string]string{"retention": "1y"}))
+
+	props, err := client.(Client).Properties(file.Location().(*Location).ContainerURL(), file.Path())
+	s.NoError(err)
+	s.Equal(int64(1), props.TagCount)
+
+	requests = nil
+	l, err := fs.NewLocation("bucket", "/logs/")
+	s.Require().NoError(err)
+	names, err := client.FindBlobsByTags(l, "retention='1y'")
+	s.NoError(err)
+	s.Equal([]string{"logs/a.txt"}, names, "blobs outside the location should be filtered out")
+	s.Require().Len(requests, 1)
+	s.Equal("/devstoreaccount1", requests[0].URL.Path, "the query should be sent to the service")
+	s.Equal("@container='bucket' AND retention='1y'", requests[0].URL.Query().Get("where"))
+}
+
+func TestTags(t *testing.T) {
+	suite.Run(t, new(TagsTestSuite))
+}
@@ -0,0 +1,80 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+// AccessTierCold is the Cold access tier, which azblob doesn't define.
+const AccessTierCold azblob.AccessTierType = "Cold"
+
+// ErrBlobArchived is returned when reading or copying a blob in the Archive tier, which has to be rehydrated to an
+// online tier with File.Rehydrate first.
+var ErrBlobArchived = errors.New("azure blob is in the Archive tier and must be rehydrated before it can be read")
+
+// WithAccessTier returns a File for the same blob whose uploads and native copies onto it write the blob in tier,
+// rather than in the AccessTier option or the account's default tier.
+func (f *File) WithAccessTier(tier azblob.AccessTierType) *File {
+	file := f.withConditions()
+	file.accessTier = tier
+	return file
+}
+
+// SetAccessTier moves the file's blob to tier.  Moving a blob out of the Archive tier rehydrates it with standard
+// priority, see Rehydrate.
+func (f *File) SetAccessTier(tier azblob.AccessTierType) error {
+	return f.Rehydrate(tier, azblob.RehydratePriorityNone)
+}
+
+// Rehydrate starts moving the file's archived blob to the online tier, Hot, Cool or Cold, with priority, Standard or
+// High.  Rehydration takes hours; until it completes the blob's BlobProperties have an ArchiveStatus of
+// "rehydrate-pending-to-<tier>" and reads return ErrBlobArchived.
+func (f *File) Rehydrate(tier azblob.AccessTierType, priority azblob.RehydratePriorityType) error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	setter, ok := client.(TierSetter)
+	if !ok {
+		return notSupported(client, "TierSetter")
+	}
+	return setter.SetTier(f, tier, priority)
+}
+
+// Properties returns the properties of the file's blob, including its access tier and archive status.
+func (f *File) Properties() (*BlobProperties, error) {
+	return f.properties()
+}
+
+// accessTier returns the tier to upload or copy file in: the file's own, the AccessTier option or the account's
+// default.
+func accessTier(file vfs.File) azblob.AccessTierType {
+	f, ok := file.(*File)
+	if !ok {
+		return azblob.DefaultAccessTier
+	}
+	if f.accessTier != azblob.AccessTierNone {
+		return f.accessTier
+	}
+	if f.fileSystem.options != nil {
+		return f.fileSystem.options.AccessTier
+	}
+	return azblob.DefaultAccessTier
+}
+
+// archivedError returns an error matching ErrBlobArchived if err is Azure refusing to read the file's archived blob,
+// otherwise err.
+func (f *File) archivedError(err error) error {
+	var storageErr azblob.StorageError
+	if errors.As(err, &storageErr) && storageErr.ServiceCode() == azblob.ServiceCodeBlobArchived {
+		return fmt.Errorf("%w: %s: %w", ErrBlobArchived, f.URI(), err)
+	}
+	return err
+}
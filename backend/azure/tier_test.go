@@ -0,0 +1,95 @@
+package azure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/stretchr/testify/suite"
+)
+
+type TierTestSuite struct {
+	suite.Suite
+}
+
+func (s *TierTestSuite) TestAccessTier() {
+	fs := NewFileSystem().WithOptions(Options{AccountName: "test-account"})
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	s.Equal(azblob.DefaultAccessTier, accessTier(f), "without a tier the account's default should be used")
+
+	fs = NewFileSystem().WithOptions(Options{AccountName: "test-account", AccessTier: azblob.AccessTierCool})
+	f, err = fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	s.Equal(azblob.AccessTierCool, accessTier(f), "the AccessTier option should be used")
+
+	archived := f.(*File).WithAccessTier(azblob.AccessTierArchive)
+	s.Equal(azblob.AccessTierArchive, accessTier(archived), "the file's tier should override the option")
+	s.Equal(azblob.AccessTierCool, accessTier(f), "WithAccessTier shouldn't change the original file")
+	s.Equal(AccessTierCold, accessTier(archived.IfNotExist().WithAccessTier(AccessTierCold)))
+	s.Equal(azblob.AccessTierArchive, accessTier(archived.IfNotExist()), "conditions should keep the file's tier")
+}
+
+func (s *TierTestSuite) TestProperties() {
+	props := &BlobProperties{AccessTier: "Archive", ArchiveStatus: "rehydrate-pending-to-hot"}
+	client := MockAzureClient{PropertiesResult: props}
+	fs := NewFileSystem().WithClient(&client)
+
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	actual, err := f.(*File).Properties()
+	s.NoError(err)
+	s.Equal(props, actual)
+}
+
+func (s *TierTestSuite) TestRehydrate() {
+	client := MockAzureClient{}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account"})
+
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	s.NoError(f.(*File).Rehydrate(azblob.AccessTierHot, azblob.RehydratePriorityHigh))
+	s.NoError(f.(*File).SetAccessTier(AccessTierCold))
+
+	client.ExpectedError = errors.New("i always error")
+	s.Error(f.(*File).Rehydrate(azblob.AccessTierHot, azblob.RehydratePriorityStandard), "set tier errors should be returned")
+
+	version, err := f.(*File).AtVersion("2023-04-05T06:07:08.0000000Z")
+	s.NoError(err)
+	s.ErrorIs(version.Rehydrate(azblob.AccessTierHot, azblob.RehydratePriorityStandard), ErrVersionReadOnly)
+
+	fs.WithClient(baseClient{&client})
+	s.ErrorIs(f.(*File).SetAccessTier(azblob.AccessTierHot), ErrNotSupported, "clients that don't set tiers should return ErrNotSupported")
+}
+
+func (s *TierTestSuite) TestReadArchived() {
+	client := MockAzureClient{
+		PropertiesResult: &BlobProperties{AccessTier: "Archive"},
+		ExpectedError:    MockStorageError{Code: azblob.ServiceCodeBlobArchived},
+	}
+	fs := NewFileSystem().WithClient(&client)
+
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	_, err = f.Read(make([]byte, 1))
+	s.ErrorIs(err, ErrBlobArchived, "reading an archived blob should return ErrBlobArchived")
+	s.ErrorAs(err, new(azblob.StorageError), "the storage error should still be wrapped")
+
+	client.ExpectedError = MockStorageError{}
+	s.NotErrorIs(f.(*File).archivedError(client.ExpectedError), ErrBlobArchived, "other storage errors should be returned as is")
+}
+
+func (s *TierTestSuite) TestCopyArchived() {
+	client := MockAzureClient{ExpectedError: MockStorageError{Code: azblob.ServiceCodeBlobArchived}}
+	fs := NewFileSystem().WithClient(&client).WithOptions(Options{AccountName: "test-account", AccountKey: "key"})
+
+	source, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	target, err := fs.NewFile("test-container", "/bar.txt")
+	s.NoError(err)
+	s.ErrorIs(source.CopyToFile(target), ErrBlobArchived, "copying an archived blob should return ErrBlobArchived")
+}
+
+func TestTier(t *testing.T) {
+	suite.Run(t, new(TierTestSuite))
+}
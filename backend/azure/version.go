@@ -0,0 +1,86 @@
+package azure
+
+import (
+	"errors"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+// ErrVersionReadOnly is returned when attempting to write to a File that refers to a specific blob version.
+var ErrVersionReadOnly = errors.New("azure file refers to a specific blob version and can't be written")
+
+// BlobVersion describes a version of a blob in a storage account with blob versioning enabled.
+type BlobVersion struct {
+	VersionID    string
+	Size         uint64
+	LastModified time.Time
+	IsLatest     bool
+}
+
+// Versions returns all versions of the file's blob, newest first.
+func (f *File) Versions() ([]BlobVersion, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := client.(VersionLister)
+	if !ok {
+		return nil, notSupported(client, "VersionLister")
+	}
+	return lister.ListVersions(f)
+}
+
+// AtVersion returns a File referring to the given version of the file's blob.  Reads, Size, LastModified, Exists and
+// copies of the returned File use that version.  Delete deletes only that version.  The returned File can't be written
+// or touched; ErrVersionReadOnly is returned instead.
+func (f *File) AtVersion(versionID string) (*File, error) {
+	if versionID == "" {
+		return nil, errors.New("non-empty string for versionID is required")
+	}
+
+	return &File{
+		fileSystem: f.fileSystem,
+		container:  f.container,
+		name:       f.name,
+		versionID:  versionID,
+	}, nil
+}
+
+// VersionID returns the version ID the file refers to, or an empty string if it refers to the current version.
+func (f *File) VersionID() string {
+	return f.versionID
+}
+
+// RestoreVersion makes the given version the current version of the file's blob by copying it over the current one.
+// The replaced version is kept.
+func (f *File) RestoreVersion(versionID string) error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+
+	version, err := f.AtVersion(versionID)
+	if err != nil {
+		return err
+	}
+	return version.CopyToFile(f)
+}
+
+// versionProperties returns the properties of the version the file refers to, found by listing the blob's versions.
+// A version that doesn't exist returns vfs.ErrNotExist.
+func (f *File) versionProperties() (*BlobProperties, error) {
+	versions, err := f.Versions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range versions {
+		if versions[i].VersionID == f.versionID {
+			return &BlobProperties{
+				Size:         versions[i].Size,
+				LastModified: &versions[i].LastModified,
+			}, nil
+		}
+	}
+	return nil, vfs.ErrNotExist
+}
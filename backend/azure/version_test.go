@@ -0,0 +1,112 @@
+package azure
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type VersionTestSuite struct {
+	suite.Suite
+}
+
+func (s *VersionTestSuite) TestVersions() {
+	modified := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	versions := []BlobVersion{
+		{VersionID: "2023-04-05T06:07:08.0000000Z", Size: 12, LastModified: modified, IsLatest: true},
+		{VersionID: "2023-04-04T06:07:08.0000000Z", Size: 5, LastModified: modified.Add(-24 * time.Hour)},
+	}
+	client := MockAzureClient{ExpectedResult: versions}
+	fs := NewFileSystem().WithClient(&client)
+
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+	actual, err := f.(*File).Versions()
+	s.NoError(err)
+	s.Equal(versions, actual)
+
+	client = MockAzureClient{ExpectedError: errors.New("i always error")}
+	_, err = f.(*File).Versions()
+	s.Error(err, "list errors should be returned")
+
+	fs.WithClient(baseClient{&client})
+	_, err = f.(*File).Versions()
+	s.ErrorIs(err, ErrNotSupported, "clients that don't list versions should return ErrNotSupported")
+}
+
+func (s *VersionTestSuite) TestAtVersion() {
+	fs := NewFileSystem().WithOptions(Options{AccountName: "test-account"})
+	f, err := fs.NewFile("test-container", "/foo.txt")
+	s.NoError(err)
+
+	_, err = f.(*File).AtVersion("")
+	s.Error(err, "a version ID is required")
+
+	version, err := f.(*File).AtVersion("2023-04-05T06:07:08.0000000Z")
+	s.NoError(err)
+	s.Equal("2023-04-05T06:07:08.0000000Z", version.VersionID())
+	s.Equal("", f.(*File).VersionID(), "the original file should not be pinned")
+	s.Equal(f.URI(), version.URI(), "a version shares its file's URI")
+}
+
+func (s *VersionTestSuite) TestVersionProperties() {
+	modified := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := MockAzureClient{ExpectedResult: []BlobVersion{
+		{VersionID: "v2", Size: 12, LastModified: modified, IsLatest: true},
+		{VersionID: "v1", Size: 5, LastModified: modified.Add(-time.Hour)},
+	}}
+	fs := NewFileSystem().WithClient(&client)
+	f, _ := fs.NewFile("test-container", "/foo.txt")
+
+	version, err := f.(*File).AtVersion("v1")
+	s.NoError(err)
+	exists, err := version.Exists()
+	s.NoError(err)
+	s.True(exists)
+	size, err := version.Size()
+	s.NoError(err)
+	s.Equal(uint64(5), size)
+	lastModified, err := version.LastModified()
+	s.NoError(err)
+	s.Equal(modified.Add(-time.Hour), *lastModified)
+
+	missing, err := f.(*File).AtVersion("v0")
+	s.NoError(err)
+	exists, err = missing.Exists()
+	s.NoError(err)
+	s.False(exists, "an unknown version should not exist")
+	_, err = missing.Size()
+	s.Error(err)
+}
+
+func (s *VersionTestSuite) TestVersionReadOnly() {
+	client := MockAzureClient{}
+	fs := NewFileSystem().WithClient(&client)
+	f, _ := fs.NewFile("test-container", "/foo.txt")
+	source, _ := fs.NewFile("test-container", "/bar.txt")
+	version, _ := f.(*File).AtVersion("v1")
+
+	_, err := version.Write([]byte("nope"))
+	s.ErrorIs(err, ErrVersionReadOnly)
+	s.ErrorIs(version.Touch(), ErrVersionReadOnly)
+	s.ErrorIs(source.CopyToFile(version), ErrVersionReadOnly)
+	s.ErrorIs(version.RestoreVersion("v0"), ErrVersionReadOnly)
+}
+
+func (s *VersionTestSuite) TestRestoreVersion() {
+	client := MockAzureClient{}
+	fs := NewFileSystem().WithClient(&client)
+	f, _ := fs.NewFile("test-container", "/foo.txt")
+
+	s.Error(f.(*File).RestoreVersion(""), "a version ID is required")
+	s.NoError(f.(*File).RestoreVersion("v1"), "restoring copies the version over the file")
+
+	client.ExpectedError = errors.New("copy failed")
+	s.Error(f.(*File).RestoreVersion("v1"), "copy errors should be returned")
+}
+
+func TestVersion(t *testing.T) {
+	suite.Run(t, new(VersionTestSuite))
+}
@@ -0,0 +1,50 @@
+/*
+Package chroot provides a vfs.FileSystem that confines all paths to a base vfs.Location.
+
+A chroot FileSystem wraps any existing backend location (the "root") and treats it as "/".  Absolute paths passed to
+FileSystem.NewFile and FileSystem.NewLocation, and relative paths passed to Location.NewLocation, Location.ChangeDir
+and Location.NewFile, are resolved against that root.  Any path that would traverse above the root, ie "../../etc/",
+results in ErrOutsideRoot rather than being silently clamped.
+
+This is useful for multi-tenant services that want to hand each tenant a vfs.FileSystem that cannot reach another
+tenant's data.
+
+# Usage
+
+A chroot FileSystem is not registered with the backend package since it requires a root location.  Create one directly:
+
+	import(
+	    "github.com/c2fo/vfs/v6/backend/chroot"
+	    "github.com/c2fo/vfs/v6/vfssimple"
+	)
+
+	func DoSomething() error {
+	    root, err := vfssimple.NewLocation("s3://mybucket/tenants/1234/")
+	    if err != nil {
+	        return err
+	    }
+
+	    fs, err := chroot.NewFileSystem(root)
+	    if err != nil {
+	        return err
+	    }
+
+	    // refers to s3://mybucket/tenants/1234/reports/daily.csv
+	    file, err := fs.NewFile("", "/reports/daily.csv")
+	    ...
+
+	    // returns chroot.ErrOutsideRoot
+	    _, err = file.Location().NewFile("../../other-tenant/secret.txt")
+	    ...
+	}
+
+# Paths and URIs
+
+Path() on a chroot Location or File returns the path relative to the root, ie "/reports/daily.csv" above.  Volume(),
+URI() and String() return the values of the underlying backend so that URIs remain usable outside of the chroot
+FileSystem, ie "s3://mybucket/tenants/1234/reports/daily.csv".
+
+Copy and move operations between chroot files (of the same or different roots) unwrap to the underlying files so any
+native copy/move support of the underlying backend is preserved.
+*/
+package chroot
@@ -0,0 +1,145 @@
+package chroot
+
+import (
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// File implements the vfs.File interface for a chroot FileSystem, wrapping a file of the underlying file system.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+	path       string
+}
+
+// Close calls Close on the underlying file.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Read calls Read on the underlying file.
+func (f *File) Read(p []byte) (n int, err error) {
+	return f.file.Read(p)
+}
+
+// Seek calls Seek on the underlying file.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write calls Write on the underlying file.
+func (f *File) Write(p []byte) (n int, err error) {
+	return f.file.Write(p)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Exists returns whether the underlying file exists.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Location returns the chroot Location of the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		location:   f.file.Location(),
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+	}
+}
+
+// CopyToLocation copies the file to a file of the same name at the given location.  The returned file belongs to the
+// given location's file system.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.CopyToFile(newFile)
+}
+
+// CopyToFile copies the file to the given file.  Chroot files are unwrapped first so the underlying file system may use
+// native copy functions where possible.
+func (f *File) CopyToFile(file vfs.File) error {
+	return f.file.CopyToFile(unwrap(file))
+}
+
+// MoveToLocation moves the file to a file of the same name at the given location.  The returned file belongs to the
+// given location's file system.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.MoveToFile(newFile)
+}
+
+// MoveToFile moves the file to the given file.  Chroot files are unwrapped first so the underlying file system may use
+// native move functions where possible.
+func (f *File) MoveToFile(file vfs.File) error {
+	return f.file.MoveToFile(unwrap(file))
+}
+
+// Delete deletes the underlying file.
+func (f *File) Delete(opts ...options.DeleteOption) error {
+	return f.file.Delete(opts...)
+}
+
+// LastModified returns the last modified time of the underlying file.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Size returns the size of the underlying file.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Path returns the absolute file path relative to the root of the chroot FileSystem, ie /some/path/to/file.txt
+func (f *File) Path() string {
+	return f.path
+}
+
+// Name returns the base name of the file.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Touch calls Touch on the underlying file.
+func (f *File) Touch() error {
+	return f.file.Touch()
+}
+
+// URI returns the fully qualified URI of the underlying file, ie s3://mybucket/root/some/path/to/file.txt
+func (f *File) URI() string {
+	return f.file.URI()
+}
+
+// SignedURL returns a signed URL of the underlying file, if its file system supports them.  See utils.SignedURL.
+func (f *File) SignedURL(method string, expiry time.Duration) (string, error) {
+	return utils.SignedURL(f.file, method, expiry)
+}
+
+// Checksum returns the checksum of the underlying file's contents for algorithm.  See utils.Checksum.
+func (f *File) Checksum(algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	return utils.Checksum(f.file, algorithm)
+}
+
+// ConcatenateFrom replaces the underlying file's contents with the contents of sources, in order.  Chroot files are
+// unwrapped first so the underlying file system may concatenate them natively.  See utils.Concatenate.
+func (f *File) ConcatenateFrom(sources ...vfs.File) error {
+	unwrapped := make([]vfs.File, len(sources))
+	for i, source := range sources {
+		unwrapped[i] = unwrap(source)
+	}
+	return utils.Concatenate(f.file, unwrapped...)
+}
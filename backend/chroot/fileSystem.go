@@ -0,0 +1,170 @@
+package chroot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+const name = "chroot"
+
+// ErrOutsideRoot is returned when a path would resolve to somewhere above the root of a chroot FileSystem.
+var ErrOutsideRoot = errors.New("path resolves outside of chroot root location")
+
+// FileSystem implements vfs.FileSystem, confining all files and locations to a root vfs.Location.
+type FileSystem struct {
+	root vfs.Location
+}
+
+// NewFileSystem initializes a chroot FileSystem rooted at the given location.  A copy of root is kept so later changes
+// to the caller's location (ie, ChangeDir) don't move the root.
+func NewFileSystem(root vfs.Location) (*FileSystem, error) {
+	if root == nil {
+		return nil, errors.New("non-nil root vfs.Location is required")
+	}
+
+	rootCopy, err := root.NewLocation("./")
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSystem{root: rootCopy}, nil
+}
+
+// Retry returns the retry function of the underlying file system.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return fs.root.FileSystem().Retry()
+}
+
+// NewFile returns a chroot File for the given absolute path, resolved relative to the root location.  Volume may be
+// empty or must match the root location's volume.
+func (fs *FileSystem) NewFile(volume, absFilePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil chroot.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(absFilePath); err != nil {
+		return nil, err
+	}
+	if err := fs.validateVolume(volume); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolvePath("/", absFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == "/" {
+		return nil, errors.New(utils.ErrBadAbsFilePath)
+	}
+
+	file, err := fs.root.NewFile(utils.RemoveLeadingSlash(resolved))
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		fileSystem: fs,
+		file:       file,
+		path:       resolved,
+	}, nil
+}
+
+// NewLocation returns a chroot Location for the given absolute path, resolved relative to the root location.  Volume
+// may be empty or must match the root location's volume.
+func (fs *FileSystem) NewLocation(volume, absLocPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil chroot.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(absLocPath); err != nil {
+		return nil, err
+	}
+	if err := fs.validateVolume(volume); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolvePath("/", absLocPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.newLocation(resolved)
+}
+
+// Name returns the name of the underlying file system, suffixed with "(chroot)".
+func (fs *FileSystem) Name() string {
+	return fmt.Sprintf("%s (%s)", fs.root.FileSystem().Name(), name)
+}
+
+// Scheme returns the scheme of the underlying file system so that URIs remain valid outside of the chroot.
+func (fs *FileSystem) Scheme() string {
+	return fs.root.FileSystem().Scheme()
+}
+
+// Root returns a copy of the root location of the FileSystem.
+func (fs *FileSystem) Root() vfs.Location {
+	root, _ := fs.root.NewLocation("./")
+	return root
+}
+
+func (fs *FileSystem) validateVolume(volume string) error {
+	if volume != "" && volume != fs.root.Volume() {
+		return fmt.Errorf("volume %q does not match chroot root volume %q", volume, fs.root.Volume())
+	}
+	return nil
+}
+
+// newLocation builds a Location from an already resolved, absolute chroot location path.
+func (fs *FileSystem) newLocation(resolved string) (*Location, error) {
+	relPath := "./"
+	if resolved != "/" {
+		relPath = utils.RemoveLeadingSlash(resolved)
+	}
+
+	loc, err := fs.root.NewLocation(relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{
+		fileSystem: fs,
+		location:   loc,
+		path:       resolved,
+	}, nil
+}
+
+// resolvePath joins relPath onto the absolute chroot path basePath and returns the shortest equivalent absolute path.
+// Unlike path.Clean, a ".." above the root is not clamped to "/"; ErrOutsideRoot is returned instead.  A trailing
+// slash on relPath is preserved.
+func resolvePath(basePath, relPath string) (string, error) {
+	var segments []string
+	for _, s := range strings.Split(basePath+"/"+relPath, "/") {
+		switch s {
+		case "", ".":
+			continue
+		case "..":
+			if len(segments) == 0 {
+				return "", ErrOutsideRoot
+			}
+			segments = segments[:len(segments)-1]
+		default:
+			segments = append(segments, s)
+		}
+	}
+
+	resolved := "/" + strings.Join(segments, "/")
+	if strings.HasSuffix(relPath, "/") {
+		resolved = utils.EnsureTrailingSlash(resolved)
+	}
+	return resolved, nil
+}
+
+// unwrap returns the underlying vfs.File of a chroot File, or the file itself for any other vfs.File.
+func unwrap(file vfs.File) vfs.File {
+	if f, ok := file.(*File); ok {
+		return f.file
+	}
+	return file
+}
@@ -0,0 +1,127 @@
+package chroot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	root vfs.Location
+	fs   *FileSystem
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	var err error
+	ts.root, err = mem.NewFileSystem().NewLocation("vol", "/tenants/1234/")
+	ts.Require().NoError(err)
+	ts.fs, err = NewFileSystem(ts.root)
+	ts.Require().NoError(err)
+}
+
+func (ts *fileSystemTestSuite) TestNewFileSystem() {
+	_, err := NewFileSystem(nil)
+	ts.EqualError(err, "non-nil root vfs.Location is required")
+
+	// changing the caller's location must not move the root
+	ts.NoError(ts.root.ChangeDir("../"))
+	ts.Equal("/tenants/1234/", ts.fs.Root().Path())
+}
+
+func (ts *fileSystemTestSuite) TestNameAndScheme() {
+	ts.Equal("In-Memory Filesystem (chroot)", ts.fs.Name())
+	ts.Equal(mem.Scheme, ts.fs.Scheme())
+	ts.NotNil(ts.fs.Retry())
+}
+
+func (ts *fileSystemTestSuite) TestNewFile() {
+	file, err := ts.fs.NewFile("", "/some/path/to/file.txt")
+	ts.NoError(err)
+	ts.Equal("/some/path/to/file.txt", file.Path())
+	ts.Equal("file.txt", file.Name())
+	ts.Equal("mem://vol/tenants/1234/some/path/to/file.txt", file.URI())
+
+	file, err = ts.fs.NewFile("vol", "/some/../other/./file.txt")
+	ts.NoError(err)
+	ts.Equal("/other/file.txt", file.Path())
+	ts.Equal("mem://vol/tenants/1234/other/file.txt", file.URI())
+}
+
+func (ts *fileSystemTestSuite) TestNewFile_Error() {
+	var nilFs *FileSystem
+	_, err := nilFs.NewFile("", "/file.txt")
+	ts.EqualError(err, "non-nil chroot.FileSystem pointer is required")
+
+	_, err = ts.fs.NewFile("", "relative/file.txt")
+	ts.EqualError(err, utils.ErrBadAbsFilePath)
+
+	_, err = ts.fs.NewFile("othervol", "/file.txt")
+	ts.EqualError(err, `volume "othervol" does not match chroot root volume "vol"`)
+
+	_, err = ts.fs.NewFile("", "/../1235/file.txt")
+	ts.ErrorIs(err, ErrOutsideRoot)
+
+	_, err = ts.fs.NewFile("", "/some/../../file.txt")
+	ts.ErrorIs(err, ErrOutsideRoot)
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation() {
+	loc, err := ts.fs.NewLocation("", "/")
+	ts.NoError(err)
+	ts.Equal("/", loc.Path())
+	ts.Equal("mem://vol/tenants/1234/", loc.URI())
+
+	loc, err = ts.fs.NewLocation("vol", "/some/path/../to/")
+	ts.NoError(err)
+	ts.Equal("/some/to/", loc.Path())
+	ts.Equal("vol", loc.Volume())
+	ts.Equal("mem://vol/tenants/1234/some/to/", loc.URI())
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation_Error() {
+	var nilFs *FileSystem
+	_, err := nilFs.NewLocation("", "/")
+	ts.EqualError(err, "non-nil chroot.FileSystem pointer is required")
+
+	_, err = ts.fs.NewLocation("", "/some/path")
+	ts.EqualError(err, utils.ErrBadAbsLocationPath)
+
+	_, err = ts.fs.NewLocation("othervol", "/")
+	ts.Error(err)
+
+	_, err = ts.fs.NewLocation("", "/../")
+	ts.ErrorIs(err, ErrOutsideRoot)
+}
+
+func (ts *fileSystemTestSuite) TestResolvePath() {
+	tests := []struct {
+		base, rel, expected string
+		err                 error
+	}{
+		{base: "/", rel: "file.txt", expected: "/file.txt"},
+		{base: "/a/b/", rel: "../c/", expected: "/a/c/"},
+		{base: "/a/b/", rel: "../../", expected: "/"},
+		{base: "/a/b/", rel: "./c/../d.txt", expected: "/a/b/d.txt"},
+		{base: "/a/b/", rel: "../../../", err: ErrOutsideRoot},
+		{base: "/", rel: "a/../../b.txt", err: ErrOutsideRoot},
+	}
+
+	for _, t := range tests {
+		actual, err := resolvePath(t.base, t.rel)
+		if t.err != nil {
+			ts.ErrorIs(err, t.err, "%s + %s", t.base, t.rel)
+			continue
+		}
+		ts.NoError(err, "%s + %s", t.base, t.rel)
+		ts.Equal(t.expected, actual, "%s + %s", t.base, t.rel)
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
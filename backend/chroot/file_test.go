@@ -0,0 +1,167 @@
+package chroot
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type fileTestSuite struct {
+	suite.Suite
+	memFs *mem.FileSystem
+	fs    *FileSystem
+}
+
+func (ts *fileTestSuite) SetupTest() {
+	ts.memFs = mem.NewFileSystem()
+	root, err := ts.memFs.NewLocation("", "/tenants/1234/")
+	ts.Require().NoError(err)
+	ts.fs, err = NewFileSystem(root)
+	ts.Require().NoError(err)
+}
+
+func (ts *fileTestSuite) TestReadWrite() {
+	file, err := ts.fs.NewFile("", "/dir/file.txt")
+	ts.Require().NoError(err)
+
+	_, err = file.Write([]byte("hello world"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	// file is visible through the underlying file system at the root
+	memFile, err := ts.memFs.NewFile("", "/tenants/1234/dir/file.txt")
+	ts.Require().NoError(err)
+	exists, err := memFile.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(11), size)
+
+	pos, err := file.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	ts.Equal(int64(6), pos)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("world", string(data))
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestLocation() {
+	file, err := ts.fs.NewFile("", "/dir/file.txt")
+	ts.Require().NoError(err)
+
+	loc := file.Location()
+	ts.Equal("/dir/", loc.Path())
+	ts.Equal("mem:///tenants/1234/dir/", loc.URI())
+	ts.Equal(ts.fs, loc.FileSystem())
+
+	_, err = loc.NewLocation("../../")
+	ts.ErrorIs(err, ErrOutsideRoot)
+}
+
+func (ts *fileTestSuite) TestCopyAndMove() {
+	src, err := ts.fs.NewFile("", "/src.txt")
+	ts.Require().NoError(err)
+	_, err = src.Write([]byte("contents"))
+	ts.NoError(err)
+	ts.NoError(src.Close())
+
+	dstLoc, err := ts.fs.NewLocation("", "/copies/")
+	ts.Require().NoError(err)
+	copied, err := src.CopyToLocation(dstLoc)
+	ts.NoError(err)
+	ts.Equal("/copies/src.txt", copied.Path())
+	ts.IsType(&File{}, copied)
+	data, err := io.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("contents", string(data))
+	ts.NoError(copied.Close())
+
+	// copy out of the chroot to a plain file of the underlying file system
+	outside, err := ts.memFs.NewFile("", "/outside.txt")
+	ts.Require().NoError(err)
+	ts.NoError(src.CopyToFile(outside))
+	data, err = io.ReadAll(outside)
+	ts.NoError(err)
+	ts.Equal("contents", string(data))
+
+	moved, err := src.MoveToLocation(dstLoc.(*Location))
+	ts.Require().NoError(err)
+	ts.Equal("/copies/src.txt", moved.Path())
+	exists, err := src.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileTestSuite) TestDeleteAndTouch() {
+	file, err := ts.fs.NewFile("", "/touched.txt")
+	ts.Require().NoError(err)
+	ts.NoError(file.Touch())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	modified, err := file.LastModified()
+	ts.NoError(err)
+	ts.NotNil(modified)
+
+	ts.NoError(file.Delete())
+	exists, err = file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileTestSuite) TestSignedURL() {
+	file, err := ts.fs.NewFile("", "/file.txt")
+	ts.Require().NoError(err)
+
+	// the underlying mem file can't be signed
+	_, err = file.(*File).SignedURL(http.MethodGet, time.Hour)
+	ts.ErrorIs(err, vfs.ErrSignedURLNotSupported)
+}
+
+func (ts *fileTestSuite) TestChecksum() {
+	file, err := ts.fs.NewFile("", "/file.txt")
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte("hello"))
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+
+	// the underlying mem file is streamed
+	sum, err := file.(*File).Checksum(vfs.ChecksumSHA256)
+	ts.NoError(err)
+	ts.Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", hex.EncodeToString(sum))
+}
+
+func (ts *fileTestSuite) TestConcatenateFrom() {
+	var sources []vfs.File
+	for _, name := range []string{"/shards/1.txt", "/shards/2.txt"} {
+		file, err := ts.fs.NewFile("", name)
+		ts.Require().NoError(err)
+		_, err = file.Write([]byte(name))
+		ts.Require().NoError(err)
+		ts.Require().NoError(file.Close())
+		sources = append(sources, file)
+	}
+
+	target, err := ts.fs.NewFile("", "/all.txt")
+	ts.Require().NoError(err)
+	ts.NoError(target.(*File).ConcatenateFrom(sources...))
+	contents, err := io.ReadAll(target)
+	ts.NoError(err)
+	ts.Equal("/shards/1.txt/shards/2.txt", string(contents))
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
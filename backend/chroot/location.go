@@ -0,0 +1,146 @@
+package chroot
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Location implements the vfs.Location interface for a chroot FileSystem, wrapping a location of the underlying file
+// system.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+	path       string
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
+
+// List returns the files at the location by calling List on the underlying location.
+func (l *Location) List() ([]string, error) {
+	return l.location.List()
+}
+
+// ListByPrefix returns the files at the location whose names start with prefix by calling ListByPrefix on the
+// underlying location.  A relative prefix that would resolve above the root returns ErrOutsideRoot.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidatePrefix(prefix); err != nil {
+		return []string{}, err
+	}
+	if _, err := resolvePath(l.path, prefix); err != nil {
+		return []string{}, err
+	}
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex returns the files at the location matching regex by calling ListByRegex on the underlying location.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.location.ListByRegex(regex)
+}
+
+// Volume returns the volume of the underlying location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path returns the absolute location path relative to the root of the chroot FileSystem, ie /some/path/to/.
+func (l *Location) Path() string {
+	return l.path
+}
+
+// Exists returns whether the underlying location exists.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation returns a new Location relative to the current one.  Returns ErrOutsideRoot if the resulting path would
+// be above the root of the chroot FileSystem.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	if l == nil {
+		return nil, errors.New("non-nil chroot.Location pointer is required")
+	}
+	if err := utils.ValidateRelativeLocationPath(relativePath); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolvePath(l.path, relativePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.fileSystem.newLocation(resolved)
+}
+
+// ChangeDir updates the location's path relative to its current path.  Returns ErrOutsideRoot, leaving the location
+// unchanged, if the resulting path would be above the root of the chroot FileSystem.
+func (l *Location) ChangeDir(relativePath string) error {
+	if l == nil {
+		return errors.New("non-nil chroot.Location pointer is required")
+	}
+	if relativePath == "" {
+		return errors.New("non-empty string relativePath is required")
+	}
+	if err := utils.ValidateRelativeLocationPath(relativePath); err != nil {
+		return err
+	}
+
+	resolved, err := resolvePath(l.path, relativePath)
+	if err != nil {
+		return err
+	}
+
+	newLocation, err := l.fileSystem.newLocation(resolved)
+	if err != nil {
+		return err
+	}
+
+	*l = *newLocation
+	return nil
+}
+
+// FileSystem returns the chroot FileSystem of the location.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a new File relative to the location.  Returns ErrOutsideRoot if the resulting path would be above
+// the root of the chroot FileSystem.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	if l == nil {
+		return nil, errors.New("non-nil chroot.Location pointer is required")
+	}
+	if relFilePath == "" {
+		return nil, errors.New("non-empty string filePath is required")
+	}
+	if err := utils.ValidateRelativeFilePath(relFilePath); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolvePath(l.path, relFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.fileSystem.NewFile("", resolved)
+}
+
+// DeleteFile deletes the file of the given name at the location.
+func (l *Location) DeleteFile(relFilePath string, opts ...options.DeleteOption) error {
+	file, err := l.NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+
+	return file.Delete(opts...)
+}
+
+// URI returns the fully qualified URI of the underlying location, ie s3://mybucket/root/some/path/.
+func (l *Location) URI() string {
+	return l.location.URI()
+}
@@ -0,0 +1,108 @@
+package chroot
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type locationTestSuite struct {
+	suite.Suite
+	fs  *FileSystem
+	loc vfs.Location
+}
+
+func (ts *locationTestSuite) SetupTest() {
+	root, err := mem.NewFileSystem().NewLocation("", "/tenants/1234/")
+	ts.Require().NoError(err)
+	ts.fs, err = NewFileSystem(root)
+	ts.Require().NoError(err)
+	ts.loc, err = ts.fs.NewLocation("", "/some/path/")
+	ts.Require().NoError(err)
+
+	for _, name := range []string{"/some/path/a.txt", "/some/path/b.csv", "/some/other.txt"} {
+		f, err := ts.fs.NewFile("", name)
+		ts.Require().NoError(err)
+		ts.Require().NoError(f.Touch())
+	}
+}
+
+func (ts *locationTestSuite) TestNewLocation() {
+	loc, err := ts.loc.NewLocation("../other/")
+	ts.NoError(err)
+	ts.Equal("/some/other/", loc.Path())
+	ts.Equal("mem:///tenants/1234/some/other/", loc.URI())
+	ts.Equal("/some/path/", ts.loc.Path(), "original location is unchanged")
+
+	loc, err = ts.loc.NewLocation("../../")
+	ts.NoError(err)
+	ts.Equal("/", loc.Path())
+
+	_, err = ts.loc.NewLocation("../../../")
+	ts.ErrorIs(err, ErrOutsideRoot)
+
+	_, err = ts.loc.NewLocation("/absolute/")
+	ts.Error(err)
+}
+
+func (ts *locationTestSuite) TestChangeDir() {
+	ts.NoError(ts.loc.ChangeDir("../"))
+	ts.Equal("/some/", ts.loc.Path())
+	ts.Equal("mem:///tenants/1234/some/", ts.loc.URI())
+
+	ts.ErrorIs(ts.loc.ChangeDir("../../"), ErrOutsideRoot)
+	ts.Equal("/some/", ts.loc.Path(), "location is unchanged after a failed ChangeDir")
+
+	ts.Error(ts.loc.ChangeDir(""))
+}
+
+func (ts *locationTestSuite) TestNewFile() {
+	file, err := ts.loc.NewFile("../other.txt")
+	ts.NoError(err)
+	ts.Equal("/some/other.txt", file.Path())
+	ts.Equal("mem:///tenants/1234/some/other.txt", file.URI())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	_, err = ts.loc.NewFile("../../../1235/secret.txt")
+	ts.ErrorIs(err, ErrOutsideRoot)
+
+	_, err = ts.loc.NewFile("")
+	ts.Error(err)
+}
+
+func (ts *locationTestSuite) TestList() {
+	list, err := ts.loc.List()
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.txt", "b.csv"}, list)
+
+	list, err = ts.loc.ListByRegex(regexp.MustCompile(`\.txt$`))
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt"}, list)
+
+	_, err = ts.loc.ListByPrefix("../../../a")
+	ts.ErrorIs(err, ErrOutsideRoot)
+}
+
+func (ts *locationTestSuite) TestDeleteFile() {
+	ts.NoError(ts.loc.DeleteFile("a.txt"))
+	list, err := ts.loc.List()
+	ts.NoError(err)
+	ts.Equal([]string{"b.csv"}, list)
+
+	ts.ErrorIs(ts.loc.DeleteFile("../../../other.txt"), ErrOutsideRoot)
+}
+
+func (ts *locationTestSuite) TestFileSystem() {
+	ts.Equal(ts.fs, ts.loc.FileSystem())
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
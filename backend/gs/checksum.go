@@ -0,0 +1,85 @@
+package gs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// storedChecksum returns the raw checksum GCS stores for algorithm, or nil if it doesn't store one.  GCS stores the
+// CRC32C of every object and the MD5 of objects that weren't composed.
+func storedChecksum(attrs *storage.ObjectAttrs, algorithm vfs.ChecksumAlgorithm) []byte {
+	switch algorithm {
+	case vfs.ChecksumCRC32C:
+		return binary.BigEndian.AppendUint32(nil, attrs.CRC32C)
+	case vfs.ChecksumMD5:
+		return attrs.MD5
+	default:
+		return nil
+	}
+}
+
+// Checksum returns the checksum of the file's contents for algorithm, vfs.ChecksumCRC32C, vfs.ChecksumMD5 or
+// vfs.ChecksumSHA256.  The checksum stored by GCS is returned when there is one, otherwise the object is streamed to
+// compute it.
+func (f *File) Checksum(algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	if _, err := utils.NewChecksumHash(algorithm); err != nil {
+		return nil, err
+	}
+
+	attrs, err := f.getObjectAttrs()
+	if err != nil {
+		return nil, err
+	}
+	if sum := storedChecksum(attrs, algorithm); sum != nil {
+		return sum, nil
+	}
+
+	// stream a copy of the file so its cursor and any pending writes are left alone
+	return utils.ComputeChecksum(&File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		generation: attrs.Generation,
+	}, algorithm)
+}
+
+// setWriterChecksum sets the checksum of contents for algorithm on w, so GCS rejects the upload if the bytes it
+// receives don't match.
+func setWriterChecksum(w *storage.Writer, algorithm vfs.ChecksumAlgorithm, contents []byte) error {
+	h, err := utils.NewChecksumHash(algorithm)
+	if err != nil {
+		return err
+	}
+	_, _ = h.Write(contents)
+
+	switch algorithm {
+	case vfs.ChecksumCRC32C:
+		w.CRC32C = binary.BigEndian.Uint32(h.Sum(nil))
+		w.SendCRC32C = true
+	case vfs.ChecksumMD5:
+		w.MD5 = h.Sum(nil)
+	default:
+		return fmt.Errorf("%w: gs only verifies %s and %s uploads", vfs.ErrChecksumAlgorithm, vfs.ChecksumCRC32C,
+			vfs.ChecksumMD5)
+	}
+	return nil
+}
+
+// verifiedReader wraps reader, reading the object with attrs, to return vfs.ErrChecksumMismatch when read to the end if
+// the object's contents don't match the checksum GCS stores for algorithm, which must be supported.  Objects stored
+// with gzip Content-Encoding aren't verified, since they're decompressed when read but their checksums are of the
+// compressed bytes.
+func verifiedReader(reader io.ReadCloser, attrs *storage.ObjectAttrs, algorithm vfs.ChecksumAlgorithm) io.ReadCloser {
+	sum := storedChecksum(attrs, algorithm)
+	if sum == nil || attrs.ContentEncoding == "gzip" {
+		return reader
+	}
+	h, _ := utils.NewChecksumHash(algorithm)
+	return utils.NewChecksumReader(reader, h, sum)
+}
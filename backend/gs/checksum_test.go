@@ -0,0 +1,123 @@
+package gs
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is used for integrity checks, not security
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type checksumTestSuite struct {
+	suite.Suite
+	server *fakestorage.Server
+	fs     *FileSystem
+}
+
+func (ts *checksumTestSuite) SetupTest() {
+	ts.server = fakestorage.NewServer(Objects{})
+	ts.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "bucki"})
+	ts.fs = NewFileSystem().WithClient(ts.server.Client())
+}
+
+func (ts *checksumTestSuite) TearDownTest() {
+	ts.server.Stop()
+}
+
+func (ts *checksumTestSuite) newFile(name string) *File {
+	file, err := ts.fs.NewFile("bucki", name)
+	ts.Require().NoError(err)
+	return file.(*File)
+}
+
+func (ts *checksumTestSuite) TestWriteAndChecksum() {
+	ts.fs.options = Options{ChecksumAlgorithm: vfs.ChecksumMD5}
+	file := ts.newFile("/reports/daily.csv")
+	_, err := file.Write([]byte("a,b,c"))
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+
+	md5Sum := md5.Sum([]byte("a,b,c")) //nolint:gosec // MD5 is used for integrity checks, not security
+	sum, err := file.Checksum(vfs.ChecksumMD5)
+	ts.NoError(err)
+	ts.Equal(md5Sum[:], sum)
+
+	crc := crc32.Checksum([]byte("a,b,c"), crc32.MakeTable(crc32.Castagnoli))
+	sum, err = file.Checksum(vfs.ChecksumCRC32C)
+	ts.NoError(err)
+	ts.Equal(binary.BigEndian.AppendUint32(nil, crc), sum)
+
+	// GCS doesn't store SHA-256 checksums, so the object is streamed
+	sha := sha256.Sum256([]byte("a,b,c"))
+	sum, err = file.Checksum(vfs.ChecksumSHA256)
+	ts.NoError(err)
+	ts.Equal(sha[:], sum)
+
+	_, err = file.Checksum("CRC64")
+	ts.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+
+	_, err = ts.newFile("/reports/missing.csv").Checksum(vfs.ChecksumMD5)
+	ts.ErrorIs(err, storage.ErrObjectNotExist)
+}
+
+func (ts *checksumTestSuite) TestSetWriterChecksum() {
+	w := ts.server.Client().Bucket("bucki").Object("file.txt").NewWriter(context.Background())
+
+	ts.Require().NoError(setWriterChecksum(w, vfs.ChecksumCRC32C, []byte("a,b,c")))
+	ts.Equal(crc32.Checksum([]byte("a,b,c"), crc32.MakeTable(crc32.Castagnoli)), w.CRC32C)
+	ts.True(w.SendCRC32C)
+
+	ts.Require().NoError(setWriterChecksum(w, vfs.ChecksumMD5, []byte("a,b,c")))
+	md5Sum := md5.Sum([]byte("a,b,c")) //nolint:gosec // MD5 is used for integrity checks, not security
+	ts.Equal(md5Sum[:], w.MD5)
+
+	err := setWriterChecksum(w, vfs.ChecksumSHA256, []byte("a,b,c"))
+	ts.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+	ts.EqualError(err, "unsupported checksum algorithm: gs only verifies CRC32C and MD5 uploads")
+}
+
+func (ts *checksumTestSuite) TestReadVerifies() {
+	ts.server.CreateObject(fakestorage.Object{
+		ObjectAttrs: fakestorage.ObjectAttrs{BucketName: "bucki", Name: "reports/daily.csv"},
+		Content:     []byte("a,b,c"),
+	})
+	file := ts.newFile("/reports/daily.csv")
+
+	for _, algorithm := range []vfs.ChecksumAlgorithm{"", vfs.ChecksumCRC32C, vfs.ChecksumMD5} {
+		ts.fs.options = Options{ChecksumAlgorithm: algorithm}
+		contents, err := io.ReadAll(file)
+		ts.NoError(err, algorithm)
+		ts.Equal("a,b,c", string(contents), algorithm)
+		ts.Require().NoError(file.Close())
+	}
+
+	ts.fs.options = Options{ChecksumAlgorithm: "CRC64"}
+	_, err := io.ReadAll(file)
+	ts.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+}
+
+func (ts *checksumTestSuite) TestVerifiedReaderSkipsGzip() {
+	attrs := &storage.ObjectAttrs{CRC32C: crc32.Checksum([]byte("compressed"), crc32.MakeTable(crc32.Castagnoli))}
+
+	_, err := io.ReadAll(verifiedReader(io.NopCloser(strings.NewReader("a,b,c")), attrs, vfs.ChecksumCRC32C))
+	ts.ErrorIs(err, vfs.ErrChecksumMismatch)
+
+	// gzip objects are decompressed when read, so their contents can't match the checksum of the compressed bytes
+	attrs.ContentEncoding = "gzip"
+	contents, err := io.ReadAll(verifiedReader(io.NopCloser(strings.NewReader("a,b,c")), attrs, vfs.ChecksumCRC32C))
+	ts.NoError(err)
+	ts.Equal("a,b,c", string(contents))
+}
+
+func TestChecksum(t *testing.T) {
+	suite.Run(t, new(checksumTestSuite))
+}
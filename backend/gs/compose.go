@@ -0,0 +1,114 @@
+package gs
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// maxComposeSources is the most source objects GCS composes in one request.
+const maxComposeSources = 32
+
+// ConcatenateFrom replaces the file's contents with the contents of sources, in order, implementing
+// vfs.Concatenator.  Sources in the file's bucket, of the same file system or with the same credentials, are
+// concatenated with GCS Compose.  More than 32 sources are first composed in groups into temporary objects next to the
+// file, which are deleted afterwards.  Otherwise the sources are streamed with utils.ConcatenateStreamed.
+func (f *File) ConcatenateFrom(sources ...vfs.File) error {
+	if f.generation != 0 {
+		return ErrGenerationReadOnly
+	}
+	if len(sources) == 0 {
+		return vfs.ErrConcatenateNoSources
+	}
+
+	handles, native, err := f.composeSources(sources)
+	if err != nil {
+		return err
+	}
+	if !native {
+		return utils.ConcatenateStreamed(f, sources...)
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	// compose groups of sources into temporary objects until the rest can be composed at once
+	var temporaries []*storage.ObjectHandle
+	defer func() {
+		for _, temporary := range temporaries {
+			_ = f.fileSystem.Retry()(func() error { return temporary.Delete(f.fileSystem.ctx) })
+		}
+	}()
+	prefix := fmt.Sprintf("%s.compose-%d", utils.RemoveLeadingSlash(f.key), time.Now().UnixNano())
+	for len(handles) > maxComposeSources {
+		next := make([]*storage.ObjectHandle, 0, (len(handles)+maxComposeSources-1)/maxComposeSources)
+		for start := 0; start < len(handles); start += maxComposeSources {
+			end := start + maxComposeSources
+			if end > len(handles) {
+				end = len(handles)
+			}
+			if end-start == 1 {
+				next = append(next, handles[start])
+				continue
+			}
+
+			temporary := f.fileSystem.encrypted(
+				f.fileSystem.bucket(client, f.bucket).Object(fmt.Sprintf("%s-%d", prefix, len(temporaries))))
+			if err := f.compose(temporary, handles[start:end], WriteOptions{}); err != nil {
+				return err
+			}
+			temporaries = append(temporaries, temporary)
+			next = append(next, temporary)
+		}
+		handles = next
+	}
+
+	target, err := f.getConditionalObjectHandle()
+	if err != nil {
+		return err
+	}
+	return f.preconditionError(f.compose(target.ObjectHandle(), handles, f.getWriteOptions()))
+}
+
+// composeSources returns the object handles of sources if they can all be composed into the file, or false if any
+// of them has to be streamed.
+func (f *File) composeSources(sources []vfs.File) ([]*storage.ObjectHandle, bool, error) {
+	handles := make([]*storage.ObjectHandle, len(sources))
+	for i, source := range sources {
+		gsSource, ok := source.(*File)
+		if !ok || gsSource.bucket != f.bucket {
+			return nil, false, nil
+		}
+		if gsSource.fileSystem != f.fileSystem {
+			opts, ok := gsSource.fileSystem.options.(Options)
+			if !ok || !f.isSameAuth(&opts) {
+				return nil, false, nil
+			}
+		}
+
+		handle, err := gsSource.getObjectHandle()
+		if err != nil {
+			return nil, false, err
+		}
+		handles[i] = handle.ObjectHandle()
+	}
+	return handles, true, nil
+}
+
+// compose runs a Compose request writing the concatenation of sources, at most 32, to target created with opts.
+func (f *File) compose(target *storage.ObjectHandle, sources []*storage.ObjectHandle, opts WriteOptions) error {
+	return f.fileSystem.Retry()(func() error {
+		composer := target.ComposerFrom(sources...)
+		composer.KMSKeyName = opts.KMSKeyName
+		composer.StorageClass = opts.StorageClass
+		composer.Retention = opts.retention()
+		_, err := composer.Run(f.fileSystem.ctx)
+		return err
+	})
+}
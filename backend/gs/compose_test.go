@@ -0,0 +1,110 @@
+package gs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type composeTestSuite struct {
+	suite.Suite
+	server *fakestorage.Server
+	fs     *FileSystem
+}
+
+func (ts *composeTestSuite) SetupTest() {
+	ts.server = fakestorage.NewServer(Objects{})
+	ts.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "bucki"})
+	ts.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "other"})
+	ts.fs = NewFileSystem().WithClient(ts.server.Client())
+}
+
+func (ts *composeTestSuite) TearDownTest() {
+	ts.server.Stop()
+}
+
+// writeShards writes count shard files to bucket and returns them with their expected concatenation.
+func (ts *composeTestSuite) writeShards(bucket string, count int) ([]vfs.File, string) {
+	var (
+		shards   []vfs.File
+		expected strings.Builder
+	)
+	for i := 0; i < count; i++ {
+		shard, err := ts.fs.NewFile(bucket, fmt.Sprintf("/shards/part-%03d", i))
+		ts.Require().NoError(err)
+		contents := fmt.Sprintf("shard %d\n", i)
+		_, err = shard.Write([]byte(contents))
+		ts.Require().NoError(err)
+		ts.Require().NoError(shard.Close())
+		shards = append(shards, shard)
+		expected.WriteString(contents)
+	}
+	return shards, expected.String()
+}
+
+func (ts *composeTestSuite) readAll(file vfs.File) string {
+	contents, err := io.ReadAll(file)
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+	return string(contents)
+}
+
+func (ts *composeTestSuite) TestConcatenateFrom() {
+	ts.Implements((*vfs.Concatenator)(nil), &File{}, "Does not implement the vfs.Concatenator interface")
+
+	shards, expected := ts.writeShards("bucki", 3)
+	target, err := ts.fs.NewFile("bucki", "/output.txt")
+	ts.Require().NoError(err)
+
+	ts.NoError(target.(*File).ConcatenateFrom(shards...))
+	ts.Equal(expected, ts.readAll(target))
+
+	// appending to the target itself is composed too
+	ts.NoError(target.(*File).ConcatenateFrom(target, shards[0]))
+	ts.Equal(expected+"shard 0\n", ts.readAll(target))
+}
+
+func (ts *composeTestSuite) TestConcatenateFromManySources() {
+	// 70 sources are composed into 3 temporary objects first
+	shards, expected := ts.writeShards("bucki", 70)
+	target, err := ts.fs.NewFile("bucki", "/output.txt")
+	ts.Require().NoError(err)
+
+	ts.NoError(target.(*File).ConcatenateFrom(shards...))
+	ts.Equal(expected, ts.readAll(target))
+
+	objects, _, err := ts.server.ListObjectsWithOptions("bucki", fakestorage.ListOptions{Prefix: "output.txt"})
+	ts.Require().NoError(err)
+	ts.Len(objects, 1, "temporary objects should be deleted")
+}
+
+func (ts *composeTestSuite) TestConcatenateFromOtherBucket() {
+	// sources in another bucket can't be composed, so they're streamed
+	shards, expected := ts.writeShards("other", 2)
+	target, err := ts.fs.NewFile("bucki", "/output.txt")
+	ts.Require().NoError(err)
+
+	ts.NoError(target.(*File).ConcatenateFrom(shards...))
+	ts.Equal(expected, ts.readAll(target))
+}
+
+func (ts *composeTestSuite) TestConcatenateFromErrors() {
+	target, err := ts.fs.NewFile("bucki", "/output.txt")
+	ts.Require().NoError(err)
+	ts.ErrorIs(target.(*File).ConcatenateFrom(), vfs.ErrConcatenateNoSources)
+
+	shards, _ := ts.writeShards("bucki", 1)
+
+	pinned := &File{fileSystem: ts.fs, bucket: "bucki", key: "/output.txt", generation: 1}
+	ts.ErrorIs(pinned.ConcatenateFrom(shards...), ErrGenerationReadOnly)
+}
+
+func TestCompose(t *testing.T) {
+	suite.Run(t, new(composeTestSuite))
+}
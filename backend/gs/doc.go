@@ -53,6 +53,97 @@ would have to be cast as gs.Filesystem to use the following:
 	    fs = fs.WithClient(client)
 	}
 
+# Client Options
+
+The client options of gs.Options are combined when the client is created, so credentials can be used with an
+Endpoint and Scopes.  At most one way of authenticating can be set: APIKey, CredentialFile, CredentialsJSON,
+WithoutAuthentication or an HTTPClient that authenticates its own requests.  Client() returns an error for conflicting
+options instead of dropping any of them.  UserProject bills requests to requester pays buckets to a project.  To use
+fake-gcs-server:
+
+	fs = fs.WithOptions(gs.Options{
+	    Endpoint:              "http://localhost:4443/storage/v1/",
+	    WithoutAuthentication: true,
+	})
+
+# Encryption and Storage Class
+
+Options.WriteOptions set the Cloud KMS key, storage class and object retention of the objects created by writes,
+Touch, ConcatenateFrom and native copies.  The gs.File method WithWriteOptions() returns a File for the same object
+overriding them.  Native copies preserve the storage class, KMS key and retention of the source object unless they're
+overridden.  Options.CustomerSuppliedKey, a 32-byte AES-256 key, encrypts the objects written and decrypts the objects
+read; it can't be combined with a KMSKeyName:
+
+	fs = fs.WithOptions(gs.Options{
+	    WriteOptions: gs.WriteOptions{
+	        KMSKeyName:   "projects/P/locations/L/keyRings/R/cryptoKeys/K",
+	        StorageClass: "NEARLINE",
+	    },
+	})
+	archived := gsFile.WithWriteOptions(gs.WriteOptions{StorageClass: "ARCHIVE"})
+	err = vfsFile.CopyToFile(archived)
+
+# Parallel Downloads
+
+Options.DownloadPartitionSize enables parallel downloads of large objects.  Objects larger than it are fetched in byte
+ranges of that size, DownloadConcurrency (default 5) at a time, instead of through a single reader.  Reads download
+to their local temp file this way.  Copies to other file systems, such as os or mem, download straight to the target
+in order; targets implementing io.WriterAt have each range written at its offset.  Ranges are read from the
+generation of the object when the download started.  With a ChecksumAlgorithm the contents are still verified:
+
+	fs = fs.WithOptions(gs.Options{
+	    DownloadPartitionSize: 64 * 1024 * 1024,
+	    DownloadConcurrency:   8,
+	})
+
+# Versions
+
+In buckets with object versioning enabled, the generations of an object can be listed with the gs.File method
+Versions().  AtGeneration() returns a read-only File pinned to a generation, whose reads, Size, LastModified and copies
+use that generation, and RestoreGeneration() makes an older generation live again by copying it over the object:
+
+	file := vfsFile.(*gs.File)
+	versions, err := file.Versions()
+	...
+	old, err := file.AtGeneration(versions[1].Generation)
+	...
+	err = file.RestoreGeneration(versions[1].Generation)
+
+# Checksums
+
+Options.ChecksumAlgorithm (vfs.ChecksumCRC32C or vfs.ChecksumMD5) enables integrity checks.  Writes send the checksum
+of their contents and GCS rejects them if the bytes it receives don't match.  Reads verify the file's contents against
+the checksum stored by GCS, returning vfs.ErrChecksumMismatch once fully read.  Objects with gzip Content-Encoding
+aren't verified on read, since GCS decompresses them but stores the checksums of their compressed bytes.
+
+The gs.File method Checksum() returns the CRC32C or MD5 stored by GCS, or streams the object to compute other
+checksums:
+
+	sum, err := vfsFile.(*gs.File).Checksum(vfs.ChecksumCRC32C)
+
+# Preconditions
+
+Writers that mustn't overwrite each other's changes can use GCS preconditions.  The gs.File methods
+IfGenerationMatch, IfMetagenerationMatch and IfNotExist return a File for the same object whose writes, deletes and
+native copies onto it only succeed if the object is unchanged, or doesn't exist.  Otherwise a
+*vfs.PreconditionFailedError, matching vfs.ErrPreconditionFailed, is returned:
+
+	generation, _, err := gsFile.CurrentGeneration()
+	conditional := gsFile.IfGenerationMatch(generation)
+	_, err = conditional.Write(updated)
+	if err = conditional.Close(); errors.Is(err, vfs.ErrPreconditionFailed) {
+		// another writer got there first, re-read the file and retry
+	}
+
+# Concatenation
+
+Many files can be concatenated into one with GCS Compose, without downloading and re-uploading them.  The gs.File
+method ConcatenateFrom() composes sources in the file's bucket, chaining Compose requests through temporary objects
+for more than 32 sources, and streams other sources.  utils.Concatenate uses it for gs files and streams files of
+other file systems:
+
+	err := utils.Concatenate(output, shards...)
+
 # Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
@@ -0,0 +1,237 @@
+package gs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// defaultDownloadConcurrency is the number of byte ranges downloaded in parallel when DownloadConcurrency isn't set.
+const defaultDownloadConcurrency = 5
+
+// byteRange is a part of an object downloaded with one ranged read.
+type byteRange struct {
+	offset, length int64
+}
+
+// downloadRanges splits an object of size into parts of partSize, the last one shorter.
+func downloadRanges(size, partSize int64) []byteRange {
+	ranges := make([]byteRange, 0, (size+partSize-1)/partSize)
+	for offset := int64(0); offset < size; offset += partSize {
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		ranges = append(ranges, byteRange{offset: offset, length: length})
+	}
+	return ranges
+}
+
+// downloadPartitions returns the DownloadPartitionSize and DownloadConcurrency options, or a partition size of 0 if
+// parallel downloads are disabled.
+func (fs *FileSystem) downloadPartitions() (partSize int64, concurrency int) {
+	options, _ := fs.options.(Options)
+	concurrency = options.DownloadConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+	return options.DownloadPartitionSize, concurrency
+}
+
+// parallelRanges returns the ranges to download the object with attrs in parallel, or nil if it should be read at
+// once: parallel downloads are disabled, the object fits in one partition, or GCS decompresses it while reading.
+func (fs *FileSystem) parallelRanges(attrs *storage.ObjectAttrs) []byteRange {
+	partSize, _ := fs.downloadPartitions()
+	if partSize <= 0 || attrs.Size <= partSize || attrs.ContentEncoding == "gzip" {
+		return nil
+	}
+	return downloadRanges(attrs.Size, partSize)
+}
+
+// readRange writes a range of the object of handle to w, wrapped in a retry.  A failed attempt is retried from the
+// start of the range, so w is created for each attempt.
+func (f *File) readRange(ctx context.Context, handle *storage.ObjectHandle, r byteRange, w func() io.Writer) error {
+	return f.fileSystem.Retry()(func() error {
+		reader, err := handle.NewRangeReader(ctx, r.offset, r.length)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = reader.Close() }()
+		_, err = io.Copy(w(), reader)
+		return err
+	})
+}
+
+// downloadTo writes the file's contents to w.  If the file system has a DownloadPartitionSize, larger objects are
+// downloaded in parallel byte ranges: written at their offsets if w is an io.WriterAt and the contents aren't verified
+// against a checksum, otherwise in order.
+func (f *File) downloadTo(w io.Writer, bufferSize int) error {
+	if writerAt, ok := w.(io.WriterAt); ok && f.fileSystem.checksumAlgorithm() == "" {
+		if done, err := f.downloadAt(writerAt); done || err != nil {
+			return err
+		}
+	}
+
+	reader, err := f.newReader()
+	if err != nil {
+		return err
+	}
+	if err := utils.TouchCopyBuffered(w, reader, bufferSize); err != nil {
+		_ = reader.Close()
+		return err
+	}
+	return reader.Close()
+}
+
+// downloadAt writes the file's object to w in parallel byte ranges, returning false without downloading it if it
+// should be read at once.
+func (f *File) downloadAt(w io.WriterAt) (bool, error) {
+	attrs, err := f.getObjectAttrs()
+	if err != nil {
+		return false, err
+	}
+	ranges := f.fileSystem.parallelRanges(attrs)
+	if ranges == nil {
+		return false, nil
+	}
+	handle, err := f.generationHandle(attrs)
+	if err != nil {
+		return false, err
+	}
+
+	_, concurrency := f.fileSystem.downloadPartitions()
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue
+				}
+
+				r := ranges[i]
+				err := f.readRange(f.fileSystem.ctx, handle, r, func() io.Writer { return io.NewOffsetWriter(w, r.offset) })
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range ranges {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return true, firstErr
+}
+
+// generationHandle returns a handle of the object generation with attrs, so reads of it in several requests aren't
+// mixed with a newer generation written in between.
+func (f *File) generationHandle(attrs *storage.ObjectAttrs) (*storage.ObjectHandle, error) {
+	generation := &File{fileSystem: f.fileSystem, bucket: f.bucket, key: f.key, generation: attrs.Generation}
+	handle, err := generation.getObjectHandle()
+	if err != nil {
+		return nil, err
+	}
+	return handle.ObjectHandle(), nil
+}
+
+// rangePart is a downloaded range of an object, or the error downloading it.
+type rangePart struct {
+	data []byte
+	err  error
+}
+
+// parallelReader reads an object in order while downloading the next byte ranges in parallel.  At most concurrency
+// ranges are held in memory.
+type parallelReader struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	parts   chan chan rangePart
+	current *bytes.Reader
+	err     error
+}
+
+// newParallelReader starts downloading ranges of the object of handle, concurrency of them at a time.
+func (f *File) newParallelReader(handle *storage.ObjectHandle, ranges []byteRange, concurrency int) *parallelReader {
+	ctx, cancel := context.WithCancel(f.fileSystem.ctx)
+	r := &parallelReader{
+		ctx:     ctx,
+		cancel:  cancel,
+		parts:   make(chan chan rangePart, concurrency-1),
+		current: bytes.NewReader(nil),
+	}
+
+	go func() {
+		defer close(r.parts)
+		for _, br := range ranges {
+			part := make(chan rangePart, 1)
+			select {
+			case r.parts <- part:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(br byteRange) {
+				buffer := bytes.NewBuffer(make([]byte, 0, br.length))
+				err := f.readRange(ctx, handle, br, func() io.Writer {
+					buffer.Reset()
+					return buffer
+				})
+				part <- rangePart{data: buffer.Bytes(), err: err}
+			}(br)
+		}
+	}()
+	return r
+}
+
+// Read implements io.Reader, returning the downloaded ranges in order.
+func (r *parallelReader) Read(p []byte) (int, error) {
+	for r.current.Len() == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		part, ok := <-r.parts
+		if !ok {
+			// the ranges stop being queued early if the context is cancelled
+			r.err = r.ctx.Err()
+			if r.err == nil {
+				r.err = io.EOF
+			}
+			continue
+		}
+		result := <-part
+		if result.err != nil {
+			r.err = result.err
+			r.cancel()
+			continue
+		}
+		r.current = bytes.NewReader(result.data)
+	}
+	return r.current.Read(p)
+}
+
+// Close implements io.Closer, cancelling the downloads in progress.
+func (r *parallelReader) Close() error {
+	r.cancel()
+	return nil
+}
@@ -0,0 +1,128 @@
+package gs
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type downloadTestSuite struct {
+	suite.Suite
+	server   *fakestorage.Server
+	fs       *FileSystem
+	contents string
+}
+
+func (ts *downloadTestSuite) SetupTest() {
+	ts.contents = strings.Repeat("0123456789", 10) + "abc"
+	ts.server = fakestorage.NewServer([]fakestorage.Object{{
+		ObjectAttrs: fakestorage.ObjectAttrs{BucketName: "bucki", Name: "large.bin"},
+		Content:     []byte(ts.contents),
+	}})
+	ts.fs = NewFileSystem().WithClient(ts.server.Client())
+	ts.fs.options = Options{DownloadPartitionSize: 10, DownloadConcurrency: 3}
+}
+
+func (ts *downloadTestSuite) TearDownTest() {
+	ts.server.Stop()
+}
+
+func (ts *downloadTestSuite) newFile(name string) *File {
+	file, err := ts.fs.NewFile("bucki", name)
+	ts.Require().NoError(err)
+	return file.(*File)
+}
+
+func (ts *downloadTestSuite) TestDownloadRanges() {
+	ts.Equal([]byteRange{{0, 10}, {10, 10}, {20, 5}}, downloadRanges(25, 10))
+	ts.Equal([]byteRange{{0, 10}, {10, 10}}, downloadRanges(20, 10))
+	ts.Empty(downloadRanges(0, 10))
+}
+
+func (ts *downloadTestSuite) TestRead() {
+	file := ts.newFile("/large.bin")
+	contents, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal(ts.contents, string(contents))
+	ts.NoError(file.Close())
+
+	// ranges are read in order when verifying the contents
+	ts.fs.options = Options{DownloadPartitionSize: 10, ChecksumAlgorithm: vfs.ChecksumCRC32C}
+	contents, err = io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal(ts.contents, string(contents))
+	ts.NoError(file.Close())
+}
+
+func (ts *downloadTestSuite) TestCopyToFile() {
+	target, err := mem.NewFileSystem().NewFile("", "/large.bin")
+	ts.Require().NoError(err)
+	ts.Require().NoError(ts.newFile("/large.bin").CopyToFile(target))
+
+	contents, err := io.ReadAll(target)
+	ts.NoError(err)
+	ts.Equal(ts.contents, string(contents))
+}
+
+func (ts *downloadTestSuite) TestDownloadAt() {
+	w := &offsetsWriter{}
+	done, err := ts.newFile("/large.bin").downloadAt(w)
+	ts.NoError(err)
+	ts.True(done)
+	ts.Equal(ts.contents, w.buffer.String())
+	ts.Subset(w.offsets, []int64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, "each range is written at its offset")
+
+	// objects in a single partition are read at once
+	ts.fs.options = Options{DownloadPartitionSize: 1000}
+	done, err = ts.newFile("/large.bin").downloadAt(&offsetsWriter{})
+	ts.NoError(err)
+	ts.False(done)
+}
+
+func (ts *downloadTestSuite) TestParallelReaderError() {
+	file := ts.newFile("/missing.bin")
+	client, err := ts.fs.Client()
+	ts.Require().NoError(err)
+
+	reader := file.newParallelReader(client.Bucket("bucki").Object("missing.bin"), downloadRanges(25, 10), 2)
+	_, err = io.ReadAll(reader)
+	ts.Error(err)
+	ts.NoError(reader.Close())
+
+	// closing the reader stops the download
+	reader = ts.newFile("/large.bin").newParallelReader(client.Bucket("bucki").Object("large.bin"),
+		downloadRanges(103, 10), 2)
+	ts.NoError(reader.Close())
+	_, err = io.ReadAll(reader)
+	ts.Error(err)
+}
+
+// offsetsWriter is an io.WriterAt recording the offsets written at.
+type offsetsWriter struct {
+	mu      sync.Mutex
+	buffer  bytes.Buffer
+	offsets []int64
+}
+
+func (w *offsetsWriter) WriteAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.offsets = append(w.offsets, off)
+	if grow := int(off) + len(p) - w.buffer.Len(); grow > 0 {
+		w.buffer.Write(make([]byte, grow))
+	}
+	copy(w.buffer.Bytes()[off:], p)
+	return len(p), nil
+}
+
+func TestDownload(t *testing.T) {
+	suite.Run(t, new(downloadTestSuite))
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -25,11 +28,14 @@ const (
 
 // File implements vfs.File interface for GS fs.
 type File struct {
-	fileSystem  *FileSystem
-	bucket      string
-	key         string
-	tempFile    *os.File
-	writeBuffer *bytes.Buffer
+	fileSystem   *FileSystem
+	bucket       string
+	key          string
+	generation   int64
+	conditions   storage.Conditions
+	writeOptions WriteOptions
+	tempFile     *os.File
+	writeBuffer  *bytes.Buffer
 }
 
 // Close cleans up underlying mechanisms for reading from and writing to the file. Closes and removes the
@@ -48,20 +54,28 @@ func (f *File) Close() error {
 
 	if f.writeBuffer != nil {
 
-		handle, err := f.getObjectHandle()
+		handle, err := f.getConditionalObjectHandle()
 		if err != nil {
 			return err
 		}
 
 		ctx, cancel := context.WithCancel(f.fileSystem.ctx)
 		defer cancel()
-		w := handle.NewWriter(ctx)
-		defer func() { _ = w.Close() }()
+		w := f.newWriter(ctx, handle)
+		if algorithm := f.fileSystem.checksumAlgorithm(); algorithm != "" {
+			if err := setWriterChecksum(w, algorithm, f.writeBuffer.Bytes()); err != nil {
+				return err
+			}
+		}
 		buffer := make([]byte, utils.TouchCopyMinBufferSize)
 		if _, err := io.CopyBuffer(w, f.writeBuffer, buffer); err != nil {
 			// cancel context (replaces CloseWithError)
 			return err
 		}
+		// the upload, and its checksum verification, completes on close
+		if err := w.Close(); err != nil {
+			return f.preconditionError(err)
+		}
 	}
 
 	f.writeBuffer = nil
@@ -89,6 +103,10 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 // Write implements the standard for io.Writer. A buffer is added to with each subsequent
 // write. Calling Close() will write the contents back to GCS.
 func (f *File) Write(data []byte) (n int, err error) {
+	if f.generation != 0 {
+		return 0, ErrGenerationReadOnly
+	}
+
 	if f.writeBuffer == nil {
 		// note, initializing with 'data' and returning len(data), nil
 		// causes issues with some Write usages, notably csv.Writer
@@ -157,6 +175,10 @@ func (f *File) CopyToFile(file vfs.File) error {
 
 	// do native copy if same location/auth
 	if tf, ok := file.(*File); ok {
+		if tf.generation != 0 {
+			return ErrGenerationReadOnly
+		}
+
 		opts, ok := tf.Location().FileSystem().(*FileSystem).options.(Options)
 		if ok {
 			if f.isSameAuth(&opts) {
@@ -172,7 +194,12 @@ func (f *File) CopyToFile(file vfs.File) error {
 		fileBufferSize = opts.FileBufferSize
 	}
 
-	if err := utils.TouchCopyBuffered(file, f, fileBufferSize); err != nil {
+	// with parallel downloads, download straight to the target rather than to a local temp file first
+	if partSize, _ := f.fileSystem.downloadPartitions(); partSize > 0 {
+		if err := f.downloadTo(file, fileBufferSize); err != nil {
+			return err
+		}
+	} else if err := utils.TouchCopyBuffered(file, f, fileBufferSize); err != nil {
 		return err
 	}
 	// Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
@@ -225,13 +252,13 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 		}
 	}
 
-	handle, err := f.getObjectHandle()
+	handle, err := f.getConditionalObjectHandle()
 	if err != nil {
 		return err
 	}
 	err = handle.Delete(f.fileSystem.ctx)
 	if err != nil {
-		return err
+		return f.preconditionError(err)
 	}
 
 	if deleteAllVersions {
@@ -252,6 +279,9 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 // Touch creates a zero-length file on the vfs.File if no File exists.  Update File's last modified timestamp.
 // Returns error if unable to touch File.
 func (f *File) Touch() error {
+	if f.generation != 0 {
+		return ErrGenerationReadOnly
+	}
 
 	// check if file exists
 	exists, err := f.Exists()
@@ -328,7 +358,7 @@ func (f *File) isBucketVersioningEnabled() (bool, error) {
 	}
 	cctx, cancel := context.WithCancel(f.fileSystem.ctx)
 	defer cancel()
-	attrs, err := client.Bucket(f.bucket).Attrs(cctx)
+	attrs, err := f.fileSystem.bucket(client, f.bucket).Attrs(cctx)
 	if err != nil {
 		return false, err
 	}
@@ -337,7 +367,7 @@ func (f *File) isBucketVersioningEnabled() (bool, error) {
 
 func (f *File) createEmptyFile() error {
 
-	handle, err := f.getObjectHandle()
+	handle, err := f.getConditionalObjectHandle()
 	if err != nil {
 		return err
 	}
@@ -346,14 +376,12 @@ func (f *File) createEmptyFile() error {
 	ctx, cancel := context.WithCancel(f.fileSystem.ctx)
 	defer cancel()
 
-	w := handle.NewWriter(ctx)
-	defer func() { _ = w.Close() }()
+	w := f.newWriter(ctx, handle)
 	if _, err := w.Write(make([]byte, 0)); err != nil {
 		return err
 	}
 
-	// return early
-	return nil
+	return f.preconditionError(w.Close())
 }
 
 func (f *File) isSameAuth(opts *Options) bool {
@@ -366,12 +394,19 @@ func (f *File) isSameAuth(opts *Options) bool {
 		return false
 	}
 
-	fOptions := f.fileSystem.options.(Options)
+	fOptions, ok := f.fileSystem.options.(Options)
+	if !ok {
+		return false
+	}
 
 	if opts.CredentialFile != "" && opts.CredentialFile == fOptions.CredentialFile {
 		return true
 	}
 
+	if len(opts.CredentialsJSON) > 0 && bytes.Equal(opts.CredentialsJSON, fOptions.CredentialsJSON) {
+		return true
+	}
+
 	if opts.APIKey != "" && opts.APIKey == fOptions.APIKey {
 		return true
 	}
@@ -407,6 +442,36 @@ func (f *File) Name() string {
 	return path.Base(f.key)
 }
 
+// SignedURL returns a V4 signed URL to download (http.MethodGet) or upload (http.MethodPut) the file, valid for expiry,
+// implementing vfs.URLSigner.  Signing requires service account credentials, or permission to sign blobs with the IAM
+// credentials API.  V4 signed URLs are valid for at most 7 days.
+func (f *File) SignedURL(method string, expiry time.Duration) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  method,
+		Expires: time.Now().Add(expiry),
+	}
+
+	switch method {
+	case http.MethodGet:
+		if f.generation != 0 {
+			opts.QueryParameters = url.Values{"generation": {strconv.FormatInt(f.generation, 10)}}
+		}
+	case http.MethodPut:
+		if f.generation != 0 {
+			return "", ErrGenerationReadOnly
+		}
+	default:
+		return "", vfs.ErrSignedURLMethod
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return "", err
+	}
+	return f.fileSystem.bucket(client, f.bucket).SignedURL(utils.RemoveLeadingSlash(f.key), opts)
+}
+
 // URI returns a full GCS URI string of the file.
 func (f *File) URI() string {
 	return utils.GetFileURI(vfs.File(f))
@@ -429,35 +494,61 @@ func (f *File) copyToLocalTempReader() (*os.File, error) {
 		return nil, err
 	}
 
-	handle, err := f.getObjectHandle()
-	if err != nil {
+	if err := f.downloadTo(tmpFile, 0); err != nil {
+		if cerr := tmpFile.Close(); cerr != nil {
+			return nil, cerr
+		}
 		return nil, err
 	}
 
-	outputReader, err := handle.NewReader(f.fileSystem.ctx)
-	if err != nil {
+	// Return cursor to the beginning of the new temp file
+	if _, err := tmpFile.Seek(0, 0); err != nil {
 		return nil, err
 	}
 
-	buffer := make([]byte, utils.TouchCopyMinBufferSize)
-	if _, err := io.CopyBuffer(tmpFile, outputReader, buffer); err != nil {
-		return nil, err
-	}
+	// initialize temp ReadCloser
+	return tmpFile, nil
+}
 
-	if err := outputReader.Close(); err != nil {
-		if cerr := tmpFile.Close(); cerr != nil {
-			return nil, cerr
+// newReader opens a reader on the file's object, verifying its contents if the file system has a ChecksumAlgorithm, and
+// downloading large objects in parallel byte ranges if it has a DownloadPartitionSize.
+func (f *File) newReader() (io.ReadCloser, error) {
+	algorithm := f.fileSystem.checksumAlgorithm()
+	if partSize, _ := f.fileSystem.downloadPartitions(); algorithm == "" && partSize <= 0 {
+		handle, err := f.getObjectHandle()
+		if err != nil {
+			return nil, err
 		}
+		return handle.NewReader(f.fileSystem.ctx)
+	}
+	if algorithm != "" {
+		if _, err := utils.NewChecksumHash(algorithm); err != nil {
+			return nil, err
+		}
+	}
+
+	attrs, err := f.getObjectAttrs()
+	if err != nil {
+		return nil, err
+	}
+	// read the generation the attributes describe, in case the object is overwritten in between
+	handle, err := f.generationHandle(attrs)
+	if err != nil {
 		return nil, err
 	}
 
-	// Return cursor to the beginning of the new temp file
-	if _, err := tmpFile.Seek(0, 0); err != nil {
+	var reader io.ReadCloser
+	if ranges := f.fileSystem.parallelRanges(attrs); ranges != nil {
+		_, concurrency := f.fileSystem.downloadPartitions()
+		reader = f.newParallelReader(handle, ranges, concurrency)
+	} else if reader, err = handle.NewReader(f.fileSystem.ctx); err != nil {
 		return nil, err
 	}
 
-	// initialize temp ReadCloser
-	return tmpFile, nil
+	if algorithm == "" {
+		return reader, nil
+	}
+	return verifiedReader(reader, attrs, algorithm), nil
 }
 
 // getObjectHandle returns cached Object struct for file
@@ -467,7 +558,10 @@ func (f *File) getObjectHandle() (ObjectHandleCopier, error) {
 		return nil, err
 	}
 
-	handler := client.Bucket(f.bucket).Object(utils.RemoveLeadingSlash(f.key))
+	handler := f.fileSystem.encrypted(f.fileSystem.bucket(client, f.bucket).Object(utils.RemoveLeadingSlash(f.key)))
+	if f.generation != 0 {
+		handler = handler.Generation(f.generation)
+	}
 	return &RetryObjectHandler{Retry: f.fileSystem.Retry(), handler: handler}, nil
 }
 
@@ -478,7 +572,7 @@ func (f *File) getObjectGenerationHandles() ([]*storage.ObjectHandle, error) {
 	if err != nil {
 		return nil, err
 	}
-	it := client.Bucket(f.bucket).
+	it := f.fileSystem.bucket(client, f.bucket).
 		Objects(f.fileSystem.ctx, &storage.Query{Versions: true, Prefix: utils.RemoveLeadingSlash(f.key)})
 
 	for {
@@ -489,7 +583,7 @@ func (f *File) getObjectGenerationHandles() ([]*storage.ObjectHandle, error) {
 		if err != nil {
 			return nil, err
 		}
-		handle := client.Bucket(attrs.Bucket).Object(attrs.Name).Generation(attrs.Generation)
+		handle := f.fileSystem.bucket(client, attrs.Bucket).Object(attrs.Name).Generation(attrs.Generation)
 		handles = append(handles, handle)
 	}
 	return handles, err
@@ -505,7 +599,7 @@ func (f *File) getObjectAttrs() (*storage.ObjectAttrs, error) {
 }
 
 func (f *File) copyWithinGCSToFile(targetFile *File) error {
-	tHandle, err := targetFile.getObjectHandle()
+	tHandle, err := targetFile.getConditionalObjectHandle()
 	if err != nil {
 		return err
 	}
@@ -520,8 +614,11 @@ func (f *File) copyWithinGCSToFile(targetFile *File) error {
 		return gerr
 	}
 	copier.ContentType(attrs.ContentType)
+	if err := targetFile.setCopierAttrs(copier, attrs); err != nil {
+		return err
+	}
 
 	// Just copy content.
 	_, cerr := copier.Run(f.fileSystem.ctx)
-	return cerr
+	return targetFile.preconditionError(cerr)
 }
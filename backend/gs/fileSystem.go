@@ -31,6 +31,21 @@ func (fs *FileSystem) Retry() vfs.Retry {
 	return vfs.DefaultRetryer()
 }
 
+// checksumAlgorithm returns the ChecksumAlgorithm option, or an empty string if none is provided.
+func (fs *FileSystem) checksumAlgorithm() vfs.ChecksumAlgorithm {
+	options, _ := fs.options.(Options)
+	return options.ChecksumAlgorithm
+}
+
+// bucket returns a handle of the named bucket, billing its requests to the UserProject option if one is provided.
+func (fs *FileSystem) bucket(client *storage.Client, name string) *storage.BucketHandle {
+	handle := client.Bucket(name)
+	if options, _ := fs.options.(Options); options.UserProject != "" {
+		handle = handle.UserProject(options.UserProject)
+	}
+	return handle
+}
+
 // NewFile function returns the gcs implementation of vfs.File.
 func (fs *FileSystem) NewFile(volume, name string) (vfs.File, error) {
 	if fs == nil {
@@ -82,7 +97,10 @@ func (fs *FileSystem) Scheme() string {
 // See Overview for authentication resolution
 func (fs *FileSystem) Client() (*storage.Client, error) {
 	if fs.client == nil {
-		gsClientOpts := parseClientOptions(fs.options)
+		gsClientOpts, err := parseClientOptions(fs.options)
+		if err != nil {
+			return nil, err
+		}
 		client, err := storage.NewClient(fs.ctx, gsClientOpts...)
 		if err != nil {
 			return nil, err
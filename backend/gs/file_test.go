@@ -3,15 +3,26 @@ package gs
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/fsouza/fake-gcs-server/fakestorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
 
+	"github.com/c2fo/vfs/v6"
 	"github.com/c2fo/vfs/v6/options/delete"
 	"github.com/c2fo/vfs/v6/utils"
 )
@@ -508,6 +519,55 @@ func (ts *fileTestSuite) TestMoveAndCopyBuffered() {
 	}
 }
 
+func (ts *fileTestSuite) TestSignedURL() {
+	ts.Implements((*vfs.URLSigner)(nil), &File{}, "Does not implement the vfs.URLSigner interface")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	ts.Require().NoError(err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "signer@project.iam.gserviceaccount.com",
+		"private_key": string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})),
+	})
+	ts.Require().NoError(err)
+	client, err := storage.NewClient(context.Background(), option.WithCredentialsJSON(credentials))
+	ts.Require().NoError(err)
+
+	fs := NewFileSystem().WithClient(client)
+	file, err := fs.NewFile("bucki", "/some/path/file.txt")
+	ts.Require().NoError(err)
+
+	signed, err := utils.SignedURL(file, http.MethodGet, time.Hour)
+	ts.Require().NoError(err)
+	u, err := url.Parse(signed)
+	ts.Require().NoError(err)
+	ts.Equal("/bucki/some/path/file.txt", u.Path)
+	expires, err := strconv.Atoi(u.Query().Get("X-Goog-Expires"))
+	ts.Require().NoError(err)
+	ts.InDelta(3600, expires, 1)
+	ts.Contains(u.Query().Get("X-Goog-Credential"), "signer@project.iam.gserviceaccount.com")
+	ts.NotEmpty(u.Query().Get("X-Goog-Signature"))
+
+	_, err = file.(*File).SignedURL(http.MethodPut, time.Minute)
+	ts.NoError(err)
+
+	generation, err := file.(*File).AtGeneration(123)
+	ts.Require().NoError(err)
+	signed, err = generation.SignedURL(http.MethodGet, time.Hour)
+	ts.Require().NoError(err)
+	ts.Contains(signed, "generation=123")
+	_, err = generation.SignedURL(http.MethodPut, time.Hour)
+	ts.ErrorIs(err, ErrGenerationReadOnly)
+
+	_, err = file.(*File).SignedURL(http.MethodDelete, time.Hour)
+	ts.ErrorIs(err, vfs.ErrSignedURLMethod)
+	_, err = file.(*File).SignedURL(http.MethodGet, 8*24*time.Hour)
+	ts.Error(err, "V4 signed URLs are valid for at most 7 days")
+}
+
 func TestFile(t *testing.T) {
 	suite.Run(t, new(fileTestSuite))
 }
@@ -201,7 +201,7 @@ func (l *Location) getBucketHandle() (BucketHandleWrapper, error) {
 	if err != nil {
 		return nil, err
 	}
-	handler := &RetryBucketHandler{Retry: l.fileSystem.Retry(), handler: client.Bucket(l.bucket)}
+	handler := &RetryBucketHandler{Retry: l.fileSystem.Retry(), handler: l.fileSystem.bucket(client, l.bucket)}
 	l.bucketHandle = handler
 	return l.bucketHandle, nil
 }
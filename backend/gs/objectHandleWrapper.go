@@ -36,6 +36,15 @@ type CopierWrapper interface {
 	ContentType(string)
 }
 
+// CopierAttrsWrapper is implemented by CopierWrappers that can set the storage class, KMS key and retention of the
+// destination object, which copies need to apply write options and keep the source object's.  Copier implements it.
+type CopierAttrsWrapper interface {
+	CopierWrapper
+	StorageClass(string)
+	DestinationKMSKeyName(string)
+	Retention(*storage.ObjectRetention)
+}
+
 // RetryObjectHandler implements the ObjectHandleCopier interface (which also is composed with ObjectHandleWrapper)
 type RetryObjectHandler struct {
 	Retry   vfs.Retry
@@ -116,6 +125,21 @@ func (c *Copier) ContentType(val string) {
 	c.copier.ContentType = val
 }
 
+// StorageClass is the storage class of the destination object.
+func (c *Copier) StorageClass(val string) {
+	c.copier.StorageClass = val
+}
+
+// DestinationKMSKeyName is the Cloud KMS key encrypting the destination object.
+func (c *Copier) DestinationKMSKeyName(val string) {
+	c.copier.DestinationKMSKeyName = val
+}
+
+// Retention is the retention configuration of the destination object.
+func (c *Copier) Retention(val *storage.ObjectRetention) {
+	c.copier.Retention = val
+}
+
 // Run performs the copy, wrapped in a retry
 func (c *Copier) Run(ctx context.Context) (*storage.ObjectAttrs, error) {
 	return objectAttributeRetry(c.Retry, func() (*storage.ObjectAttrs, error) {
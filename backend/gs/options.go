@@ -1,36 +1,93 @@
 package gs
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
 	"google.golang.org/api/option"
 
 	"github.com/c2fo/vfs/v6"
 )
 
-// Options holds Google Cloud Storage -specific options.  Currently only client options are used.
+// Options holds Google Cloud Storage -specific options.  The client options are combined when the client is created;
+// at most one way of authenticating can be set.
 type Options struct {
-	APIKey         string   `json:"apiKey,omitempty"`
-	CredentialFile string   `json:"credentialFilePath,omitempty"`
-	Endpoint       string   `json:"endpoint,omitempty"`
-	Scopes         []string `json:"WithoutAuthentication,omitempty"`
+	// APIKey authenticates requests with an API key.
+	APIKey string `json:"apiKey,omitempty"`
+	// CredentialFile is the path of a JSON credentials file, such as a service account key.
+	CredentialFile string `json:"credentialFilePath,omitempty"`
+	// CredentialsJSON holds the contents of a JSON credentials file.
+	CredentialsJSON []byte `json:"credentialsJSON,omitempty"`
+	// WithoutAuthentication sends unauthenticated requests, for public buckets or emulators such as fake-gcs-server.
+	WithoutAuthentication bool `json:"withoutAuthentication,omitempty"`
+	// HTTPClient is used as is to send requests, and must authenticate them itself.
+	HTTPClient *http.Client `json:"-"`
+	// Endpoint overrides the GCS JSON API endpoint, e.g. "http://localhost:4443/storage/v1/" for fake-gcs-server.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Scopes are the OAuth2 scopes requested for credentials, ScopeFullControl by default.
+	Scopes []string `json:"scopes,omitempty"`
+	// UserProject is the project billed for requests to requester pays buckets.
+	UserProject    string `json:"userProject,omitempty"`
 	Retry          vfs.Retry
 	FileBufferSize int // Buffer Size In Bytes Used with utils.TouchCopyBuffered
+	// DownloadPartitionSize enables parallel downloads: objects larger than it are downloaded in byte ranges of that
+	// many bytes, DownloadConcurrency (default 5) at a time, by reads and by copies to other file systems.
+	DownloadPartitionSize int64
+	DownloadConcurrency   int
+	// ChecksumAlgorithm, vfs.ChecksumCRC32C or vfs.ChecksumMD5, enables integrity checks.  Writes send the checksum of
+	// their contents for GCS to verify, and reads verify the file's contents against the checksum stored by GCS.
+	ChecksumAlgorithm vfs.ChecksumAlgorithm `json:"checksumAlgorithm,omitempty"`
+	// CustomerSuppliedKey is a 32-byte AES-256 key encrypting the objects written, and decrypting the objects read.
+	CustomerSuppliedKey []byte `json:"customerSuppliedKey,omitempty"`
+	// WriteOptions are the attributes of the objects created, which files can override with File.WithWriteOptions.
+	WriteOptions
 }
 
-func parseClientOptions(opts vfs.Options) []option.ClientOption {
+// parseClientOptions returns the client options of opts, combined.  An error is returned for options that the client
+// would otherwise silently ignore: more than one way of authenticating, or Scopes without credentials to apply them to.
+func parseClientOptions(opts vfs.Options) ([]option.ClientOption, error) {
 	var googleClientOpts []option.ClientOption
 
 	// we only care about 'gs.Options' types, skip anything else
-	if opts, ok := opts.(Options); ok {
-		switch {
-		case opts.APIKey != "":
-			googleClientOpts = append(googleClientOpts, option.WithAPIKey(opts.APIKey))
-		case opts.CredentialFile != "":
-			googleClientOpts = append(googleClientOpts, option.WithCredentialsFile(opts.CredentialFile))
-		case opts.Endpoint != "":
-			googleClientOpts = append(googleClientOpts, option.WithEndpoint(opts.Endpoint))
-		case len(opts.Scopes) > 0:
-			googleClientOpts = append(googleClientOpts, option.WithScopes(opts.Scopes...))
+	opt, ok := opts.(Options)
+	if !ok {
+		return googleClientOpts, nil
+	}
+
+	var auth []string
+	if opt.APIKey != "" {
+		auth = append(auth, "APIKey")
+		googleClientOpts = append(googleClientOpts, option.WithAPIKey(opt.APIKey))
+	}
+	if opt.CredentialFile != "" {
+		auth = append(auth, "CredentialFile")
+		googleClientOpts = append(googleClientOpts, option.WithCredentialsFile(opt.CredentialFile))
+	}
+	if len(opt.CredentialsJSON) > 0 {
+		auth = append(auth, "CredentialsJSON")
+		googleClientOpts = append(googleClientOpts, option.WithCredentialsJSON(opt.CredentialsJSON))
+	}
+	if opt.WithoutAuthentication {
+		auth = append(auth, "WithoutAuthentication")
+		googleClientOpts = append(googleClientOpts, option.WithoutAuthentication())
+	}
+	if opt.HTTPClient != nil {
+		auth = append(auth, "HTTPClient")
+		googleClientOpts = append(googleClientOpts, option.WithHTTPClient(opt.HTTPClient))
+	}
+	if len(auth) > 1 {
+		return nil, fmt.Errorf("gs options %s can't be combined, set only one", strings.Join(auth, ", "))
+	}
+
+	if len(opt.Scopes) > 0 {
+		if len(auth) == 1 && auth[0] != "CredentialFile" && auth[0] != "CredentialsJSON" {
+			return nil, fmt.Errorf("gs option Scopes can't be combined with %s", auth[0])
 		}
+		googleClientOpts = append(googleClientOpts, option.WithScopes(opt.Scopes...))
+	}
+	if opt.Endpoint != "" {
+		googleClientOpts = append(googleClientOpts, option.WithEndpoint(opt.Endpoint))
 	}
-	return googleClientOpts
+	return googleClientOpts, nil
 }
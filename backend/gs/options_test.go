@@ -0,0 +1,339 @@
+package gs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+)
+
+type optionsTestSuite struct {
+	suite.Suite
+	fake     *fakestorage.Server
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+// SetupTest serves fake-gcs-server from a test server recording the requests it receives, and a token endpoint for
+// service account credentials.
+func (ts *optionsTestSuite) SetupTest() {
+	fake, err := fakestorage.NewServerWithOptions(fakestorage.Options{NoListener: true, PublicHost: "127.0.0.1"})
+	ts.Require().NoError(err)
+	fake.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "bucki"})
+	ts.fake = fake
+	ts.requests = nil
+	ts.bodies = nil
+
+	ts.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		ts.NoError(err)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		ts.mu.Lock()
+		ts.requests = append(ts.requests, r.Clone(r.Context()))
+		ts.bodies = append(ts.bodies, string(body))
+		ts.mu.Unlock()
+		fake.HTTPHandler().ServeHTTP(w, r)
+	}))
+}
+
+func (ts *optionsTestSuite) TearDownTest() {
+	ts.server.Close()
+	ts.fake.Stop()
+}
+
+func (ts *optionsTestSuite) endpoint() string {
+	return ts.server.URL + "/storage/v1/"
+}
+
+// roundTrip writes and reads back a file with a file system using opts.
+func (ts *optionsTestSuite) roundTrip(opts Options) {
+	fs := NewFileSystem().WithOptions(opts)
+	file, err := fs.NewFile("bucki", "/options/file.txt")
+	ts.Require().NoError(err)
+
+	_, err = file.Write([]byte("hello"))
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+
+	contents, err := io.ReadAll(file)
+	ts.Require().NoError(err)
+	ts.Equal("hello", string(contents))
+	ts.NotEmpty(ts.requests, "requests should be sent to the endpoint")
+}
+
+// serviceAccountJSON returns the credentials JSON of a service account whose tokens are issued by the test server.
+func (ts *optionsTestSuite) serviceAccountJSON() []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	ts.Require().NoError(err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "test-project",
+		"private_key_id": "test-key",
+		"private_key":    string(keyPEM),
+		"client_email":   "vfs@test-project.iam.gserviceaccount.com",
+		"client_id":      "1234",
+		"token_uri":      ts.server.URL + "/token",
+	})
+	ts.Require().NoError(err)
+	return credentials
+}
+
+func (ts *optionsTestSuite) TestEndpointWithoutAuthentication() {
+	ts.roundTrip(Options{Endpoint: ts.endpoint(), WithoutAuthentication: true})
+	for _, r := range ts.requests {
+		ts.Empty(r.Header.Get("Authorization"), "requests should be unauthenticated")
+	}
+}
+
+func (ts *optionsTestSuite) TestEndpointWithCredentialsJSON() {
+	ts.roundTrip(Options{
+		Endpoint:        ts.endpoint(),
+		CredentialsJSON: ts.serviceAccountJSON(),
+		Scopes:          []string{"https://www.googleapis.com/auth/devstorage.read_write"},
+	})
+	for _, r := range ts.requests {
+		ts.True(strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "),
+			"the endpoint shouldn't drop the credentials, got %q", r.Header.Get("Authorization"))
+	}
+}
+
+func (ts *optionsTestSuite) TestEndpointWithHTTPClient() {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("X-Client", "custom")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	ts.roundTrip(Options{Endpoint: ts.endpoint(), HTTPClient: client})
+	for _, r := range ts.requests {
+		ts.Equal("custom", r.Header.Get("X-Client"), "requests should be sent with the HTTPClient")
+	}
+}
+
+func (ts *optionsTestSuite) TestUserProject() {
+	ts.roundTrip(Options{Endpoint: ts.endpoint(), WithoutAuthentication: true, UserProject: "billing-project"})
+	for _, r := range ts.requests {
+		// JSON API requests name the user project in the query, XML API reads in a header
+		userProject := r.URL.Query().Get("userProject")
+		if userProject == "" {
+			userProject = r.Header.Get("X-Goog-User-Project")
+		}
+		ts.Equal("billing-project", userProject, "%s %s should bill the user project", r.Method, r.URL)
+	}
+}
+
+func (ts *optionsTestSuite) TestParseClientOptions() {
+	tests := []struct {
+		name    string
+		opts    Options
+		count   int
+		wantErr string
+	}{
+		{name: "none", opts: Options{}, count: 0},
+		{
+			name:  "credential file, scopes and endpoint",
+			opts:  Options{CredentialFile: "/creds.json", Scopes: []string{"read"}, Endpoint: "http://localhost/storage/v1/"},
+			count: 3,
+		},
+		{name: "api key and endpoint", opts: Options{APIKey: "key", Endpoint: "http://localhost/storage/v1/"}, count: 2},
+		{name: "without authentication", opts: Options{WithoutAuthentication: true}, count: 1},
+		{
+			name:    "two credentials",
+			opts:    Options{CredentialFile: "/creds.json", CredentialsJSON: []byte("{}")},
+			wantErr: "gs options CredentialFile, CredentialsJSON can't be combined, set only one",
+		},
+		{
+			name:    "credentials without authentication",
+			opts:    Options{APIKey: "key", WithoutAuthentication: true},
+			wantErr: "gs options APIKey, WithoutAuthentication can't be combined, set only one",
+		},
+		{
+			name:    "scopes without credentials",
+			opts:    Options{HTTPClient: http.DefaultClient, Scopes: []string{"read"}},
+			wantErr: "gs option Scopes can't be combined with HTTPClient",
+		},
+	}
+	for _, tt := range tests {
+		ts.Run(tt.name, func() {
+			clientOpts, err := parseClientOptions(tt.opts)
+			if tt.wantErr != "" {
+				ts.EqualError(err, tt.wantErr)
+				return
+			}
+			ts.NoError(err)
+			ts.Len(clientOpts, tt.count)
+		})
+	}
+
+	clientOpts, err := parseClientOptions(struct{}{})
+	ts.NoError(err)
+	ts.Empty(clientOpts, "other option types are ignored")
+}
+
+func (ts *optionsTestSuite) TestClientError() {
+	fs := NewFileSystem().WithOptions(Options{APIKey: "key", CredentialFile: "/creds.json"})
+	_, err := fs.Client()
+	ts.EqualError(err, "gs options APIKey, CredentialFile can't be combined, set only one")
+}
+
+func (ts *optionsTestSuite) TestOptionsJSON() {
+	var opts Options
+	ts.Require().NoError(json.Unmarshal([]byte(`{"endpoint":"http://localhost/","scopes":["read"],"withoutAuthentication":true,`+
+		`"userProject":"billing-project"}`), &opts))
+	ts.Equal(Options{
+		Endpoint:              "http://localhost/",
+		Scopes:                []string{"read"},
+		WithoutAuthentication: true,
+		UserProject:           "billing-project",
+	}, opts)
+}
+
+// request returns the last recorded request whose method and path match, and its body.
+func (ts *optionsTestSuite) request(method, pathPart string) (*http.Request, string) {
+	for i := len(ts.requests) - 1; i >= 0; i-- {
+		if r := ts.requests[i]; r.Method == method && strings.Contains(r.URL.Path, pathPart) {
+			return r, ts.bodies[i]
+		}
+	}
+	ts.Failf("request not found", "no %s request to %s", method, pathPart)
+	return &http.Request{URL: &url.URL{}}, ""
+}
+
+func (ts *optionsTestSuite) TestWriteOptions() {
+	fs := NewFileSystem().WithOptions(Options{
+		Endpoint:              ts.endpoint(),
+		WithoutAuthentication: true,
+		WriteOptions: WriteOptions{
+			KMSKeyName:      "projects/p/locations/us/keyRings/r/cryptoKeys/k",
+			StorageClass:    "NEARLINE",
+			RetentionMode:   "Unlocked",
+			RetentionPeriod: time.Hour,
+		},
+	})
+	file, err := fs.NewFile("bucki", "/archive/report.csv")
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte("a,b,c"))
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+
+	r, body := ts.request(http.MethodPost, "/upload/storage/v1/b/bucki/o")
+	ts.Equal("projects/p/locations/us/keyRings/r/cryptoKeys/k", r.URL.Query().Get("kmsKeyName"))
+	ts.Contains(body, `"storageClass":"NEARLINE"`)
+	ts.Contains(body, `"mode":"Unlocked"`)
+
+	// file write options override the file system's
+	touched, err := fs.NewFile("bucki", "/archive/touched.csv")
+	ts.Require().NoError(err)
+	ts.Require().NoError(touched.(*File).WithWriteOptions(WriteOptions{StorageClass: "ARCHIVE"}).Touch())
+	r, body = ts.request(http.MethodPost, "/upload/storage/v1/b/bucki/o")
+	ts.Equal("projects/p/locations/us/keyRings/r/cryptoKeys/k", r.URL.Query().Get("kmsKeyName"))
+	ts.Contains(body, `"storageClass":"ARCHIVE"`)
+}
+
+func (ts *optionsTestSuite) TestCustomerSuppliedKey() {
+	key := bytes.Repeat([]byte{7}, 32)
+	ts.roundTrip(Options{Endpoint: ts.endpoint(), WithoutAuthentication: true, CustomerSuppliedKey: key})
+	for _, r := range ts.requests {
+		ts.Equal("AES256", r.Header.Get("X-Goog-Encryption-Algorithm"), "%s %s should send the key", r.Method, r.URL)
+	}
+}
+
+func (ts *optionsTestSuite) TestCopyWriteOptions() {
+	// files of file systems with the same API key are copied natively
+	fs := NewFileSystem().WithOptions(Options{Endpoint: ts.endpoint(), APIKey: "key"})
+	source, err := fs.NewFile("bucki", "/source.txt")
+	ts.Require().NoError(err)
+	_, err = source.Write([]byte("hello"))
+	ts.Require().NoError(err)
+	ts.Require().NoError(source.Close())
+
+	// attributes preserved from the source are covered by TestSetCopierAttrs, fake-gcs-server doesn't store them
+	target, err := fs.NewFile("bucki", "/overridden.txt")
+	ts.Require().NoError(err)
+	overridden := target.(*File).WithWriteOptions(WriteOptions{
+		KMSKeyName:   "projects/p/locations/us/keyRings/r/cryptoKeys/k",
+		StorageClass: "COLDLINE",
+	})
+	ts.Require().NoError(source.CopyToFile(overridden))
+	r, body := ts.request(http.MethodPost, "/rewriteTo/")
+	ts.Contains(body, `"contentType":"text/plain; charset=utf-8"`)
+	ts.Equal("projects/p/locations/us/keyRings/r/cryptoKeys/k", r.URL.Query().Get("destinationKmsKeyName"))
+	ts.Contains(body, `"storageClass":"COLDLINE"`)
+}
+
+func (ts *optionsTestSuite) TestSetCopierAttrs() {
+	retainUntil := time.Now().Add(24 * time.Hour)
+	attrs := &storage.ObjectAttrs{
+		StorageClass: "NEARLINE",
+		KMSKeyName:   "projects/p/locations/us/keyRings/r/cryptoKeys/k/cryptoKeyVersions/3",
+		Retention:    &storage.ObjectRetention{Mode: "Locked", RetainUntil: retainUntil},
+	}
+
+	file := &File{fileSystem: NewFileSystem(), bucket: "bucki", key: "/copy.txt"}
+	copier := &Copier{copier: &storage.Copier{}}
+	ts.NoError(file.setCopierAttrs(copier, attrs))
+	ts.Equal("NEARLINE", copier.copier.StorageClass)
+	ts.Equal("projects/p/locations/us/keyRings/r/cryptoKeys/k", copier.copier.DestinationKMSKeyName)
+	ts.Equal(attrs.Retention, copier.copier.Retention)
+
+	file = file.WithWriteOptions(WriteOptions{StorageClass: "ARCHIVE", RetentionMode: "Unlocked", RetentionPeriod: time.Hour})
+	copier = &Copier{copier: &storage.Copier{}}
+	ts.NoError(file.setCopierAttrs(copier, attrs))
+	ts.Equal("ARCHIVE", copier.copier.StorageClass)
+	ts.Equal("Unlocked", copier.copier.Retention.Mode)
+	ts.WithinDuration(time.Now().Add(time.Hour), copier.copier.Retention.RetainUntil, time.Minute)
+
+	// copies encrypted with a customer-supplied key can't also name a KMS key
+	file = &File{fileSystem: NewFileSystem().WithOptions(Options{CustomerSuppliedKey: make([]byte, 32)}), key: "/copy.txt"}
+	copier = &Copier{copier: &storage.Copier{}}
+	ts.NoError(file.setCopierAttrs(copier, attrs))
+	ts.Empty(copier.copier.DestinationKMSKeyName)
+
+	// copiers that can't set the attributes copy with GCS's defaults, unless write options need them
+	ts.NoError(file.setCopierAttrs(contentTypeCopier{}, attrs))
+	file = file.WithWriteOptions(WriteOptions{StorageClass: "ARCHIVE"})
+	ts.EqualError(file.setCopierAttrs(contentTypeCopier{}, attrs),
+		"gs write options can't be applied to a copy by gs.contentTypeCopier, which doesn't implement gs.CopierAttrsWrapper")
+}
+
+// contentTypeCopier is a CopierWrapper that doesn't implement CopierAttrsWrapper.
+type contentTypeCopier struct{}
+
+func (contentTypeCopier) Run(context.Context) (*storage.ObjectAttrs, error) {
+	return &storage.ObjectAttrs{}, nil
+}
+
+func (contentTypeCopier) ContentType(string) {}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestOptions(t *testing.T) {
+	suite.Run(t, new(optionsTestSuite))
+}
@@ -0,0 +1,91 @@
+package gs
+
+import (
+	"errors"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// CurrentGeneration returns the generation and metageneration of the file's live object, to pass to
+// IfGenerationMatch and IfMetagenerationMatch.
+func (f *File) CurrentGeneration() (generation, metageneration int64, err error) {
+	attrs, err := f.getObjectAttrs()
+	if err != nil {
+		return 0, 0, err
+	}
+	return attrs.Generation, attrs.Metageneration, nil
+}
+
+// IfGenerationMatch returns a File for the same object whose writes, deletes and native copies onto it only succeed if
+// the object's live generation is still generation.  Otherwise a *vfs.PreconditionFailedError is returned.
+func (f *File) IfGenerationMatch(generation int64) *File {
+	file := f.withConditions()
+	file.conditions.GenerationMatch = generation
+	file.conditions.DoesNotExist = false
+	return file
+}
+
+// IfMetagenerationMatch returns a File for the same object whose writes, deletes and native copies onto it only
+// succeed if the object's metageneration is still metageneration.  Otherwise a *vfs.PreconditionFailedError is
+// returned.
+func (f *File) IfMetagenerationMatch(metageneration int64) *File {
+	file := f.withConditions()
+	file.conditions.MetagenerationMatch = metageneration
+	return file
+}
+
+// IfNotExist returns a File for the same object whose writes and native copies onto it only succeed if the object
+// doesn't exist.  Otherwise a *vfs.PreconditionFailedError is returned.
+func (f *File) IfNotExist() *File {
+	file := f.withConditions()
+	file.conditions.DoesNotExist = true
+	file.conditions.GenerationMatch = 0
+	return file
+}
+
+// withConditions returns a copy of the file, without its pending reads and writes, keeping its conditions and write
+// options.
+func (f *File) withConditions() *File {
+	return &File{
+		fileSystem:   f.fileSystem,
+		bucket:       f.bucket,
+		key:          f.key,
+		generation:   f.generation,
+		conditions:   f.conditions,
+		writeOptions: f.writeOptions,
+	}
+}
+
+// getConditionalObjectHandle returns an object handle for writing or deleting the file's object, subject to the
+// file's conditions.
+func (f *File) getConditionalObjectHandle() (ObjectHandleCopier, error) {
+	if f.conditions == (storage.Conditions{}) {
+		return f.getObjectHandle()
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	handler := f.fileSystem.encrypted(f.fileSystem.bucket(client, f.bucket).Object(utils.RemoveLeadingSlash(f.key)))
+	if f.generation != 0 {
+		handler = handler.Generation(f.generation)
+	}
+	handler = handler.If(f.conditions)
+	return &RetryObjectHandler{Retry: f.fileSystem.Retry(), handler: handler}, nil
+}
+
+// preconditionError returns a *vfs.PreconditionFailedError if err is GCS rejecting a request because of the file's
+// conditions, otherwise err.
+func (f *File) preconditionError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return &vfs.PreconditionFailedError{URI: f.URI(), Err: err}
+	}
+	return err
+}
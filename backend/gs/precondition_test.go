@@ -0,0 +1,102 @@
+package gs
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/googleapi"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type preconditionTestSuite struct {
+	suite.Suite
+	server *fakestorage.Server
+	fs     *FileSystem
+}
+
+func (ts *preconditionTestSuite) SetupTest() {
+	ts.server = fakestorage.NewServer(Objects{})
+	ts.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "bucki"})
+	ts.fs = NewFileSystem().WithClient(ts.server.Client())
+}
+
+func (ts *preconditionTestSuite) TearDownTest() {
+	ts.server.Stop()
+}
+
+func (ts *preconditionTestSuite) write(f *File, contents string) error {
+	if _, err := f.Write([]byte(contents)); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (ts *preconditionTestSuite) TestIfGenerationMatch() {
+	file, err := ts.fs.NewFile("bucki", "/jobs/state.json")
+	ts.Require().NoError(err)
+	ts.Require().NoError(ts.write(file.(*File), "v1"))
+
+	generation, _, err := file.(*File).CurrentGeneration()
+	ts.Require().NoError(err)
+	ts.NotZero(generation)
+
+	// the first worker to write wins
+	first := file.(*File).IfGenerationMatch(generation)
+	second := file.(*File).IfGenerationMatch(generation)
+	ts.NoError(ts.write(first, "v2 from first"))
+
+	err = ts.write(second, "v2 from second")
+	ts.ErrorIs(err, vfs.ErrPreconditionFailed)
+	var preconditionErr *vfs.PreconditionFailedError
+	ts.Require().ErrorAs(err, &preconditionErr)
+	ts.Equal("gs://bucki/jobs/state.json", preconditionErr.URI)
+
+	contents, err := ts.fs.NewFile("bucki", "/jobs/state.json")
+	ts.Require().NoError(err)
+	data := make([]byte, 32)
+	n, _ := contents.Read(data)
+	ts.Equal("v2 from first", string(data[:n]))
+}
+
+func (ts *preconditionTestSuite) TestIfNotExist() {
+	file, err := ts.fs.NewFile("bucki", "/jobs/lock")
+	ts.Require().NoError(err)
+
+	ts.NoError(ts.write(file.(*File).IfNotExist(), "worker-1"))
+	ts.ErrorIs(ts.write(file.(*File).IfNotExist(), "worker-2"), vfs.ErrPreconditionFailed)
+
+	// conditions don't carry over to the original file
+	ts.NoError(ts.write(file.(*File), "worker-3"))
+}
+
+func (ts *preconditionTestSuite) TestConditions() {
+	file := &File{fileSystem: ts.fs, bucket: "bucki", key: "/file.txt"}
+
+	conditional := file.IfGenerationMatch(5).IfMetagenerationMatch(2)
+	ts.Equal(int64(5), conditional.conditions.GenerationMatch)
+	ts.Equal(int64(2), conditional.conditions.MetagenerationMatch)
+	ts.Zero(file.conditions, "the original file should be unconditional")
+
+	conditional = conditional.IfNotExist()
+	ts.True(conditional.conditions.DoesNotExist)
+	ts.Zero(conditional.conditions.GenerationMatch, "DoesNotExist and GenerationMatch are exclusive")
+}
+
+func (ts *preconditionTestSuite) TestPreconditionError() {
+	file := &File{fileSystem: ts.fs, bucket: "bucki", key: "/file.txt"}
+
+	err := file.preconditionError(&googleapi.Error{Code: http.StatusPreconditionFailed})
+	ts.ErrorIs(err, vfs.ErrPreconditionFailed)
+
+	other := errors.New("boom")
+	ts.Equal(other, file.preconditionError(other))
+	ts.NoError(file.preconditionError(nil))
+}
+
+func TestPrecondition(t *testing.T) {
+	suite.Run(t, new(preconditionTestSuite))
+}
@@ -0,0 +1,98 @@
+package gs
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// ErrGenerationReadOnly is returned when attempting to write to a File that refers to a specific object generation.
+var ErrGenerationReadOnly = errors.New("gs file refers to a specific object generation and can't be written")
+
+// ObjectVersion describes a generation of a GCS object in a bucket with object versioning enabled.
+type ObjectVersion struct {
+	Generation   int64
+	Size         uint64
+	LastModified time.Time
+	IsLatest     bool
+}
+
+// Versions returns all generations of the file's object, newest first.  Buckets without object versioning only return
+// the live generation.
+func (f *File) Versions() ([]ObjectVersion, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	key := utils.RemoveLeadingSlash(f.key)
+	it := f.fileSystem.bucket(client, f.bucket).Objects(f.fileSystem.ctx, &storage.Query{Versions: true, Prefix: key})
+
+	versions := make([]ObjectVersion, 0)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// the prefix also matches other objects whose names start with this one's, ie "file.txt.bak"
+		if attrs.Name != key {
+			continue
+		}
+		versions = append(versions, ObjectVersion{
+			Generation:   attrs.Generation,
+			Size:         uint64(attrs.Size),
+			LastModified: attrs.Updated,
+			// noncurrent generations have a deletion time
+			IsLatest: attrs.Deleted.IsZero(),
+		})
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Generation > versions[j].Generation
+	})
+
+	return versions, nil
+}
+
+// AtGeneration returns a File referring to the given generation of the file's object.  Reads, Size, LastModified,
+// Exists and copies of the returned File use that generation.  Delete permanently deletes only that generation.  The
+// returned File can't be written or touched; ErrGenerationReadOnly is returned instead.
+func (f *File) AtGeneration(generation int64) (*File, error) {
+	if generation <= 0 {
+		return nil, errors.New("positive generation is required")
+	}
+
+	return &File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		generation: generation,
+	}, nil
+}
+
+// Generation returns the generation the file refers to, or 0 if it refers to the live generation.
+func (f *File) Generation() int64 {
+	return f.generation
+}
+
+// RestoreGeneration makes the given generation the live generation of the file's object by copying it over the live
+// one.  With object versioning enabled, the replaced generation is kept as a noncurrent generation.
+func (f *File) RestoreGeneration(generation int64) error {
+	if f.generation != 0 {
+		return ErrGenerationReadOnly
+	}
+
+	version, err := f.AtGeneration(generation)
+	if err != nil {
+		return err
+	}
+	return version.CopyToFile(f)
+}
@@ -0,0 +1,140 @@
+package gs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
+)
+
+type versionTestSuite struct {
+	suite.Suite
+	server *fakestorage.Server
+	fs     *FileSystem
+}
+
+func (ts *versionTestSuite) SetupTest() {
+	ts.server = fakestorage.NewServer(Objects{})
+	ts.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "bucki", VersioningEnabled: true})
+	ts.fs = NewFileSystem().WithClient(ts.server.Client())
+}
+
+func (ts *versionTestSuite) TearDownTest() {
+	ts.server.Stop()
+}
+
+func (ts *versionTestSuite) writeObject(name, contents string) {
+	w := ts.server.Client().Bucket("bucki").Object(name).NewWriter(context.Background())
+	_, err := w.Write([]byte(contents))
+	ts.Require().NoError(err)
+	ts.Require().NoError(w.Close())
+}
+
+func (ts *versionTestSuite) readAll(f *File) string {
+	data, err := io.ReadAll(f)
+	ts.Require().NoError(err)
+	ts.Require().NoError(f.Close())
+	return string(data)
+}
+
+func (ts *versionTestSuite) TestVersions() {
+	ts.writeObject("path/file.txt", "one")
+	ts.writeObject("path/file.txt", "two!")
+	ts.writeObject("path/file.txt.bak", "other")
+
+	file, err := ts.fs.NewFile("bucki", "/path/file.txt")
+	ts.Require().NoError(err)
+
+	versions, err := file.(*File).Versions()
+	ts.Require().NoError(err)
+	ts.Require().Len(versions, 2)
+	ts.Greater(versions[0].Generation, versions[1].Generation)
+	ts.True(versions[0].IsLatest)
+	ts.Equal(uint64(4), versions[0].Size)
+	ts.False(versions[1].IsLatest)
+	ts.Equal(uint64(3), versions[1].Size)
+}
+
+func (ts *versionTestSuite) TestAtGenerationAndRestore() {
+	ts.writeObject("path/file.txt", "one")
+	ts.writeObject("path/file.txt", "two")
+
+	vfsFile, err := ts.fs.NewFile("bucki", "/path/file.txt")
+	ts.Require().NoError(err)
+	file := vfsFile.(*File)
+
+	versions, err := file.Versions()
+	ts.Require().NoError(err)
+	ts.Require().Len(versions, 2)
+	oldest := versions[1].Generation
+
+	_, err = file.AtGeneration(0)
+	ts.Error(err)
+
+	old, err := file.AtGeneration(oldest)
+	ts.Require().NoError(err)
+	ts.Equal(oldest, old.Generation())
+	ts.Zero(file.Generation())
+	ts.Equal(file.URI(), old.URI())
+	ts.Equal("one", ts.readAll(old))
+	ts.Equal("two", ts.readAll(file))
+
+	// generations are read-only
+	_, err = old.Write([]byte("nope"))
+	ts.ErrorIs(err, ErrGenerationReadOnly)
+	ts.ErrorIs(old.Touch(), ErrGenerationReadOnly)
+	ts.ErrorIs(file.CopyToFile(old), ErrGenerationReadOnly)
+	ts.ErrorIs(old.RestoreGeneration(oldest), ErrGenerationReadOnly)
+
+	ts.NoError(file.RestoreGeneration(oldest))
+	ts.Equal("one", ts.readAll(file))
+
+	versions, err = file.Versions()
+	ts.NoError(err)
+	ts.Len(versions, 3, "restoring keeps the replaced generation")
+}
+
+func (ts *versionTestSuite) TestDeleteGenerationWithConditions() {
+	ts.writeObject("path/file.txt", "one")
+	ts.writeObject("path/file.txt", "two")
+
+	// record the deletes sent to the fake server, which doesn't delete single generations itself
+	var deletes []*url.URL
+	transport := ts.server.HTTPClient().Transport
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(ts.server.URL()+"/storage/v1/"),
+		option.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodDelete {
+				deletes = append(deletes, r.URL)
+			}
+			return transport.RoundTrip(r)
+		})}))
+	ts.Require().NoError(err)
+	fs := NewFileSystem().WithClient(client)
+
+	vfsFile, err := fs.NewFile("bucki", "/path/file.txt")
+	ts.Require().NoError(err)
+	versions, err := vfsFile.(*File).Versions()
+	ts.Require().NoError(err)
+	ts.Require().Len(versions, 2)
+
+	old, err := vfsFile.(*File).AtGeneration(versions[1].Generation)
+	ts.Require().NoError(err)
+
+	// only the pinned generation is deleted, not the live object
+	ts.Require().NoError(old.IfMetagenerationMatch(1).Delete())
+	ts.Require().Len(deletes, 1)
+	ts.Equal(strconv.FormatInt(versions[1].Generation, 10), deletes[0].Query().Get("generation"))
+	ts.Equal("1", deletes[0].Query().Get("ifMetagenerationMatch"))
+}
+
+func TestVersion(t *testing.T) {
+	suite.Run(t, new(versionTestSuite))
+}
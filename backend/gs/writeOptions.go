@@ -0,0 +1,117 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// WriteOptions are the attributes given to the objects created by writes, Touch and native copies.  Fields left zero
+// fall back to the bucket's defaults, or, for native copies, to the attributes of the source object.
+type WriteOptions struct {
+	// KMSKeyName is the Cloud KMS key, projects/P/locations/L/keyRings/R/cryptoKeys/K, encrypting new objects.  It
+	// can't be combined with Options.CustomerSuppliedKey.
+	KMSKeyName string `json:"kmsKeyName,omitempty"`
+	// StorageClass of new objects, such as "NEARLINE", "COLDLINE" or "ARCHIVE".
+	StorageClass string `json:"storageClass,omitempty"`
+	// RetentionMode, "Unlocked" or "Locked", retains new objects for RetentionPeriod from when they're created.  The
+	// bucket must have object retention enabled.
+	RetentionMode   string        `json:"retentionMode,omitempty"`
+	RetentionPeriod time.Duration `json:"retentionPeriod,omitempty"`
+}
+
+// WithWriteOptions returns a File for the same object whose writes, Touch and native copies onto it create the object
+// with opts.  Fields left zero fall back to the WriteOptions of the file system's Options.
+func (f *File) WithWriteOptions(opts WriteOptions) *File {
+	file := f.withConditions()
+	file.writeOptions = opts
+	return file
+}
+
+// getWriteOptions returns the file's write options, falling back to those of its file system.
+func (f *File) getWriteOptions() WriteOptions {
+	options, _ := f.fileSystem.options.(Options)
+	opts := options.WriteOptions
+	if f.writeOptions.KMSKeyName != "" {
+		opts.KMSKeyName = f.writeOptions.KMSKeyName
+	}
+	if f.writeOptions.StorageClass != "" {
+		opts.StorageClass = f.writeOptions.StorageClass
+	}
+	if f.writeOptions.RetentionMode != "" {
+		opts.RetentionMode = f.writeOptions.RetentionMode
+		opts.RetentionPeriod = f.writeOptions.RetentionPeriod
+	}
+	return opts
+}
+
+// retention returns the retention of an object created now, or nil without a RetentionMode.
+func (o WriteOptions) retention() *storage.ObjectRetention {
+	if o.RetentionMode == "" {
+		return nil
+	}
+	return &storage.ObjectRetention{Mode: o.RetentionMode, RetainUntil: time.Now().Add(o.RetentionPeriod)}
+}
+
+// customerSuppliedKey returns the CustomerSuppliedKey option, or nil if none is provided.
+func (fs *FileSystem) customerSuppliedKey() []byte {
+	options, _ := fs.options.(Options)
+	return options.CustomerSuppliedKey
+}
+
+// encrypted returns handle using the file system's customer-supplied encryption key, if it has one.
+func (fs *FileSystem) encrypted(handle *storage.ObjectHandle) *storage.ObjectHandle {
+	if key := fs.customerSuppliedKey(); key != nil {
+		return handle.Key(key)
+	}
+	return handle
+}
+
+// newWriter returns a writer to handle creating the object with the file's write options.
+func (f *File) newWriter(ctx context.Context, handle ObjectHandleCopier) *storage.Writer {
+	opts := f.getWriteOptions()
+	w := handle.NewWriter(ctx)
+	w.KMSKeyName = opts.KMSKeyName
+	w.StorageClass = opts.StorageClass
+	w.Retention = opts.retention()
+	return w
+}
+
+// setCopierAttrs sets the attributes of the copy onto the file made by copier: those of the file's write options,
+// otherwise the storage class, KMS key and retention of the source object with attrs.  A KMS key isn't preserved
+// when the file system has a customer-supplied encryption key, which the copy is encrypted with instead.  Copiers that
+// don't implement CopierAttrsWrapper copy with GCS's defaults, and an error is returned if write options need them.
+func (f *File) setCopierAttrs(wrapper CopierWrapper, attrs *storage.ObjectAttrs) error {
+	opts := f.getWriteOptions()
+	copier, ok := wrapper.(CopierAttrsWrapper)
+	if !ok {
+		if opts.StorageClass != "" || opts.KMSKeyName != "" || opts.retention() != nil {
+			return fmt.Errorf("gs write options can't be applied to a copy by %T, which doesn't implement "+
+				"gs.CopierAttrsWrapper", wrapper)
+		}
+		return nil
+	}
+
+	storageClass := opts.StorageClass
+	if storageClass == "" {
+		storageClass = attrs.StorageClass
+	}
+	copier.StorageClass(storageClass)
+
+	kmsKeyName := opts.KMSKeyName
+	if kmsKeyName == "" && f.fileSystem.customerSuppliedKey() == nil {
+		// objects name the key version that encrypted them, copies have to name the key
+		kmsKeyName, _, _ = strings.Cut(attrs.KMSKeyName, "/cryptoKeyVersions/")
+	}
+	copier.DestinationKMSKeyName(kmsKeyName)
+
+	retention := opts.retention()
+	if retention == nil {
+		retention = attrs.Retention
+	}
+	copier.Retention(retention)
+	return nil
+}
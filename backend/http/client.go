@@ -0,0 +1,129 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	_http "net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// head returns the response headers of a HEAD request for p, with the raw query rawQuery.
+func (fs *FileSystem) head(authority utils.Authority, p, rawQuery string) (_http.Header, error) {
+	c := fs.newClient()
+	u := c.URL(authority, p)
+	u.RawQuery = rawQuery
+	resp, err := c.Do(_http.MethodHead, authority, u, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := c.CheckStatus(resp, _http.MethodHead, p, _http.StatusOK); err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
+// contentLength returns the Content-Length of a HEAD response.
+func contentLength(header _http.Header, p string) (uint64, error) {
+	size, err := strconv.ParseUint(header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("http HEAD %s: server did not return a valid Content-Length", p)
+	}
+	return size, nil
+}
+
+// lastModified returns the Last-Modified time of a HEAD response.
+func lastModified(header _http.Header, p string) (*time.Time, error) {
+	t, err := _http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return nil, fmt.Errorf("http HEAD %s: server did not return a valid Last-Modified", p)
+	}
+	return &t, nil
+}
+
+// list returns the names of the files directly within dir by parsing the links of the directory index page the server
+// returns for dir (ie, an nginx autoindex or apache mod_autoindex page).  Links to sub-directories, to other
+// directories, to other hosts, or with a query string (ie, sort links) are ignored.  A non-existent directory results
+// in an empty list.
+func (fs *FileSystem) list(authority utils.Authority, dir string) ([]string, error) {
+	c := fs.newClient()
+	u := c.URL(authority, dir)
+	resp, err := c.Do(_http.MethodGet, authority, u, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := c.CheckStatus(resp, _http.MethodGet, dir, _http.StatusOK); err != nil {
+		if errors.Is(err, vfs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	return parseIndex(resp.Body, u)
+}
+
+// parseIndex returns the base names of the files linked from the html page r, located at base, that are directly
+// within base's path.
+func parseIndex(r io.Reader, base *url.URL) ([]string, error) {
+	names := make([]string, 0)
+	seen := map[string]bool{}
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if errors.Is(tokenizer.Err(), io.EOF) {
+				return names, nil
+			}
+			return nil, tokenizer.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag, hasAttr := tokenizer.TagName()
+			if string(tag) != "a" {
+				continue
+			}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = tokenizer.TagAttr()
+				if string(key) != "href" {
+					continue
+				}
+				if name := linkedFile(base, string(val)); name != "" && !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+}
+
+// linkedFile returns the base name of the file href refers to, or "" if href isn't a file directly within base.
+func linkedFile(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || ref.RawQuery != "" {
+		return ""
+	}
+
+	u := base.ResolveReference(ref)
+	if u.Host != base.Host || u.Scheme != base.Scheme {
+		return ""
+	}
+	if u.Path == "" || u.Path[len(u.Path)-1] == '/' {
+		return ""
+	}
+	if utils.EnsureTrailingSlash(path.Dir(u.Path)) != base.Path {
+		return ""
+	}
+	return path.Base(u.Path)
+}
@@ -0,0 +1,78 @@
+/*
+Package http - read-only HTTP(S) VFS implementation.
+
+# Usage
+
+Rely on github.com/c2fo/vfs/v6/backend
+
+	import(
+	    "github.com/c2fo/vfs/v6/backend"
+	    "github.com/c2fo/vfs/v6/backend/http"
+	)
+
+	func UseFs() error {
+	    fs := backend.Backend(http.SecureScheme)
+	    ...
+	}
+
+Or use vfssimple to copy a published file to any other backend:
+
+	func DoSomething() error {
+	    src, err := vfssimple.NewFile("https://downloads.acme.com/exports/daily.csv")
+	    if err != nil {
+	        return err
+	    }
+
+	    dst, err := vfssimple.NewLocation("s3://mybucket/imports/")
+	    if err != nil {
+	        return err
+	    }
+
+	    _, err = src.CopyToLocation(dst)
+	    return err
+	}
+
+http can be augmented with some implementation-specific methods.  Backend returns vfs.Filesystem interface so it
+would have to be cast as http.Filesystem to use them.
+
+These methods are chainable:
+(*FileSystem) WithClient(client *http.Client) *FileSystem
+(*FileSystem) WithOptions(opts vfs.Options) *FileSystem
+
+# Protocol
+
+  - Exists, Size and LastModified use HEAD, returning the Content-Length and Last-Modified headers
+  - Read uses GET, with a Range header after a Seek.  Servers that ignore Range are supported by discarding bytes up
+    to the cursor.
+  - CopyToFile and CopyToLocation stream the contents to the target file
+  - Location List, ListByPrefix and ListByRegex parse the links of the directory index page the server returns for the
+    location (ie, an nginx autoindex or apache mod_autoindex page).  Only links to files directly within the location
+    are returned.  If the server doesn't publish index pages, the lists are empty.
+
+The backend is read-only: Write, Touch, Delete, MoveToFile, MoveToLocation and Location.DeleteFile return ErrReadOnly.
+
+A file's query string, ie the signature of a pre-signed URL, is kept with the file, sent with its HEAD and GET
+requests and included in its URI, but isn't part of its Path.  Locations have no query string.
+
+	file, err := vfssimple.NewFile("https://downloads.acme.com/exports/daily.csv?expires=1700000000&signature=abc123")
+
+# Authentication
+
+Anonymous requests are sent unless credentials are provided.
+
+## USERNAME
+
+User may only be set in the URI authority section (Volume in vfs parlance), ie "https://someuser@downloads.acme.com/".
+If a username is provided, requests are sent with HTTP basic authentication.
+
+## PASSWORD
+
+Passwords may be passed via Options.Password or via the environmental variable *VFS_HTTP_PASSWORD*.  Options.Password,
+if set, overrides the env var.
+
+## BEARER TOKEN
+
+A bearer token may be passed via Options.BearerToken or via the environmental variable *VFS_HTTP_TOKEN*.  When set,
+it is used instead of basic authentication.
+*/
+package http
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// File implements vfs.File interface for a read-only file published over HTTP(S).
+type File struct {
+	fileSystem *FileSystem
+	authority  utils.Authority
+	path       string
+	rawQuery   string
+	cursorPos  int64
+	reader     io.ReadCloser
+}
+
+// LastModified returns the Last-Modified header of a HEAD request for the file.
+func (f *File) LastModified() (*time.Time, error) {
+	header, err := f.fileSystem.head(f.authority, f.Path(), f.rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	return lastModified(header, f.Path())
+}
+
+// Name returns the base name of the file path.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the absolute path of the file, ie /some/path/to/file.txt
+func (f *File) Path() string {
+	return utils.EnsureLeadingSlash(f.path)
+}
+
+// Exists returns whether a HEAD request for the file succeeds.
+func (f *File) Exists() (bool, error) {
+	_, err := f.fileSystem.head(f.authority, f.Path(), f.rawQuery)
+	if err != nil {
+		if errors.Is(err, vfs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Size returns the Content-Length header of a HEAD request for the file.
+func (f *File) Size() (uint64, error) {
+	header, err := f.fileSystem.head(f.authority, f.Path(), f.rawQuery)
+	if err != nil {
+		return 0, err
+	}
+	return contentLength(header, f.Path())
+}
+
+// Location returns a vfs.Location at the location of the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+		Authority:  f.authority,
+	}
+}
+
+// CopyToFile streams the contents of File into the target file using io.CopyBuffer.
+func (f *File) CopyToFile(file vfs.File) error {
+	// validate seek is at 0,0 before doing copy
+	if f.cursorPos != 0 {
+		return vfs.CopyToNotPossible
+	}
+
+	if err := utils.TouchCopyBuffered(file, f, 0); err != nil {
+		return err
+	}
+	// Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// CopyToLocation creates a copy of *File, using the file's current name as the new file's name at the given location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.CopyToFile(newFile)
+}
+
+// MoveToFile returns ErrReadOnly since the source file can't be deleted.
+func (f *File) MoveToFile(_ vfs.File) error {
+	return ErrReadOnly
+}
+
+// MoveToLocation returns ErrReadOnly since the source file can't be deleted.
+func (f *File) MoveToLocation(_ vfs.Location) (vfs.File, error) {
+	return nil, ErrReadOnly
+}
+
+// Delete returns ErrReadOnly.
+func (f *File) Delete(_ ...options.DeleteOption) error {
+	return ErrReadOnly
+}
+
+// Close closes any open GET request and resets the cursor.
+func (f *File) Close() error {
+	f.cursorPos = 0
+
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read implements io.Reader, streaming the file's contents with GET (using a Range header when not at the start of
+// the file).
+func (f *File) Read(p []byte) (int, error) {
+	r, err := f.getReader()
+	if err != nil {
+		return 0, err
+	}
+
+	read, err := r.Read(p)
+	f.cursorPos += int64(read)
+	return read, err
+}
+
+// Seek implements io.Seeker.  The next Read after a Seek issues a new GET starting at the new offset.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, vfs.ErrSeekInvalidWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.cursorPos
+	case io.SeekEnd:
+		length, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		offset += int64(length)
+	}
+	if offset < 0 {
+		return 0, vfs.ErrSeekInvalidOffset
+	}
+	f.cursorPos = offset
+
+	// invalidate reader
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return f.cursorPos, nil
+}
+
+// Write returns ErrReadOnly.
+func (f *File) Write(_ []byte) (int, error) {
+	return 0, ErrReadOnly
+}
+
+// Touch returns ErrReadOnly.
+func (f *File) Touch() error {
+	return ErrReadOnly
+}
+
+// URI returns the File's URI as a string, including its query string.
+func (f *File) URI() string {
+	if f.rawQuery != "" {
+		return utils.GetFileURI(f) + "?" + f.rawQuery
+	}
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+func (f *File) getReader() (io.ReadCloser, error) {
+	if f.reader != nil {
+		return f.reader, nil
+	}
+
+	c := f.fileSystem.newClient()
+	u := c.URL(f.authority, f.Path())
+	u.RawQuery = f.rawQuery
+	r, err := c.GetReader(f.authority, u, f.cursorPos)
+	if err != nil {
+		return nil, err
+	}
+
+	f.reader = r
+	return f.reader, nil
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"errors"
+	_http "net/http"
+	"path"
+	"strings"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend"
+	"github.com/c2fo/vfs/v6/backend/internal/httpclient"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+const (
+	// Scheme defines the filesystem type for plain HTTP.
+	Scheme = "http"
+	// SecureScheme defines the filesystem type for HTTPS.
+	SecureScheme = "https"
+)
+
+const name = "HTTP"
+
+// ErrReadOnly is returned by any operation that would modify a file or location on an http filesystem.
+var ErrReadOnly = errors.New("http filesystem is read-only")
+
+// FileSystem implements vfs.Filesystem for read-only access to files published over HTTP(S).
+type FileSystem struct {
+	options vfs.Options
+	client  *_http.Client
+	secure  bool
+}
+
+// Retry will return the default no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile function returns the http implementation of vfs.File.  A query string following the path, ie the signature of
+// a pre-signed URL, is kept with the file and sent with its requests.
+func (fs *FileSystem) NewFile(authority, filePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil http.FileSystem pointer is required")
+	}
+	if filePath == "" {
+		return nil, errors.New("non-empty string for path is required")
+	}
+	filePath, rawQuery, _ := strings.Cut(filePath, "?")
+	if err := utils.ValidateAbsoluteFilePath(filePath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		fileSystem: fs,
+		authority:  auth,
+		path:       path.Clean(filePath),
+		rawQuery:   rawQuery,
+	}, nil
+}
+
+// NewLocation function returns the http implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(authority, locPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil http.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(locPath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{
+		fileSystem: fs,
+		path:       utils.EnsureTrailingSlash(path.Clean(locPath)),
+		Authority:  auth,
+	}, nil
+}
+
+// Name returns "HTTP"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "http" or "https" as the initial part of a file URI ie: https://
+func (fs *FileSystem) Scheme() string {
+	if fs.secure {
+		return SecureScheme
+	}
+	return Scheme
+}
+
+// Client returns the underlying http client, creating it, if necessary
+func (fs *FileSystem) Client() *_http.Client {
+	if fs.client == nil {
+		fs.client = _http.DefaultClient
+		if opts, ok := fs.options.(Options); ok && opts.HTTPClient != nil {
+			fs.client = opts.HTTPClient
+		}
+	}
+	return fs.client
+}
+
+// WithOptions sets options for client and returns the filesystem (chainable)
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	// only set options if vfs.Options is http.Options
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		// we set client to nil to ensure that a new client is created using the new options when Client() is called
+		fs.client = nil
+	}
+	return fs
+}
+
+// WithClient passes in an http client and returns the filesystem (chainable)
+func (fs *FileSystem) WithClient(client *_http.Client) *FileSystem {
+	fs.client = client
+	return fs
+}
+
+// NewFileSystem initializer for a FileSystem using plain HTTP.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+// NewSecureFileSystem initializer for a FileSystem using HTTPS.
+func NewSecureFileSystem() *FileSystem {
+	return &FileSystem{secure: true}
+}
+
+// newClient returns the client of the file system's requests.
+func (fs *FileSystem) newClient() *httpclient.Client {
+	opts := fs.getOptions()
+	return &httpclient.Client{
+		Name:        "http",
+		Secure:      fs.secure,
+		HTTPClient:  fs.Client(),
+		Password:    httpclient.Fetch(opts.Password, envPassword),
+		BearerToken: httpclient.Fetch(opts.BearerToken, envToken),
+	}
+}
+
+func (fs *FileSystem) getOptions() Options {
+	if opts, ok := fs.options.(Options); ok {
+		return opts
+	}
+	return Options{}
+}
+
+func init() {
+	// registers default Filesystems
+	backend.Register(Scheme, NewFileSystem())
+	backend.Register(SecureScheme, NewSecureFileSystem())
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	_http "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/backend"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// newTestServer starts an in-process file server for a temp dir populated with files (path => contents), returning
+// the server and its authority.
+func newTestServer(t *testing.T, files map[string]string) (*httptest.Server, string) {
+	dir := t.TempDir()
+	for p, contents := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(_http.FileServer(_http.Dir(dir)))
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+type fileSystemTestSuite struct {
+	suite.Suite
+}
+
+func (ts *fileSystemTestSuite) TestRegistered() {
+	ts.Equal(Scheme, backend.Backend(Scheme).Scheme())
+	ts.Equal(SecureScheme, backend.Backend(SecureScheme).Scheme())
+	ts.Equal("HTTP", NewFileSystem().Name())
+}
+
+func (ts *fileSystemTestSuite) TestNewFile() {
+	fs := NewFileSystem()
+	file, err := fs.NewFile("user@host.com:8080", "/path/../to/file.txt")
+	ts.NoError(err)
+	ts.Equal("/to/file.txt", file.Path())
+	ts.Equal("http://user@host.com:8080/to/file.txt", file.URI())
+
+	file, err = NewSecureFileSystem().NewFile("host.com", "/file.txt")
+	ts.NoError(err)
+	ts.Equal("https://host.com/file.txt", file.URI())
+
+	_, err = fs.NewFile("host.com", "relative.txt")
+	ts.EqualError(err, utils.ErrBadAbsFilePath)
+	_, err = fs.NewFile("host.com", "")
+	ts.Error(err)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewFile("host.com", "/file.txt")
+	ts.EqualError(err, "non-nil http.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation() {
+	fs := NewFileSystem()
+	loc, err := fs.NewLocation("host.com", "/some/path/")
+	ts.NoError(err)
+	ts.Equal("http://host.com/some/path/", loc.URI())
+
+	_, err = fs.NewLocation("host.com", "/some/path")
+	ts.EqualError(err, utils.ErrBadAbsLocationPath)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewLocation("host.com", "/")
+	ts.EqualError(err, "non-nil http.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestClientAndOptions() {
+	fs := NewFileSystem()
+	ts.Equal(_http.DefaultClient, fs.Client())
+
+	client := &_http.Client{}
+	fs.WithOptions(Options{HTTPClient: client})
+	ts.Equal(client, fs.Client())
+
+	other := &_http.Client{}
+	fs.WithClient(other)
+	ts.Equal(other, fs.Client())
+}
+
+func (ts *fileSystemTestSuite) TestAuthentication() {
+	var gotUser, gotPass, gotAuth string
+	server := httptest.NewServer(_http.HandlerFunc(func(w _http.ResponseWriter, r *_http.Request) {
+		gotUser, gotPass, _ = r.BasicAuth()
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(_http.StatusNotFound)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	fs := NewFileSystem().WithOptions(Options{Password: "s3cr3t"})
+	file, err := fs.NewFile("bob@"+host, "/file.txt")
+	ts.Require().NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	ts.Equal("bob", gotUser)
+	ts.Equal("s3cr3t", gotPass)
+
+	fs = NewFileSystem().WithOptions(Options{BearerToken: "tok"})
+	file, err = fs.NewFile("bob@"+host, "/file.txt")
+	ts.Require().NoError(err)
+	_, err = file.Exists()
+	ts.NoError(err)
+	ts.Equal("Bearer tok", gotAuth)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
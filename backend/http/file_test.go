@@ -0,0 +1,197 @@
+package http
+
+import (
+	"io"
+	_http "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type fileTestSuite struct {
+	suite.Suite
+	server    *httptest.Server
+	authority string
+	fs        *FileSystem
+}
+
+func (ts *fileTestSuite) SetupTest() {
+	ts.server, ts.authority = newTestServer(ts.T(), map[string]string{
+		"data/file.txt": "hello world",
+	})
+	ts.fs = NewFileSystem()
+}
+
+func (ts *fileTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *fileTestSuite) newFile(p string) vfs.File {
+	file, err := ts.fs.NewFile(ts.authority, p)
+	ts.Require().NoError(err)
+	return file
+}
+
+func (ts *fileTestSuite) TestRead() {
+	file := ts.newFile("/data/file.txt")
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(11), size)
+
+	modified, err := file.LastModified()
+	ts.NoError(err)
+	ts.NotNil(modified)
+
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestSeek() {
+	file := ts.newFile("/data/file.txt")
+
+	pos, err := file.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	ts.Equal(int64(6), pos)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("world", string(data))
+
+	pos, err = file.Seek(-5, io.SeekEnd)
+	ts.NoError(err)
+	ts.Equal(int64(6), pos)
+	pos, err = file.Seek(-3, io.SeekCurrent)
+	ts.NoError(err)
+	ts.Equal(int64(3), pos)
+	data, err = io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("lo world", string(data))
+
+	// seeking to the end results in an empty read
+	_, err = file.Seek(0, io.SeekEnd)
+	ts.NoError(err)
+	data, err = io.ReadAll(file)
+	ts.NoError(err)
+	ts.Empty(data)
+
+	_, err = file.Seek(-1, io.SeekStart)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidOffset)
+	_, err = file.Seek(0, 3)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidWhence)
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestSeekWithoutRangeSupport() {
+	server := httptest.NewServer(_http.HandlerFunc(func(w _http.ResponseWriter, r *_http.Request) {
+		_, _ = io.Copy(w, strings.NewReader("hello world"))
+	}))
+	defer server.Close()
+
+	file, err := ts.fs.NewFile(strings.TrimPrefix(server.URL, "http://"), "/file.txt")
+	ts.Require().NoError(err)
+	_, err = file.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("world", string(data))
+}
+
+func (ts *fileTestSuite) TestQueryString() {
+	var queries []string
+	server := httptest.NewServer(_http.HandlerFunc(func(w _http.ResponseWriter, r *_http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("signature") != "a/b" {
+			w.WriteHeader(_http.StatusForbidden)
+			return
+		}
+		_http.ServeContent(w, r, "file.txt", time.Now(), strings.NewReader("hello world"))
+	}))
+	defer server.Close()
+
+	file, err := ts.fs.NewFile(strings.TrimPrefix(server.URL, "http://"), "/data/file.txt?signature=a%2Fb&expires=1")
+	ts.Require().NoError(err)
+	ts.Equal("/data/file.txt", file.Path())
+	ts.Equal("file.txt", file.Name())
+	ts.Equal(server.URL+"/data/file.txt?signature=a%2Fb&expires=1", file.URI())
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(11), size)
+	_, err = file.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("world", string(data))
+	ts.NoError(file.Close())
+	ts.Equal([]string{"signature=a%2Fb&expires=1", "signature=a%2Fb&expires=1"}, queries, "HEAD and Range requests should send the query")
+
+	loc, err := ts.fs.NewLocation(strings.TrimPrefix(server.URL, "http://"), "/data/")
+	ts.Require().NoError(err)
+	file, err = loc.NewFile("file.txt?signature=a%2Fb")
+	ts.Require().NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+}
+
+func (ts *fileTestSuite) TestNotExist() {
+	file := ts.newFile("/missing.txt")
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	_, err = file.Size()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = file.LastModified()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = file.Read(make([]byte, 1))
+	ts.ErrorIs(err, vfs.ErrNotExist)
+}
+
+func (ts *fileTestSuite) TestReadOnly() {
+	file := ts.newFile("/data/file.txt")
+
+	_, err := file.Write([]byte("nope"))
+	ts.ErrorIs(err, ErrReadOnly)
+	ts.ErrorIs(file.Touch(), ErrReadOnly)
+	ts.ErrorIs(file.Delete(), ErrReadOnly)
+	ts.ErrorIs(file.MoveToFile(ts.newFile("/other.txt")), ErrReadOnly)
+	_, err = file.MoveToLocation(file.Location())
+	ts.ErrorIs(err, ErrReadOnly)
+	ts.ErrorIs(file.Location().DeleteFile("file.txt"), ErrReadOnly)
+}
+
+func (ts *fileTestSuite) TestCopyToOtherFileSystem() {
+	file := ts.newFile("/data/file.txt")
+
+	memLoc, err := mem.NewFileSystem().NewLocation("", "/copies/")
+	ts.Require().NoError(err)
+
+	copied, err := file.CopyToLocation(memLoc)
+	ts.NoError(err)
+	ts.Equal("/copies/file.txt", copied.Path())
+	data, err := io.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+
+	// copy is only allowed from the start of the file
+	_, err = file.Seek(1, io.SeekStart)
+	ts.NoError(err)
+	ts.ErrorIs(file.CopyToFile(copied), vfs.CopyToNotPossible)
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+type locationTestSuite struct {
+	suite.Suite
+}
+
+func (ts *locationTestSuite) TestList() {
+	server, authority := newTestServer(ts.T(), map[string]string{
+		"data/a.csv":        "a",
+		"data/b.csv":        "b",
+		"data/c.txt":        "c",
+		"data/sub/d.txt":    "d",
+		"data/with space.x": "e",
+	})
+	defer server.Close()
+
+	loc, err := NewFileSystem().NewLocation(authority, "/data/")
+	ts.Require().NoError(err)
+
+	exists, err := loc.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	list, err := loc.List()
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.csv", "b.csv", "c.txt", "with space.x"}, list)
+
+	list, err = loc.ListByPrefix("b")
+	ts.NoError(err)
+	ts.Equal([]string{"b.csv"}, list)
+
+	list, err = loc.ListByPrefix("sub/d")
+	ts.NoError(err)
+	ts.Equal([]string{"d.txt"}, list)
+
+	list, err = loc.ListByRegex(regexp.MustCompile(`\.csv$`))
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.csv", "b.csv"}, list)
+
+	missing, err := loc.NewLocation("missing/")
+	ts.Require().NoError(err)
+	exists, err = missing.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	list, err = missing.List()
+	ts.NoError(err)
+	ts.Empty(list)
+}
+
+func (ts *locationTestSuite) TestParseIndex() {
+	page := `<html><body><pre>
+<a href="?C=N;O=D">Name</a>
+<a href="../">Parent Directory</a>
+<a href="sub/">sub/</a>
+<a href="file1.txt">file1.txt</a>
+<a href="/data/file2.txt">file2.txt</a>
+<a href="/other/file3.txt">file3.txt</a>
+<a href="http://elsewhere.com/data/file4.txt">file4.txt</a>
+<a href="file%205.txt">file 5.txt</a>
+<a href="file1.txt">again</a>
+</pre></body></html>`
+
+	base, err := url.Parse("http://host.com/data/")
+	ts.Require().NoError(err)
+	list, err := parseIndex(strings.NewReader(page), base)
+	ts.NoError(err)
+	ts.Equal([]string{"file1.txt", "file2.txt", "file 5.txt"}, list)
+}
+
+func (ts *locationTestSuite) TestNewLocationAndFile() {
+	loc, err := NewSecureFileSystem().NewLocation("host.com", "/some/path/")
+	ts.Require().NoError(err)
+
+	newLoc, err := loc.NewLocation("../other/")
+	ts.NoError(err)
+	ts.Equal("https://host.com/some/other/", newLoc.URI())
+	ts.Equal("https://host.com/some/path/", loc.URI())
+
+	ts.NoError(loc.ChangeDir("deeper/"))
+	ts.Equal("/some/path/deeper/", loc.Path())
+	ts.EqualError(loc.ChangeDir("/abs/"), utils.ErrBadRelLocationPath)
+
+	file, err := loc.NewFile("file.txt")
+	ts.NoError(err)
+	ts.Equal("https://host.com/some/path/deeper/file.txt", file.URI())
+	ts.Equal(loc.URI(), file.Location().URI())
+
+	_, err = loc.NewFile("/abs.txt")
+	ts.EqualError(err, utils.ErrBadRelFilePath)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	_http "net/http"
+)
+
+// Options struct implements the vfs.Options interface, providing optional parameters for creating an http filesystem.
+type Options struct {
+	Password    string        `json:"password,omitempty"`    // env var VFS_HTTP_PASSWORD
+	BearerToken string        `json:"bearerToken,omitempty"` // env var VFS_HTTP_TOKEN
+	HTTPClient  *_http.Client `json:"-"`
+}
+
+const (
+	envPassword = "VFS_HTTP_PASSWORD" //nolint:gosec
+	envToken    = "VFS_HTTP_TOKEN"    //nolint:gosec
+)
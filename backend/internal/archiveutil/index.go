@@ -0,0 +1,110 @@
+// Package archiveutil holds the entry handling shared by the archive backends, zip and tar: the in-memory index of an
+// archive's entries and the buffering of a file's contents until it's closed and added to an archive as an entry.
+// Buffered entries are held in memory, so the backends buffer only the entries they can't stream to the archive as
+// they're written.
+package archiveutil
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Index is an in-memory index of the entries of an archive, of type E, keyed by their absolute paths within the
+// archive.  It's safe for concurrent use.
+type Index[E any] struct {
+	mu      sync.RWMutex
+	entries map[string]E
+}
+
+// NewIndex returns an empty Index.
+func NewIndex[E any]() *Index[E] {
+	return &Index[E]{entries: make(map[string]E)}
+}
+
+// Get returns the entry at the absolute path absFilePath, and whether it exists.
+func (idx *Index[E]) Get(absFilePath string) (E, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	e, ok := idx.entries[absFilePath]
+	return e, ok
+}
+
+// Set adds the entry at the absolute path absFilePath, replacing any existing one.
+func (idx *Index[E]) Set(absFilePath string, e E) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries[absFilePath] = e
+}
+
+// Len returns the number of entries.
+func (idx *Index[E]) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// List returns the sorted base names of entries directly within the absolute location path dir that pass test.
+func (idx *Index[E]) List(dir string, test func(name string) bool) []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	names := make([]string, 0)
+	for p := range idx.entries {
+		if utils.EnsureTrailingSlash(path.Dir(p)) == dir && test(path.Base(p)) {
+			names = append(names, path.Base(p))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasPrefix returns true if any entry exists within the absolute location path dir, at any depth.
+func (idx *Index[E]) HasPrefix(dir string) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	for p := range idx.entries {
+		if strings.HasPrefix(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteBuffer buffers what's written to a file of an archive opened for writing, until the file is closed and its
+// contents are added to the archive as an entry.  The whole entry is held in memory until then.  The zero value is an
+// empty buffer that nothing has been written to.
+type WriteBuffer struct {
+	buf *bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (w *WriteBuffer) Write(p []byte) (int, error) {
+	if w.buf == nil {
+		w.buf = bytes.NewBuffer([]byte{})
+	}
+	return w.buf.Write(p)
+}
+
+// Written reports whether anything (even nothing) has been written since the buffer was last flushed.
+func (w *WriteBuffer) Written() bool {
+	return w.buf != nil
+}
+
+// Flush passes the buffered contents to add, if anything (even nothing, as by Touch) was written, then empties the
+// buffer once add succeeds.
+func (w *WriteBuffer) Flush(add func(data []byte) error) error {
+	if w.buf == nil {
+		return nil
+	}
+	if err := add(w.buf.Bytes()); err != nil {
+		return err
+	}
+	w.buf = nil
+	return nil
+}
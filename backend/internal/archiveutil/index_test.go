@@ -0,0 +1,62 @@
+package archiveutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type indexTestSuite struct {
+	suite.Suite
+}
+
+func (ts *indexTestSuite) TestIndex() {
+	idx := NewIndex[int]()
+	for i, p := range []string{"/a.txt", "/dir/b.txt", "/dir/c.csv", "/dir/sub/d.txt"} {
+		idx.Set(p, i)
+	}
+	ts.Equal(4, idx.Len())
+
+	e, ok := idx.Get("/dir/b.txt")
+	ts.True(ok)
+	ts.Equal(1, e)
+	_, ok = idx.Get("/dir/missing.txt")
+	ts.False(ok)
+
+	ts.Equal([]string{"b.txt", "c.csv"}, idx.List("/dir/", func(string) bool { return true }))
+	ts.Equal([]string{"c.csv"}, idx.List("/dir/", func(name string) bool { return name == "c.csv" }))
+	ts.Empty(idx.List("/none/", func(string) bool { return true }))
+
+	ts.True(idx.HasPrefix("/dir/sub/"))
+	ts.False(idx.HasPrefix("/none/"))
+}
+
+func (ts *indexTestSuite) TestWriteBuffer() {
+	var added []string
+	add := func(data []byte) error {
+		added = append(added, string(data))
+		return nil
+	}
+
+	var w WriteBuffer
+	ts.NoError(w.Flush(add))
+	ts.Empty(added, "nothing should be added if nothing was written")
+
+	_, err := w.Write([]byte{})
+	ts.NoError(err)
+	ts.NoError(w.Flush(add))
+	ts.Equal([]string{""}, added, "an empty write should add an empty entry")
+
+	_, err = w.Write([]byte("abc"))
+	ts.NoError(err)
+	ts.Error(w.Flush(func([]byte) error { return errors.New("i always error") }))
+	ts.NoError(w.Flush(add))
+	ts.Equal([]string{"", "abc"}, added, "contents should be kept until they're added")
+	ts.NoError(w.Flush(add))
+	ts.Len(added, 2)
+}
+
+func TestIndex(t *testing.T) {
+	suite.Run(t, new(indexTestSuite))
+}
@@ -0,0 +1,146 @@
+// Package httpclient holds the HTTP(S) plumbing shared by the http and webdav backends: building and authenticating
+// requests for paths on a server, checking response statuses and reading files with Range requests.
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Client sends a backend's requests to HTTP(S) servers, authenticating them with a bearer token or, for authorities
+// with a username, basic auth.
+type Client struct {
+	// Name is the backend's name, ie "http" or "webdav", used in errors
+	Name string
+
+	// Secure sends requests with https rather than http
+	Secure bool
+
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+
+	// Password is the basic auth password of the authority's user
+	Password string
+
+	// BearerToken, if set, is sent rather than basic auth
+	BearerToken string
+}
+
+// Fetch returns value, or the value of the environment variable env if value is empty.  It's used to fetch a backend's
+// credentials from its options and environment.
+//
+// note: since the format "user:pass" in the authority userinfo field is deprecated (per https://tools.ietf.org/html/rfc3986#section-3.2.1)
+// it is not used for passwords and should never be included in a vfs URI
+func Fetch(value, env string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(env)
+}
+
+// URL returns the http(s) URL of path p on the server identified by authority.
+func (c *Client) URL(authority utils.Authority, p string) *url.URL {
+	u := &url.URL{
+		Scheme: "http",
+		Host:   authority.HostPortStr(),
+		Path:   p,
+	}
+	if c.Secure {
+		u.Scheme = "https"
+	}
+	return u
+}
+
+// Do sends a request for u, adding authentication for authority and any headers.
+func (c *Client) Do(method string, authority utils.Authority, u *url.URL, body io.Reader,
+	headers map[string]string) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	} else if username := authority.UserInfo().Username(); username != "" {
+		req.SetBasicAuth(username, c.Password)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	return c.HTTPClient.Do(req)
+}
+
+// DoAndClose sends a request for u, discarding and closing the response body, and returns an error unless the response
+// status is one of expected.
+func (c *Client) DoAndClose(method string, authority utils.Authority, u *url.URL, body io.Reader, headers map[string]string,
+	expected ...int) (int, error) {
+	resp, err := c.Do(method, authority, u, body, headers)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, c.CheckStatus(resp, method, u.Path, expected...)
+}
+
+// CheckStatus returns vfs.ErrNotExist for a 404 or 410 response, or an error describing any other status not in
+// expected.
+func (c *Client) CheckStatus(resp *http.Response, method, p string, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return vfs.ErrNotExist
+	}
+	return fmt.Errorf("%s %s %s: unexpected response status %q", c.Name, method, p, resp.Status)
+}
+
+// GetReader returns a reader of the file at u starting at offset, sending a GET request with a Range header when the
+// offset isn't 0.  If the server ignores the Range header, the file's contents are discarded up to the offset.
+func (c *Client) GetReader(authority utils.Authority, u *url.URL, offset int64) (io.ReadCloser, error) {
+	headers := map[string]string{}
+	if offset > 0 {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+	}
+
+	resp, err := c.Do(http.MethodGet, authority, u, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// server ignored the Range header so discard up to the offset
+		if offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil && !errors.Is(err, io.EOF) {
+				_ = resp.Body.Close()
+				return nil, err
+			}
+		}
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		// offset is at or beyond the end of the file
+		_ = resp.Body.Close()
+		return io.NopCloser(strings.NewReader("")), nil
+	default:
+		_ = resp.Body.Close()
+		return nil, c.CheckStatus(resp, http.MethodGet, u.Path)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+type clientTestSuite struct {
+	suite.Suite
+}
+
+func (ts *clientTestSuite) TestFetch() {
+	ts.T().Setenv("VFS_TEST_TOKEN", "env-token")
+	ts.Equal("opt-token", Fetch("opt-token", "VFS_TEST_TOKEN"), "options should override the environment")
+	ts.Equal("env-token", Fetch("", "VFS_TEST_TOKEN"))
+	ts.Empty(Fetch("", "VFS_TEST_UNSET"))
+}
+
+func (ts *clientTestSuite) TestDo() {
+	var req *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	authority, err := utils.NewAuthority("user@" + server.Listener.Addr().String())
+	ts.Require().NoError(err)
+	c := &Client{Name: "test", HTTPClient: server.Client(), Password: "secret"}
+	u := c.URL(authority, "/some/file.txt")
+	ts.Equal("http://"+server.Listener.Addr().String()+"/some/file.txt", u.String())
+
+	_, err = c.DoAndClose(http.MethodHead, authority, u, nil, map[string]string{"X-Test": "yes"}, http.StatusOK)
+	ts.EqualError(err, `test HEAD /some/file.txt: unexpected response status "418 I'm a teapot"`)
+	username, password, ok := req.BasicAuth()
+	ts.True(ok)
+	ts.Equal("user", username)
+	ts.Equal("secret", password)
+	ts.Equal("yes", req.Header.Get("X-Test"))
+
+	c.BearerToken = "token"
+	status, err := c.DoAndClose(http.MethodHead, authority, u, nil, nil, http.StatusTeapot)
+	ts.NoError(err)
+	ts.Equal(http.StatusTeapot, status)
+	ts.Equal("Bearer token", req.Header.Get("Authorization"), "a bearer token should be sent rather than basic auth")
+}
+
+func (ts *clientTestSuite) TestGetReader() {
+	const contents = "0123456789"
+	ignoreRange := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing.txt":
+			w.WriteHeader(http.StatusGone)
+		case ignoreRange:
+			_, _ = io.WriteString(w, contents)
+		default:
+			http.ServeContent(w, r, "file.txt", time.Now(), strings.NewReader(contents))
+		}
+	}))
+	defer server.Close()
+
+	authority, err := utils.NewAuthority(server.Listener.Addr().String())
+	ts.Require().NoError(err)
+	c := &Client{Name: "test", HTTPClient: server.Client()}
+
+	for _, ignore := range []bool{false, true} {
+		ignoreRange = ignore
+		for offset, expected := range map[int64]string{0: contents, 4: "456789", 10: "", 20: ""} {
+			r, err := c.GetReader(authority, c.URL(authority, "/file.txt"), offset)
+			ts.Require().NoError(err, offset)
+			data, err := io.ReadAll(r)
+			ts.NoError(err)
+			ts.Equal(expected, string(data), "offset %d, server ignores Range: %t", offset, ignore)
+			ts.NoError(r.Close())
+		}
+	}
+
+	_, err = c.GetReader(authority, &url.URL{Scheme: "http", Host: server.Listener.Addr().String(), Path: "/missing.txt"}, 0)
+	ts.ErrorIs(err, vfs.ErrNotExist, "a 410 should be reported as a missing file")
+}
+
+func TestClient(t *testing.T) {
+	suite.Run(t, new(clientTestSuite))
+}
@@ -0,0 +1,161 @@
+package s3
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// maxPutObjectSize is the largest object S3 accepts in a single PutObject.
+const maxPutObjectSize = 5 * 1024 * 1024 * 1024
+
+// storedChecksums holds the checksum-related fields returned by HeadObject and GetObject.
+type storedChecksums struct {
+	crc32c               *string
+	sha256               *string
+	eTag                 *string
+	serverSideEncryption *string
+	sseCustomerAlgorithm *string
+}
+
+// get returns the raw stored checksum for algorithm, or nil if S3 doesn't store one.  Checksums of multipart uploads,
+// suffixed with the number of parts, are checksums of the parts' checksums and aren't returned.  The ETag is only
+// the MD5 of the contents for objects uploaded in one part without SSE-KMS or SSE-C.
+func (c storedChecksums) get(algorithm vfs.ChecksumAlgorithm) []byte {
+	var encoded string
+	switch algorithm {
+	case vfs.ChecksumCRC32C:
+		encoded = aws.StringValue(c.crc32c)
+	case vfs.ChecksumSHA256:
+		encoded = aws.StringValue(c.sha256)
+	case vfs.ChecksumMD5:
+		if aws.StringValue(c.serverSideEncryption) == sseKMS || aws.StringValue(c.sseCustomerAlgorithm) != "" {
+			return nil
+		}
+		eTag := strings.Trim(aws.StringValue(c.eTag), `"`)
+		if strings.Contains(eTag, "-") {
+			return nil
+		}
+		sum, err := hex.DecodeString(eTag)
+		if err != nil {
+			return nil
+		}
+		return sum
+	}
+
+	if encoded == "" || strings.Contains(encoded, "-") {
+		return nil
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	return sum
+}
+
+// Checksum returns the checksum of the file's contents for algorithm, vfs.ChecksumCRC32C, vfs.ChecksumMD5 or
+// vfs.ChecksumSHA256.  The checksum stored by S3 is returned when there is one, otherwise the object is streamed to
+// compute it.
+func (f *File) Checksum(algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	if _, err := utils.NewChecksumHash(algorithm); err != nil {
+		return nil, err
+	}
+
+	input, err := f.headObjectInput()
+	if err != nil {
+		return nil, err
+	}
+	input.SetChecksumMode(s3.ChecksumModeEnabled)
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	head, err := client.HeadObject(input)
+	if err != nil {
+		return nil, handleExistsError(err)
+	}
+
+	stored := storedChecksums{
+		crc32c:               head.ChecksumCRC32C,
+		sha256:               head.ChecksumSHA256,
+		eTag:                 head.ETag,
+		serverSideEncryption: head.ServerSideEncryption,
+		sseCustomerAlgorithm: head.SSECustomerAlgorithm,
+	}
+	if sum := stored.get(algorithm); sum != nil {
+		return sum, nil
+	}
+
+	// stream a copy of the file so its cursor and any pending writes are left alone
+	return utils.ComputeChecksum(&File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		versionID:  f.versionID,
+	}, algorithm)
+}
+
+// setUploadChecksum sets the checksum of contents for algorithm on an upload, so S3 rejects it if the bytes it receives
+// don't match, and stores CRC32C and SHA-256 checksums with the object.
+func setUploadChecksum(uploader *s3manager.Uploader, input *s3manager.UploadInput, algorithm vfs.ChecksumAlgorithm,
+	contents []byte) error {
+	h, err := utils.NewChecksumHash(algorithm)
+	if err != nil {
+		return err
+	}
+
+	// S3 only verifies the checksum of the whole object when it's uploaded in one part.  Larger objects are uploaded
+	// in parts, each sent with its Content-MD5.
+	size := int64(len(contents))
+	if size > maxPutObjectSize {
+		return nil
+	}
+	if size > uploader.PartSize {
+		uploader.PartSize = size
+	}
+
+	_, _ = h.Write(contents)
+	sum := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	switch algorithm {
+	case vfs.ChecksumCRC32C:
+		input.ChecksumAlgorithm = aws.String(s3.ChecksumAlgorithmCrc32c)
+		input.ChecksumCRC32C = &sum
+	case vfs.ChecksumSHA256:
+		input.ChecksumAlgorithm = aws.String(s3.ChecksumAlgorithmSha256)
+		input.ChecksumSHA256 = &sum
+	case vfs.ChecksumMD5:
+		input.ContentMD5 = &sum
+	}
+	return nil
+}
+
+// verifiedBody returns the body of a GetObject of the whole object, wrapped so reading it to the end returns
+// vfs.ErrChecksumMismatch if its contents don't match the checksum stored by S3.  The body is returned as is when S3
+// stores no checksum for algorithm.
+func verifiedBody(output *s3.GetObjectOutput, algorithm vfs.ChecksumAlgorithm) (io.ReadCloser, error) {
+	h, err := utils.NewChecksumHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := storedChecksums{
+		crc32c:               output.ChecksumCRC32C,
+		sha256:               output.ChecksumSHA256,
+		eTag:                 output.ETag,
+		serverSideEncryption: output.ServerSideEncryption,
+		sseCustomerAlgorithm: output.SSECustomerAlgorithm,
+	}
+	if sum := stored.get(algorithm); sum != nil {
+		return utils.NewChecksumReader(output.Body, h, sum), nil
+	}
+	return output.Body, nil
+}
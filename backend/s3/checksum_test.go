@@ -0,0 +1,173 @@
+package s3
+
+import (
+	"crypto/md5" //nolint:gosec // MD5 is used for integrity checks, not security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/mocks"
+)
+
+type checksumTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	fs     *FileSystem
+	file   *File
+}
+
+func (ts *checksumTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.client, options: Options{ChecksumAlgorithm: vfs.ChecksumSHA256}}
+	file, err := ts.fs.NewFile("bucket", "/reports/daily.csv")
+	ts.Require().NoError(err)
+	ts.file = file.(*File)
+}
+
+func crc32c(contents string) []byte {
+	return binary.BigEndian.AppendUint32(nil, crc32.Checksum([]byte(contents), crc32.MakeTable(crc32.Castagnoli)))
+}
+
+func (ts *checksumTestSuite) TestStoredChecksums() {
+	md5Sum := md5.Sum([]byte("contents")) //nolint:gosec // MD5 is used for integrity checks, not security
+	eTag := `"` + hex.EncodeToString(md5Sum[:]) + `"`
+	crc := base64.StdEncoding.EncodeToString(crc32c("contents"))
+
+	tests := []struct {
+		name      string
+		stored    storedChecksums
+		algorithm vfs.ChecksumAlgorithm
+		expected  []byte
+	}{
+		{"crc32c", storedChecksums{crc32c: &crc}, vfs.ChecksumCRC32C, crc32c("contents")},
+		{"multipart crc32c", storedChecksums{crc32c: aws.String(crc + "-3")}, vfs.ChecksumCRC32C, nil},
+		{"missing sha256", storedChecksums{crc32c: &crc}, vfs.ChecksumSHA256, nil},
+		{"etag md5", storedChecksums{eTag: &eTag}, vfs.ChecksumMD5, md5Sum[:]},
+		{"multipart etag", storedChecksums{eTag: aws.String(`"abc-2"`)}, vfs.ChecksumMD5, nil},
+		{"kms etag", storedChecksums{eTag: &eTag, serverSideEncryption: aws.String(sseKMS)}, vfs.ChecksumMD5, nil},
+		{"sse-c etag", storedChecksums{eTag: &eTag, sseCustomerAlgorithm: aws.String(sseAES256)}, vfs.ChecksumMD5, nil},
+	}
+	for _, tt := range tests {
+		ts.Run(tt.name, func() {
+			ts.Equal(tt.expected, tt.stored.get(tt.algorithm))
+		})
+	}
+}
+
+func (ts *checksumTestSuite) TestUploadChecksum() {
+	sha := sha256.Sum256([]byte("a,b,c"))
+	ts.client.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.StringValue(input.ChecksumAlgorithm) == s3.ChecksumAlgorithmSha256 &&
+			aws.StringValue(input.ChecksumSHA256) == base64.StdEncoding.EncodeToString(sha[:])
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	_, err := ts.file.Write([]byte("a,b,c"))
+	ts.Require().NoError(err)
+	ts.NoError(ts.file.Close())
+	ts.client.AssertExpectations(ts.T())
+
+	ts.fs.options = Options{ChecksumAlgorithm: "CRC64"}
+	_, err = ts.file.Write([]byte("a,b,c"))
+	ts.Require().NoError(err)
+	ts.ErrorIs(ts.file.Close(), vfs.ErrChecksumAlgorithm)
+}
+
+func (ts *checksumTestSuite) TestSetUploadChecksum() {
+	uploader, input := s3manager.NewUploaderWithClient(ts.client), &s3manager.UploadInput{}
+
+	ts.Require().NoError(setUploadChecksum(uploader, input, vfs.ChecksumMD5, []byte("contents")))
+	md5Sum := md5.Sum([]byte("contents")) //nolint:gosec // MD5 is used for integrity checks, not security
+	ts.Equal(base64.StdEncoding.EncodeToString(md5Sum[:]), *input.ContentMD5)
+
+	input = &s3manager.UploadInput{}
+	large := make([]byte, 6*1024*1024)
+	ts.Require().NoError(setUploadChecksum(uploader, input, vfs.ChecksumCRC32C, large))
+	ts.Equal(s3.ChecksumAlgorithmCrc32c, *input.ChecksumAlgorithm)
+	ts.Equal(base64.StdEncoding.EncodeToString(crc32c(string(large))), *input.ChecksumCRC32C)
+	ts.Equal(int64(len(large)), uploader.PartSize, "checksummed uploads are sent in a single part")
+}
+
+func (ts *checksumTestSuite) TestReadVerifies() {
+	sha := sha256.Sum256([]byte("a,b,c"))
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(5)}, nil)
+	ts.client.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.StringValue(input.ChecksumMode) == s3.ChecksumModeEnabled && input.Range == nil
+	})).Return(&s3.GetObjectOutput{
+		Body:           io.NopCloser(strings.NewReader("a,b,c")),
+		ChecksumSHA256: aws.String(base64.StdEncoding.EncodeToString(sha[:])),
+	}, nil).Once()
+
+	contents, err := io.ReadAll(ts.file)
+	ts.NoError(err)
+	ts.Equal("a,b,c", string(contents))
+	ts.Require().NoError(ts.file.Close())
+
+	ts.client.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(&s3.GetObjectOutput{
+		Body:           io.NopCloser(strings.NewReader("a,b,x")),
+		ChecksumSHA256: aws.String(base64.StdEncoding.EncodeToString(sha[:])),
+	}, nil).Once()
+	_, err = io.ReadAll(ts.file)
+	ts.ErrorIs(err, vfs.ErrChecksumMismatch)
+	ts.Require().NoError(ts.file.Close())
+
+	// reads from an offset are not verified
+	ts.client.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return input.ChecksumMode == nil && aws.StringValue(input.Range) == "bytes=2-"
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("b,x"))}, nil).Once()
+	_, err = ts.file.Seek(2, io.SeekStart)
+	ts.Require().NoError(err)
+	contents, err = io.ReadAll(ts.file)
+	ts.NoError(err)
+	ts.Equal("b,x", string(contents))
+}
+
+func (ts *checksumTestSuite) TestChecksum() {
+	crc := base64.StdEncoding.EncodeToString(crc32c("a,b,c"))
+	ts.client.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return aws.StringValue(input.ChecksumMode) == s3.ChecksumModeEnabled
+	})).Return(&s3.HeadObjectOutput{
+		ContentLength:        aws.Int64(5),
+		ChecksumCRC32C:       &crc,
+		ETag:                 aws.String(`"1b2cf535f27731c974343645a3985328"`),
+		ServerSideEncryption: aws.String(sseKMS),
+	}, nil)
+
+	sum, err := ts.file.Checksum(vfs.ChecksumCRC32C)
+	ts.NoError(err)
+	ts.Equal(crc32c("a,b,c"), sum)
+
+	// the ETag of an SSE-KMS object isn't its MD5, so the object is streamed
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(5)}, nil)
+	ts.client.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("a,b,c"))}, nil).Once()
+	sum, err = ts.file.Checksum(vfs.ChecksumMD5)
+	ts.NoError(err)
+	md5Sum := md5.Sum([]byte("a,b,c")) //nolint:gosec // MD5 is used for integrity checks, not security
+	ts.Equal(md5Sum[:], sum)
+
+	_, err = ts.file.Checksum("CRC64")
+	ts.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+}
+
+func TestChecksumTestSuite(t *testing.T) {
+	suite.Run(t, new(checksumTestSuite))
+}
@@ -0,0 +1,96 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// ConcatenateFrom replaces the file's contents with the contents of sources, in order, implementing
+// vfs.Concatenator.  When every source can be copied natively to the file, as with CopyToFile, they are concatenated by
+// a multipart upload whose parts are copied from the sources with UploadPartCopy.  Since every part but the last must
+// be at least 5 MiB, the sources are streamed with utils.ConcatenateStreamed if any but the last is smaller, or if any
+// can't be copied natively.
+func (f *File) ConcatenateFrom(sources ...vfs.File) error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+	if len(sources) == 0 {
+		return vfs.ErrConcatenateNoSources
+	}
+
+	ranges, err := f.concatenateRanges(sources)
+	if err != nil {
+		return err
+	}
+	if ranges == nil {
+		return utils.ConcatenateStreamed(f, sources...)
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	createInput := newMultipartUploadInput(ranges[0].input)
+	upload, err := client.CreateMultipartUpload(createInput)
+	if err != nil {
+		return err
+	}
+
+	parts, err := uploadPartCopies(client, upload.UploadId, ranges, f.fileSystem.getOptions().CopyConcurrency)
+	if err != nil {
+		// the error of the failed part is more useful than one from aborting the upload
+		_, _ = client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:       createInput.Bucket,
+			Key:          createInput.Key,
+			UploadId:     upload.UploadId,
+			RequestPayer: createInput.RequestPayer,
+		})
+		return err
+	}
+
+	_, err = client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          createInput.Bucket,
+		Key:             createInput.Key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		RequestPayer:    createInput.RequestPayer,
+	})
+	return err
+}
+
+// concatenateRanges returns the ranges of sources to copy to the parts of a multipart upload concatenating them into
+// the file, or nil if they have to be streamed.  Empty sources are skipped.
+func (f *File) concatenateRanges(sources []vfs.File) ([]copyRange, error) {
+	var ranges []copyRange
+	for i, source := range sources {
+		s3Source, ok := source.(*File)
+		if !ok {
+			return nil, nil
+		}
+		input, err := s3Source.getCopyObjectInput(f)
+		if err != nil || input == nil {
+			return nil, err
+		}
+		head, err := s3Source.getHeadObject()
+		if err != nil {
+			return nil, err
+		}
+
+		size := aws.Int64Value(head.ContentLength)
+		if size == 0 {
+			continue
+		}
+		if size < minCopyPartitionSize && i < len(sources)-1 {
+			return nil, nil
+		}
+		partSize := copyPartitionSize(size, f.fileSystem.getOptions().CopyPartitionSize)
+		ranges = append(ranges, copyRanges(input, head.ETag, size, partSize)...)
+	}
+	if len(ranges) > maxCopyParts {
+		return nil, nil
+	}
+	return ranges, nil
+}
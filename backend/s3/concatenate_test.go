@@ -0,0 +1,157 @@
+package s3
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/mocks"
+)
+
+const mib = 1024 * 1024
+
+type concatenateTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	fs     *FileSystem
+	target *File
+}
+
+func (ts *concatenateTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.client, options: Options{AccessKeyID: "abc", CopyConcurrency: 2}}
+	target, err := ts.fs.NewFile("bucket", "/output/all.csv")
+	ts.Require().NoError(err)
+	ts.target = target.(*File)
+}
+
+// shard returns a file whose HEAD reports size bytes.
+func (ts *concatenateTestSuite) shard(name string, size int64) *File {
+	file, err := ts.fs.NewFile("bucket", name)
+	ts.Require().NoError(err)
+	ts.client.On("HeadObject", mock.MatchedBy(func(in *s3.HeadObjectInput) bool {
+		return aws.StringValue(in.Key) == name
+	})).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(size), ETag: aws.String(`"` + name + `"`)}, nil)
+	return file.(*File)
+}
+
+func (ts *concatenateTestSuite) TestConcatenateFrom() {
+	ts.Implements((*vfs.Concatenator)(nil), &File{}, "Does not implement the vfs.Concatenator interface")
+
+	sources := []vfs.File{
+		ts.shard("/shards/1.csv", 6*mib),
+		ts.shard("/shards/empty.csv", 0),
+		ts.shard("/shards/2.csv", 1024),
+	}
+	ts.client.On("CreateMultipartUpload", &s3.CreateMultipartUploadInput{
+		Bucket:               aws.String("bucket"),
+		Key:                  aws.String("/output/all.csv"),
+		ServerSideEncryption: aws.String(sseAES256),
+	}).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.client.On("UploadPartCopy", &s3.UploadPartCopyInput{
+		Bucket:            aws.String("bucket"),
+		Key:               aws.String("/output/all.csv"),
+		UploadId:          aws.String("upload"),
+		PartNumber:        aws.Int64(1),
+		CopySource:        aws.String("bucket%2Fshards%2F1.csv"),
+		CopySourceRange:   aws.String("bytes=0-6291455"),
+		CopySourceIfMatch: aws.String(`"/shards/1.csv"`),
+	}).Return(&s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("part1")}}, nil).Once()
+	ts.client.On("UploadPartCopy", &s3.UploadPartCopyInput{
+		Bucket:            aws.String("bucket"),
+		Key:               aws.String("/output/all.csv"),
+		UploadId:          aws.String("upload"),
+		PartNumber:        aws.Int64(2),
+		CopySource:        aws.String("bucket%2Fshards%2F2.csv"),
+		CopySourceRange:   aws.String("bytes=0-1023"),
+		CopySourceIfMatch: aws.String(`"/shards/2.csv"`),
+	}).Return(&s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("part2")}}, nil).Once()
+	ts.client.On("CompleteMultipartUpload", &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("/output/all.csv"),
+		UploadId: aws.String("upload"),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: []*s3.CompletedPart{
+			{ETag: aws.String("part1"), PartNumber: aws.Int64(1)},
+			{ETag: aws.String("part2"), PartNumber: aws.Int64(2)},
+		}},
+	}).Return(&s3.CompleteMultipartUploadOutput{}, nil).Once()
+
+	ts.NoError(ts.target.ConcatenateFrom(sources...))
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *concatenateTestSuite) TestConcatenateFromSmallSources() {
+	// parts but the last must be at least 5 MiB, so small sources are streamed
+	sources := []vfs.File{ts.shard("/shards/1.csv", 4), ts.shard("/shards/2.csv", 4)}
+	ts.client.On("GetObject", mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+		return aws.StringValue(in.Key) == "/shards/1.csv"
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("a,b\n"))}, nil).Once()
+	ts.client.On("GetObject", mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+		return aws.StringValue(in.Key) == "/shards/2.csv"
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("c,d\n"))}, nil).Once()
+
+	var uploaded string
+	ts.client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).Run(func(args mock.Arguments) {
+		body, err := io.ReadAll(args.Get(0).(*s3.PutObjectInput).Body)
+		ts.Require().NoError(err)
+		uploaded = string(body)
+	}).Return(newRequest(opPutObject, nil), &s3.PutObjectOutput{}).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	ts.NoError(ts.target.ConcatenateFrom(sources...))
+	ts.Equal("a,b\nc,d\n", uploaded)
+	ts.client.AssertNotCalled(ts.T(), "CreateMultipartUpload", mock.Anything)
+}
+
+func (ts *concatenateTestSuite) TestConcatenateFromAbortsOnError() {
+	sources := []vfs.File{ts.shard("/shards/1.csv", 6*mib), ts.shard("/shards/2.csv", 6*mib)}
+	ts.client.On("CreateMultipartUpload", mock.AnythingOfType("*s3.CreateMultipartUploadInput")).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.client.On("UploadPartCopy", mock.AnythingOfType("*s3.UploadPartCopyInput")).Return(nil, errors.New("part failed"))
+	ts.client.On("AbortMultipartUpload", &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("/output/all.csv"),
+		UploadId: aws.String("upload"),
+	}).Return(&s3.AbortMultipartUploadOutput{}, nil).Once()
+
+	ts.EqualError(ts.target.ConcatenateFrom(sources...), "part failed")
+	ts.client.AssertExpectations(ts.T())
+	ts.client.AssertNotCalled(ts.T(), "CompleteMultipartUpload", mock.Anything)
+}
+
+func (ts *concatenateTestSuite) TestConcatenateFromErrors() {
+	ts.ErrorIs(ts.target.ConcatenateFrom(), vfs.ErrConcatenateNoSources)
+
+	version, err := ts.target.AtVersion("v1")
+	ts.Require().NoError(err)
+	ts.ErrorIs(version.ConcatenateFrom(ts.target), ErrVersionReadOnly)
+}
+
+func (ts *concatenateTestSuite) TestCopyRanges() {
+	input := &s3.CopyObjectInput{}
+	bounds := func(ranges []copyRange) [][2]int64 {
+		var b [][2]int64
+		for _, r := range ranges {
+			b = append(b, [2]int64{r.start, r.end})
+		}
+		return b
+	}
+
+	ts.Equal([][2]int64{{0, 10*mib - 1}, {10 * mib, 20*mib - 1}}, bounds(copyRanges(input, nil, 20*mib, 10*mib)))
+	// a short last range is joined to the previous one
+	ts.Equal([][2]int64{{0, 10*mib - 1}, {10 * mib, 21*mib - 1}}, bounds(copyRanges(input, nil, 21*mib, 10*mib)))
+	// unless that would make a part larger than 5 GiB
+	ts.Equal([][2]int64{{0, 5*gib - 4*mib - 1}, {5*gib - 4*mib, 5*gib + mib - 1}},
+		bounds(copyRanges(input, nil, 5*gib+mib, maxCopyObjectSize)))
+}
+
+func TestConcatenate(t *testing.T) {
+	suite.Run(t, new(concatenateTestSuite))
+}
@@ -58,6 +58,134 @@ would have to be cast as s3.FileSystem to use the following:
 Canned ACL's can be passed in as an Option.  This string will be applied to all writes, moves, and copies.
 See https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl for values.
 
+# Server-side Encryption
+
+Objects are written with SSE-S3 ("AES256") by default, which can be turned off with DisableServerSideEncryption.
+SSE-KMS is used when Options.ServerSideEncryption is "aws:kms" or an SSEKMSKeyID is set, optionally with an
+SSEKMSEncryptionContext and SSEKMSBucketKeyEnabled:
+
+	fs = fs.WithOptions(
+	    s3.Options{
+	        SSEKMSKeyID:             "arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+	        SSEKMSEncryptionContext: map[string]string{"tenant": "1234"},
+	    },
+	)
+
+For SSE-C, set SSECustomerKey to a base64-encoded 256-bit key.  The key is sent with every write, read, HEAD and copy
+of the file system's objects.  For native copies, the source file's key is re-supplied to decrypt the object and the copy
+is encrypted according to the target file's options.
+
+# Storage Class and Tags
+
+Options.StorageClass (ie "GLACIER_IR" or "INTELLIGENT_TIERING") and Options.Tags are applied to all writes and native
+copies.  When no Tags are set, native copies keep the source object's tags.  Tags of an existing object can be read and
+replaced with the s3.File methods Tags() and SetTags():
+
+	file := vfsFile.(*s3.File)
+	tags, err := file.Tags()
+	...
+	tags["retention"] = "1y"
+	err = file.SetTags(tags)
+
+# Cross-account Copies
+
+Copies between s3 files are done natively, with CopyObject, when both file systems use the same credentials.  Otherwise
+the object is streamed through the process, unless the target's Options.CopyStrategy allows its credentials to read
+the source:
+
+  - CopyStrategyTargetCredentials always copies natively with the target's credentials.
+  - CopyStrategyProbe first HEADs the source object with the target's credentials, and copies natively if that is
+    allowed, or streams the object if it is forbidden.
+
+# Large Copies
+
+CopyObject can't copy objects larger than 5 GiB, so when it refuses a larger object the native copy uses a multipart
+upload whose parts are copied in parallel with UploadPartCopy.  The target's Options.CopyPartitionSize (default 256 MiB)
+and Options.CopyConcurrency (default 5) control the size of the parts and how many are copied at once.  As with
+CopyObject, the copy keeps the source object's metadata and, unless Options.Tags is set, its tags, and uses the target's
+ACL, server-side encryption and storage class.
+
+# Versions
+
+In buckets with versioning enabled, the versions of an object, including delete markers, can be listed with the s3.File
+method Versions().  AtVersion() returns a read-only File pinned to a version ID, whose reads, Size, LastModified and
+copies use that version, and RestoreVersion() makes an older version current again by copying it over the object:
+
+	file := vfsFile.(*s3.File)
+	versions, err := file.Versions()
+	...
+	old, err := file.AtVersion(versions[1].VersionID)
+	...
+	err = file.RestoreVersion(versions[1].VersionID)
+
+# Object Lock
+
+In buckets with Object Lock enabled, Options.ObjectLockMode ("GOVERNANCE" or "COMPLIANCE") and
+Options.ObjectLockRetention, which must be set together, retain objects written or natively copied for that long, and
+Options.ObjectLockLegalHold places a legal hold on them.  The protection of an existing object, or of a version
+returned by AtVersion(), can be read and changed with the s3.File methods ObjectLock(), SetRetention() and
+SetLegalHold():
+
+	file := vfsFile.(*s3.File)
+	lock, err := file.ObjectLock()
+	...
+	err = file.SetRetention(s3.ObjectLockRetentionModeGovernance, time.Now().AddDate(1, 0, 0))
+	...
+	err = file.SetLegalHold(true)
+
+Deleting a protected version returns an *s3.ObjectLockedError describing the protection.  Options.BypassGovernanceRetention
+allows deleting versions in GOVERNANCE mode, given the s3:BypassGovernanceRetention permission.
+
+# Requester Pays and Endpoints
+
+Options.RequestPayer acknowledges that the requester pays for requests to requester-pays buckets, and is sent with
+every read, HEAD, list, write, copy and delete.  Native copies send it if either the source or the target file system
+sets it.  Options.UseAccelerate sends requests to S3 Transfer Acceleration endpoints, for buckets with Transfer
+Acceleration enabled, and Options.UseDualStack sends them to dual-stack (IPv4 and IPv6) endpoints.  Both only apply
+to clients created from the options, not to clients passed to WithClient():
+
+	fs := s3.NewFileSystem().WithOptions(s3.Options{
+		Region:        "us-east-1",
+		RequestPayer:  true,
+		UseAccelerate: true,
+	})
+
+# Checksums
+
+Options.ChecksumAlgorithm (vfs.ChecksumCRC32C, vfs.ChecksumMD5 or vfs.ChecksumSHA256) enables integrity checks.  Writes
+send the checksum of their contents, as x-amz-checksum-crc32c, x-amz-checksum-sha256 or Content-MD5, and S3 rejects
+them if the bytes it receives don't match.  Such writes are uploaded in a single part, up to 5 GiB.  Reads of a whole
+file verify its contents against the checksum stored by S3, returning vfs.ErrChecksumMismatch once fully read.  The
+ETag is used as the MD5 of objects uploaded in one part without SSE-KMS or SSE-C.
+
+The s3.File method Checksum() returns the checksum stored by S3, or streams the object to compute it:
+
+	sum, err := vfsFile.(*s3.File).Checksum(vfs.ChecksumSHA256)
+
+# Preconditions
+
+Writers that mustn't overwrite each other's changes can use S3 conditional writes.  The s3.File methods IfMatch and
+IfNotExist return a File for the same object whose writes only succeed if the object's ETag is unchanged, or if the
+object doesn't exist, sending If-Match or If-None-Match headers.  Deletes of an IfMatch File are conditional too.
+Multipart uploads only send the headers with CompleteMultipartUpload, and copies onto a conditional File are streamed
+rather than native.  A rejected request returns a *vfs.PreconditionFailedError, matching vfs.ErrPreconditionFailed:
+
+	eTag, err := s3File.ETag()
+	conditional := s3File.IfMatch(eTag)
+	_, err = conditional.Write(updated)
+	if err = conditional.Close(); errors.Is(err, vfs.ErrPreconditionFailed) {
+		// another writer got there first, re-read the file and retry
+	}
+
+# Concatenation
+
+Many files can be concatenated into one without downloading and re-uploading them.  The s3.File method
+ConcatenateFrom() copies sources that can be copied natively to the file into the parts of a multipart upload with
+UploadPartCopy.  Every part but the last must be at least 5 MiB, so sources are streamed if any but the last is
+smaller.  utils.Concatenate uses it for s3 files and streams files of other file systems:
+
+	err := utils.Concatenate(output, shards...)
+
 # Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -27,9 +31,12 @@ type File struct {
 	fileSystem  *FileSystem
 	bucket      string
 	key         string
+	versionID   string
 	cursorPos   int64
 	reader      io.ReadCloser
 	writeBuffer *bytes.Buffer
+	ifMatch     string
+	ifNotExist  bool
 }
 
 // Info Functions
@@ -89,7 +96,8 @@ func (f *File) Location() vfs.Location {
 // Move/Copy Operations
 
 // CopyToFile puts the contents of File into the targetFile passed. Uses the S3 CopyObject
-// method if the target file is also on S3, otherwise uses io.CopyBuffer.
+// method if the target file is also on S3, or a multipart UploadPartCopy for objects larger
+// than 5 GiB, otherwise uses io.CopyBuffer.
 func (f *File) CopyToFile(file vfs.File) error {
 	// validate seek is at 0,0 before doing copy
 	if f.cursorPos != 0 {
@@ -102,14 +110,17 @@ func (f *File) CopyToFile(file vfs.File) error {
 		if err != nil {
 			return err
 		}
-		// if input is not nil, use it to natively copy object
+		// if input is not nil, use it to natively copy object with the target's credentials
 		if input != nil {
-			client, err := f.fileSystem.Client()
+			client, err := tf.fileSystem.Client()
 			if err != nil {
 				return err
 			}
 			_, err = client.CopyObject(input)
-			return err
+			if !isCopyTooLarge(err) {
+				return err
+			}
+			return f.multipartCopy(client, tf.fileSystem.getOptions(), input, err)
 		}
 	}
 
@@ -192,12 +203,21 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 		}
 	}
 
-	_, err = client.DeleteObject(&s3.DeleteObjectInput{
-		Key:    &f.key,
-		Bucket: &f.bucket,
-	})
+	deleteInput := &s3.DeleteObjectInput{
+		Key:          &f.key,
+		Bucket:       &f.bucket,
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
+	}
+	if f.versionID != "" {
+		deleteInput.VersionId = &f.versionID
+	}
+	bypassGovernance := f.fileSystem.getOptions().BypassGovernanceRetention
+	if bypassGovernance {
+		deleteInput.BypassGovernanceRetention = aws.Bool(true)
+	}
+	err = f.deleteObject(client, deleteInput)
 	if err != nil {
-		return err
+		return f.deleteError(client, f.versionID, err)
 	}
 
 	if deleteAllVersions {
@@ -207,12 +227,17 @@ func (f *File) Delete(opts ...options.DeleteOption) error {
 		}
 
 		for _, version := range objectVersions.Versions {
-			if _, err = client.DeleteObject(&s3.DeleteObjectInput{
-				Key:       &f.key,
-				Bucket:    &f.bucket,
-				VersionId: version.VersionId,
-			}); err != nil {
-				return err
+			input := &s3.DeleteObjectInput{
+				Key:          &f.key,
+				Bucket:       &f.bucket,
+				VersionId:    version.VersionId,
+				RequestPayer: f.fileSystem.getOptions().requestPayer(),
+			}
+			if bypassGovernance {
+				input.BypassGovernanceRetention = aws.Bool(true)
+			}
+			if _, err = client.DeleteObject(input); err != nil {
+				return f.deleteError(client, aws.StringValue(version.VersionId), err)
 			}
 		}
 	}
@@ -242,13 +267,23 @@ func (f *File) Close() error {
 		}
 
 		uploader := s3manager.NewUploaderWithClient(client)
-		uploadInput := uploadInput(f)
-		uploadInput.Body = f.writeBuffer
-
-		_, err = uploader.Upload(uploadInput)
+		input, err := uploadInput(f)
 		if err != nil {
 			return err
 		}
+		input.Body = f.writeBuffer
+		if algorithm := f.fileSystem.getOptions().ChecksumAlgorithm; algorithm != "" {
+			if err := setUploadChecksum(uploader, input, algorithm, f.writeBuffer.Bytes()); err != nil {
+				return err
+			}
+			input.Body = bytes.NewReader(f.writeBuffer.Bytes())
+		}
+		f.setUploadConditions(uploader)
+
+		_, err = uploader.Upload(input)
+		if err != nil {
+			return f.preconditionError(err)
+		}
 
 		f.writeBuffer = nil
 		return waitUntilFileExists(f, 5)
@@ -329,6 +364,10 @@ func seekTo(length, position, offset int64, whence int) (int64, error) {
 // PutObject to s3. The underlying implementation uses s3manager which will determine whether
 // it is appropriate to call PutObject, or initiate a multi-part upload.
 func (f *File) Write(data []byte) (res int, err error) {
+	if f.versionID != "" {
+		return 0, ErrVersionReadOnly
+	}
+
 	if f.writeBuffer == nil {
 		// note, initializing with 'data' and returning len(data), nil
 		// causes issues with some Write usages, notably csv.Writer
@@ -354,6 +393,10 @@ func (f *File) Write(data []byte) (res int, err error) {
 // Touch creates a zero-length file on the vfs.File if no File exists.  Update File's last modified timestamp.
 // Returns error if unable to touch File.
 func (f *File) Touch() error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+
 	// check if file exists
 	exists, err := f.Exists()
 	if err != nil {
@@ -378,6 +421,100 @@ func (f *File) Touch() error {
 	return nil
 }
 
+// Tags returns the tags of the s3 object.
+func (f *File) Tags() (map[string]string, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	input := new(s3.GetObjectTaggingInput).SetBucket(f.bucket).SetKey(f.key)
+	input.RequestPayer = f.fileSystem.getOptions().requestPayer()
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	output, err := client.GetObjectTagging(input)
+	if err != nil {
+		return nil, handleExistsError(err)
+	}
+
+	tags := make(map[string]string, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+// SetTags replaces the tags of the existing s3 object with tags.  An empty map removes all tags.
+func (f *File) SetTags(tags map[string]string) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		input := new(s3.DeleteObjectTaggingInput).SetBucket(f.bucket).SetKey(f.key)
+		if f.versionID != "" {
+			input.SetVersionId(f.versionID)
+		}
+		_, err = client.DeleteObjectTagging(input)
+		return handleExistsError(err)
+	}
+
+	tagSet := make([]*s3.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, new(s3.Tag).SetKey(k).SetValue(v))
+	}
+	// sort for a deterministic request
+	sort.Slice(tagSet, func(i, j int) bool { return *tagSet[i].Key < *tagSet[j].Key })
+
+	input := new(s3.PutObjectTaggingInput).
+		SetBucket(f.bucket).
+		SetKey(f.key).
+		SetTagging(new(s3.Tagging).SetTagSet(tagSet))
+	input.RequestPayer = f.fileSystem.getOptions().requestPayer()
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	_, err = client.PutObjectTagging(input)
+	return handleExistsError(err)
+}
+
+// SignedURL returns a presigned URL to download (http.MethodGet) or upload (http.MethodPut) the file, valid for
+// expiry, implementing vfs.URLSigner.  S3 rejects URLs whose expiry exceeds 7 days.  Requests to presigned URLs of objects encrypted
+// with SSE-C must include the SSE-C headers.
+func (f *File) SignedURL(method string, expiry time.Duration) (string, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return "", err
+	}
+
+	// requests to URLs of objects in requester-pays buckets must include the x-amz-request-payer header
+	requestPayer := f.fileSystem.getOptions().requestPayer()
+
+	var req *request.Request
+	switch method {
+	case http.MethodGet:
+		input := &s3.GetObjectInput{Bucket: aws.String(f.bucket), Key: aws.String(f.key), RequestPayer: requestPayer}
+		if f.versionID != "" {
+			input.VersionId = aws.String(f.versionID)
+		}
+		req, _ = client.GetObjectRequest(input)
+	case http.MethodPut:
+		if f.versionID != "" {
+			return "", ErrVersionReadOnly
+		}
+		req, _ = client.PutObjectRequest(&s3.PutObjectInput{Bucket: aws.String(f.bucket), Key: aws.String(f.key),
+			RequestPayer: requestPayer})
+	default:
+		return "", vfs.ErrSignedURLMethod
+	}
+
+	return req.Presign(expiry)
+}
+
 // URI returns the File's URI as a string.
 func (f *File) URI() string {
 	return utils.GetFileURI(f)
@@ -394,14 +531,70 @@ Private helper functions
 func (f *File) getAllObjectVersions(client s3iface.S3API) (*s3.ListObjectVersionsOutput, error) {
 	prefix := utils.RemoveLeadingSlash(f.key)
 	objVers, err := client.ListObjectVersions(&s3.ListObjectVersionsInput{
-		Bucket: &f.bucket,
-		Prefix: &prefix,
+		Bucket:       &f.bucket,
+		Prefix:       &prefix,
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
 	})
 	return objVers, err
 }
 
-func (f *File) getHeadObject() (*s3.HeadObjectOutput, error) {
+// targetCanCopyNatively returns whether the credentials of targetFile's file system, which differ from the file's, can
+// be used to natively copy the file, according to the target's CopyStrategy.
+func (f *File) targetCanCopyNatively(targetFile *File) (bool, error) {
+	switch strategy := targetFile.fileSystem.getOptions().CopyStrategy; strategy {
+	case CopyStrategyStream:
+		return false, nil
+	case CopyStrategyTargetCredentials:
+		return true, nil
+	case CopyStrategyProbe:
+		headObjectInput, err := f.headObjectInput()
+		if err != nil {
+			return false, err
+		}
+		client, err := targetFile.fileSystem.Client()
+		if err != nil {
+			return false, err
+		}
+		_, err = client.HeadObject(headObjectInput)
+		if err != nil {
+			var reqErr awserr.RequestFailure
+			if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusForbidden {
+				return false, nil
+			}
+			return false, handleExistsError(err)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown s3 CopyStrategy %q", strategy)
+	}
+}
+
+// headObjectInput returns the input of a HeadObject request for the file.
+func (f *File) headObjectInput() (*s3.HeadObjectInput, error) {
 	headObjectInput := new(s3.HeadObjectInput).SetKey(f.key).SetBucket(f.bucket)
+	headObjectInput.RequestPayer = f.fileSystem.getOptions().requestPayer()
+	if f.versionID != "" {
+		headObjectInput.SetVersionId(f.versionID)
+	}
+
+	// objects encrypted with SSE-C can only be read by re-supplying the key
+	key, err := f.fileSystem.getOptions().sseCustomerKey()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		headObjectInput.SetSSECustomerAlgorithm(sseAES256).SetSSECustomerKey(string(key))
+	}
+
+	return headObjectInput, nil
+}
+
+func (f *File) getHeadObject() (*s3.HeadObjectOutput, error) {
+	headObjectInput, err := f.headObjectInput()
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := f.fileSystem.Client()
 	if err != nil {
 		return nil, err
@@ -414,6 +607,14 @@ func (f *File) getHeadObject() (*s3.HeadObjectOutput, error) {
 
 // For copy from S3-to-S3 when credentials are the same between source and target, return *s3.CopyObjectInput or error
 func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error) {
+	if targetFile.versionID != "" {
+		return nil, ErrVersionReadOnly
+	}
+	// the target's conditions aren't applied to CopyObject, so conditional targets are written by streaming
+	if targetFile.conditional() {
+		return nil, nil
+	}
+
 	// first we must determine if we're using the same s3 credentials for source and target before doing a native copy
 	isSameAccount := false
 	var ACL string
@@ -441,23 +642,56 @@ func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error)
 		}
 	}
 
+	if !isSameAccount {
+		var err error
+		isSameAccount, err = f.targetCanCopyNatively(targetFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If both files use the same account, copy with native library. Otherwise, copy to disk
 	// first before pushing out to the target file's location.
 	if isSameAccount {
 		// PathEscape ensures we url-encode as required by the API, including double-encoding literals
 		copySourceKey := url.PathEscape(path.Join(f.bucket, f.key))
+		if f.versionID != "" {
+			copySourceKey += "?versionId=" + url.QueryEscape(f.versionID)
+		}
 
 		copyInput := new(s3.CopyObjectInput).
-			SetServerSideEncryption("AES256").
 			SetACL(ACL).
 			SetKey(targetFile.key).
 			SetBucket(targetFile.bucket).
 			SetCopySource(copySourceKey)
 
-		if f.fileSystem.options != nil && f.fileSystem.options.(Options).DisableServerSideEncryption {
-			copyInput.ServerSideEncryption = nil
+		// the requester pays if either the source or the target bucket is requester-pays
+		copyInput.RequestPayer = f.fileSystem.getOptions().requestPayer()
+		if copyInput.RequestPayer == nil {
+			copyInput.RequestPayer = targetFile.fileSystem.getOptions().requestPayer()
+		}
+
+		if err := setCopyObjectEncryption(copyInput, f.fileSystem.getOptions(), targetFile.fileSystem.getOptions()); err != nil {
+			return nil, err
 		}
 
+		// storage class and tags follow the target's options, as if it were written directly.  S3 keeps the source's
+		// tags unless they are replaced.
+		targetOpts := targetFile.fileSystem.getOptions()
+		if targetOpts.StorageClass != "" {
+			copyInput.SetStorageClass(targetOpts.StorageClass)
+		}
+		if tagging := targetOpts.tagging(); tagging != nil {
+			copyInput.SetTagging(*tagging).SetTaggingDirective(s3.TaggingDirectiveReplace)
+		}
+		lock, err := targetOpts.objectLockSettings(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		copyInput.ObjectLockMode = lock.mode
+		copyInput.ObjectLockRetainUntilDate = lock.retainUntil
+		copyInput.ObjectLockLegalHoldStatus = lock.legalHold
+
 		// validate copyInput
 		if err := copyInput.Validate(); err != nil {
 			return nil, err
@@ -471,29 +705,76 @@ func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error)
 }
 
 // TODO: need to provide an implementation-agnostic container for providing config options such as SSE
-func uploadInput(f *File) *s3manager.UploadInput {
-	sseType := "AES256"
+func uploadInput(f *File) (*s3manager.UploadInput, error) {
+	if f.fileSystem.options == nil {
+		f.fileSystem.options = Options{}
+	}
+	opts := f.fileSystem.getOptions()
+
+	sse, err := opts.sseSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	input := &s3manager.UploadInput{
-		Bucket:               &f.bucket,
-		Key:                  &f.key,
-		ServerSideEncryption: &sseType,
+		Bucket:                  &f.bucket,
+		Key:                     &f.key,
+		RequestPayer:            opts.requestPayer(),
+		ServerSideEncryption:    sse.algorithm,
+		SSEKMSKeyId:             sse.kmsKeyID,
+		SSEKMSEncryptionContext: sse.kmsContext,
+		BucketKeyEnabled:        sse.bucketKeyEnabled,
+		SSECustomerAlgorithm:    sse.customerAlgorithm,
+		SSECustomerKey:          sse.customerKey,
+	}
+
+	lock, err := opts.objectLockSettings(time.Now())
+	if err != nil {
+		return nil, err
 	}
+	input.ObjectLockMode = lock.mode
+	input.ObjectLockRetainUntilDate = lock.retainUntil
+	input.ObjectLockLegalHoldStatus = lock.legalHold
 
-	if f.fileSystem.options == nil {
-		f.fileSystem.options = Options{}
+	if opts.ACL != "" {
+		input.ACL = &opts.ACL
+	}
+	if opts.StorageClass != "" {
+		input.StorageClass = &opts.StorageClass
 	}
+	input.Tagging = opts.tagging()
+
+	return input, nil
+}
 
-	if f.fileSystem.options.(Options).DisableServerSideEncryption {
-		input.ServerSideEncryption = nil
+// setCopyObjectEncryption sets the server-side encryption of a native copy.  The source's SSE-C key, if any, is
+// re-supplied so S3 can decrypt it.  The copy is encrypted according to the target's options, as if it were written
+// directly, except that disabling server-side encryption on the source also disables it for the copy.
+func setCopyObjectEncryption(input *s3.CopyObjectInput, sourceOpts, targetOpts Options) error {
+	sourceKey, err := sourceOpts.sseCustomerKey()
+	if err != nil {
+		return err
+	}
+	if sourceKey != nil {
+		input.SetCopySourceSSECustomerAlgorithm(sseAES256).SetCopySourceSSECustomerKey(string(sourceKey))
 	}
 
-	if opts, ok := f.fileSystem.options.(Options); ok {
-		if opts.ACL != "" {
-			input.ACL = &opts.ACL
-		}
+	sse, err := targetOpts.sseSettings()
+	if err != nil {
+		return err
+	}
+	if sourceOpts.DisableServerSideEncryption {
+		sse.algorithm, sse.kmsKeyID, sse.kmsContext, sse.bucketKeyEnabled = nil, nil, nil, nil
 	}
 
-	return input
+	input.ServerSideEncryption = sse.algorithm
+	input.SSEKMSKeyId = sse.kmsKeyID
+	input.SSEKMSEncryptionContext = sse.kmsContext
+	input.BucketKeyEnabled = sse.bucketKeyEnabled
+	input.SSECustomerAlgorithm = sse.customerAlgorithm
+	input.SSECustomerKey = sse.customerKey
+
+	return nil
 }
 
 // WaitUntilFileExists attempts to ensure that a recently written file is available before moving on.  This is helpful for
@@ -544,11 +825,31 @@ func (f *File) getReader() (io.ReadCloser, error) {
 			f.reader = io.NopCloser(strings.NewReader(""))
 		} else {
 
-			// Create the request to get the object
+			// Create the request to get the object.  Reads of the whole object are verified against its stored
+			// checksum when a ChecksumAlgorithm is set, which S3 only returns for requests without a range.
 			input := new(s3.GetObjectInput).
 				SetBucket(f.bucket).
-				SetKey(f.key).
-				SetRange(fmt.Sprintf("bytes=%d-", f.cursorPos))
+				SetKey(f.key)
+			input.RequestPayer = f.fileSystem.getOptions().requestPayer()
+			checksumAlgorithm := f.fileSystem.getOptions().ChecksumAlgorithm
+			verify := checksumAlgorithm != "" && f.cursorPos == 0
+			if verify {
+				input.SetChecksumMode(s3.ChecksumModeEnabled)
+			} else {
+				input.SetRange(fmt.Sprintf("bytes=%d-", f.cursorPos))
+			}
+			if f.versionID != "" {
+				input.SetVersionId(f.versionID)
+			}
+
+			// objects encrypted with SSE-C can only be read by re-supplying the key
+			key, err := f.fileSystem.getOptions().sseCustomerKey()
+			if err != nil {
+				return nil, err
+			}
+			if key != nil {
+				input.SetSSECustomerAlgorithm(sseAES256).SetSSECustomerKey(string(key))
+			}
 
 			// Get the client
 			client, err := f.fileSystem.Client()
@@ -564,6 +865,12 @@ func (f *File) getReader() (io.ReadCloser, error) {
 
 			// Set the reader to the body of the object
 			f.reader = result.Body
+			if verify {
+				if f.reader, err = verifiedBody(result, checksumAlgorithm); err != nil {
+					_ = result.Body.Close()
+					return nil, err
+				}
+			}
 		}
 	}
 	return f.reader, nil
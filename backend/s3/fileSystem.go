@@ -119,6 +119,14 @@ func (fs *FileSystem) WithClient(client interface{}) *FileSystem {
 	return fs
 }
 
+// getOptions returns the file system's s3.Options, or the zero value if none are set.
+func (fs *FileSystem) getOptions() Options {
+	if opts, ok := fs.options.(Options); ok {
+		return opts
+	}
+	return Options{}
+}
+
 // NewFileSystem initializer for FileSystem struct accepts aws-sdk s3iface.S3API client and returns Filesystem or error.
 func NewFileSystem() *FileSystem {
 	return &FileSystem{}
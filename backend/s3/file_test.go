@@ -2,6 +2,7 @@ package s3
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"io"
 	"net/http"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -407,7 +410,7 @@ func (ts *fileTestSuite) TestCopyToLocation() {
 
 	l := &Location{
 		fileSystem: &FileSystem{
-			client:  &mocks.S3API{},
+			client:  s3Mock1,
 			options: defaultOptions,
 		},
 		bucket: "bucket",
@@ -483,7 +486,6 @@ func (ts *fileTestSuite) TestMoveToLocation() {
 	location := new(mocks.Location)
 	location.On("NewFile", mock.Anything).Return(f, nil)
 
-	s3apiMock.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(&s3.CopyObjectOutput{}, nil)
 	s3apiMock.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(&s3.DeleteObjectOutput{}, nil)
 
 	file, err := fs.NewFile("bucket", "/hello.txt")
@@ -517,6 +519,7 @@ func (ts *fileTestSuite) TestMoveToLocation() {
 	ts.NoError(err, "MoveToLocation error not expected")
 
 	s3apiMock.AssertExpectations(ts.T())
+	s3Mock1.AssertNumberOfCalls(ts.T(), "CopyObject", 2) // native copies use the target's client
 	location.AssertExpectations(ts.T())
 	mockLocation.AssertExpectations(ts.T())
 }
@@ -656,21 +659,236 @@ func (ts *fileTestSuite) TestStringer() {
 func (ts *fileTestSuite) TestUploadInput() {
 	fs = FileSystem{client: &mocks.S3API{}}
 	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
-	ts.Equal("AES256", *uploadInput(file.(*File)).ServerSideEncryption, "sse was set")
-	ts.Equal("/some/file/test.txt", *uploadInput(file.(*File)).Key, "key was set")
-	ts.Equal("mybucket", *uploadInput(file.(*File)).Bucket, "bucket was set")
+	input, err := uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Equal("AES256", *input.ServerSideEncryption, "sse was set")
+	ts.Equal("/some/file/test.txt", *input.Key, "key was set")
+	ts.Equal("mybucket", *input.Bucket, "bucket was set")
 }
 
 func (ts *fileTestSuite) TestUploadInputDisableSSE() {
 	fs := NewFileSystem().
 		WithOptions(Options{DisableServerSideEncryption: true})
 	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
-	input := uploadInput(file.(*File))
+	input, err := uploadInput(file.(*File))
+	ts.NoError(err)
 	ts.Nil(input.ServerSideEncryption, "sse was disabled")
 	ts.Equal("/some/file/test.txt", *input.Key, "key was set")
 	ts.Equal("mybucket", *input.Bucket, "bucket was set")
 }
 
+func (ts *fileTestSuite) TestUploadInputSSEKMS() {
+	fs := NewFileSystem().
+		WithOptions(Options{
+			SSEKMSKeyID:             "arn:aws:kms:us-east-1:123456789012:key/abc",
+			SSEKMSEncryptionContext: map[string]string{"tenant": "1234"},
+			SSEKMSBucketKeyEnabled:  true,
+		})
+	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
+	input, err := uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Equal("aws:kms", *input.ServerSideEncryption, "kms is implied by key id")
+	ts.Equal("arn:aws:kms:us-east-1:123456789012:key/abc", *input.SSEKMSKeyId)
+	ts.Equal(base64.StdEncoding.EncodeToString([]byte(`{"tenant":"1234"}`)), *input.SSEKMSEncryptionContext)
+	ts.True(*input.BucketKeyEnabled)
+	ts.Nil(input.SSECustomerKey)
+
+	// explicit algorithm without a key id uses the bucket's default kms key
+	fs = NewFileSystem().WithOptions(Options{ServerSideEncryption: "aws:kms"})
+	file, _ = fs.NewFile("mybucket", "/some/file/test.txt")
+	input, err = uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Equal("aws:kms", *input.ServerSideEncryption)
+	ts.Nil(input.SSEKMSKeyId)
+	ts.Nil(input.SSEKMSEncryptionContext)
+	ts.Nil(input.BucketKeyEnabled)
+}
+
+func (ts *fileTestSuite) TestUploadInputSSEC() {
+	key := bytes.Repeat([]byte("k"), 32)
+	fs := NewFileSystem().
+		WithOptions(Options{
+			SSECustomerKey: base64.StdEncoding.EncodeToString(key),
+			SSEKMSKeyID:    "ignored",
+		})
+	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
+	input, err := uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Equal("AES256", *input.SSECustomerAlgorithm)
+	ts.Equal(string(key), *input.SSECustomerKey)
+	ts.Nil(input.ServerSideEncryption, "sse-c can't be combined with other sse")
+	ts.Nil(input.SSEKMSKeyId)
+
+	fs = NewFileSystem().WithOptions(Options{SSECustomerKey: base64.StdEncoding.EncodeToString([]byte("short"))})
+	file, _ = fs.NewFile("mybucket", "/some/file/test.txt")
+	_, err = uploadInput(file.(*File))
+	ts.EqualError(err, "s3 SSECustomerKey must be a 256-bit key, got 40 bits")
+
+	fs = NewFileSystem().WithOptions(Options{SSECustomerKey: "not base64!"})
+	file, _ = fs.NewFile("mybucket", "/some/file/test.txt")
+	_, err = uploadInput(file.(*File))
+	ts.ErrorContains(err, "unable to decode s3 SSECustomerKey")
+}
+
+func (ts *fileTestSuite) TestSSECReads() {
+	key := bytes.Repeat([]byte("k"), 32)
+	client := &mocks.S3API{}
+	fs := &FileSystem{client: client, options: Options{SSECustomerKey: base64.StdEncoding.EncodeToString(key)}}
+	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
+
+	hasKey := func(algorithm, k *string) bool {
+		return algorithm != nil && *algorithm == "AES256" && k != nil && *k == string(key)
+	}
+	client.On("HeadObject", mock.MatchedBy(func(in *s3.HeadObjectInput) bool {
+		return hasKey(in.SSECustomerAlgorithm, in.SSECustomerKey)
+	})).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(5)}, nil)
+	client.On("GetObject", mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+		return hasKey(in.SSECustomerAlgorithm, in.SSECustomerKey)
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("hello"))}, nil)
+
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("hello", string(data))
+	client.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestGetCopyObjectEncryption() {
+	sourceKey := bytes.Repeat([]byte("s"), 32)
+	targetKey := bytes.Repeat([]byte("t"), 32)
+	newFile := func(opts Options, key string) *File {
+		return &File{
+			fileSystem: &FileSystem{client: s3apiMock, options: opts},
+			bucket:     "TestBucket",
+			key:        key,
+		}
+	}
+
+	// sse-c source is re-supplied, target is encrypted with its own key
+	source := newFile(Options{AccessKeyID: "abc", SSECustomerKey: base64.StdEncoding.EncodeToString(sourceKey)}, "/src.txt")
+	target := newFile(Options{AccessKeyID: "abc", SSECustomerKey: base64.StdEncoding.EncodeToString(targetKey)}, "/dst.txt")
+	input, err := source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Equal("AES256", *input.CopySourceSSECustomerAlgorithm)
+	ts.Equal(string(sourceKey), *input.CopySourceSSECustomerKey)
+	ts.Equal("AES256", *input.SSECustomerAlgorithm)
+	ts.Equal(string(targetKey), *input.SSECustomerKey)
+	ts.Nil(input.ServerSideEncryption)
+
+	// sse-c source copied to a kms target
+	target = newFile(Options{AccessKeyID: "abc", SSEKMSKeyID: "my-key"}, "/dst.txt")
+	input, err = source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Equal(string(sourceKey), *input.CopySourceSSECustomerKey)
+	ts.Nil(input.SSECustomerKey)
+	ts.Equal("aws:kms", *input.ServerSideEncryption)
+	ts.Equal("my-key", *input.SSEKMSKeyId)
+
+	// default options copy with AES256
+	source = newFile(Options{AccessKeyID: "abc"}, "/src.txt")
+	target = newFile(Options{AccessKeyID: "abc"}, "/dst.txt")
+	input, err = source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Equal("AES256", *input.ServerSideEncryption)
+	ts.Nil(input.CopySourceSSECustomerKey)
+	ts.Nil(input.SSEKMSKeyId)
+}
+
+func (ts *fileTestSuite) TestUploadInputStorageClassAndTags() {
+	fs := NewFileSystem().
+		WithOptions(Options{
+			StorageClass: s3.StorageClassGlacierIr,
+			Tags:         map[string]string{"team": "data eng", "cost-center": "42"},
+		})
+	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
+	input, err := uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Equal("GLACIER_IR", *input.StorageClass)
+	ts.Equal("cost-center=42&team=data+eng", *input.Tagging)
+
+	fs = NewFileSystem()
+	file, _ = fs.NewFile("mybucket", "/some/file/test.txt")
+	input, err = uploadInput(file.(*File))
+	ts.NoError(err)
+	ts.Nil(input.StorageClass)
+	ts.Nil(input.Tagging)
+}
+
+func (ts *fileTestSuite) TestGetCopyObjectStorageClassAndTags() {
+	source := &File{
+		fileSystem: &FileSystem{client: s3apiMock, options: Options{AccessKeyID: "abc"}},
+		bucket:     "TestBucket",
+		key:        "/src.txt",
+	}
+	target := &File{
+		fileSystem: &FileSystem{client: s3apiMock, options: Options{
+			AccessKeyID:  "abc",
+			StorageClass: s3.StorageClassIntelligentTiering,
+			Tags:         map[string]string{"archived": "true"},
+		}},
+		bucket: "TestBucket",
+		key:    "/dst.txt",
+	}
+
+	input, err := source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Equal("INTELLIGENT_TIERING", *input.StorageClass)
+	ts.Equal("archived=true", *input.Tagging)
+	ts.Equal(s3.TaggingDirectiveReplace, *input.TaggingDirective)
+
+	// without target tags, the source tags are kept
+	input, err = target.getCopyObjectInput(source)
+	ts.NoError(err)
+	ts.Nil(input.StorageClass)
+	ts.Nil(input.Tagging)
+	ts.Nil(input.TaggingDirective)
+}
+
+func (ts *fileTestSuite) TestTags() {
+	s3apiMock.On("GetObjectTagging", &s3.GetObjectTaggingInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("/some/path/to/file.txt"),
+	}).Return(&s3.GetObjectTaggingOutput{TagSet: []*s3.Tag{
+		{Key: aws.String("team"), Value: aws.String("data")},
+		{Key: aws.String("tier"), Value: aws.String("archive")},
+	}}, nil).Once()
+
+	tags, err := testFile.(*File).Tags()
+	ts.NoError(err)
+	ts.Equal(map[string]string{"team": "data", "tier": "archive"}, tags)
+
+	s3apiMock.On("GetObjectTagging", mock.AnythingOfType("*s3.GetObjectTaggingInput")).
+		Return(nil, awserr.New(s3.ErrCodeNoSuchKey, "key doesn't exist", nil)).Once()
+	_, err = testFile.(*File).Tags()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+
+	s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestSetTags() {
+	s3apiMock.On("PutObjectTagging", &s3.PutObjectTaggingInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("/some/path/to/file.txt"),
+		Tagging: &s3.Tagging{TagSet: []*s3.Tag{
+			{Key: aws.String("a"), Value: aws.String("1")},
+			{Key: aws.String("b"), Value: aws.String("2")},
+		}},
+	}).Return(&s3.PutObjectTaggingOutput{}, nil).Once()
+	ts.NoError(testFile.(*File).SetTags(map[string]string{"b": "2", "a": "1"}))
+
+	// an empty map removes all tags
+	s3apiMock.On("DeleteObjectTagging", &s3.DeleteObjectTaggingInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("/some/path/to/file.txt"),
+	}).Return(&s3.DeleteObjectTaggingOutput{}, nil).Once()
+	ts.NoError(testFile.(*File).SetTags(nil))
+
+	s3apiMock.On("PutObjectTagging", mock.AnythingOfType("*s3.PutObjectTaggingInput")).
+		Return(nil, errors.New("access denied")).Once()
+	ts.EqualError(testFile.(*File).SetTags(map[string]string{"a": "1"}), "access denied")
+
+	s3apiMock.AssertExpectations(ts.T())
+}
+
 func (ts *fileTestSuite) TestNewFile() {
 	fs := &FileSystem{}
 	// fs is nil
@@ -776,3 +994,137 @@ func (ts *fileTestSuite) TestSeekTo() {
 func TestFile(t *testing.T) {
 	suite.Run(t, new(fileTestSuite))
 }
+
+func (ts *fileTestSuite) TestGetCopyObjectCopyStrategy() {
+	source := &File{
+		fileSystem: &FileSystem{client: s3apiMock, options: Options{AccessKeyID: "source"}},
+		bucket:     "SourceBucket",
+		key:        "/src.txt",
+	}
+	targetClient := &mocks.S3API{}
+	target := &File{
+		fileSystem: &FileSystem{client: targetClient, options: Options{AccessKeyID: "target"}},
+		bucket:     "TargetBucket",
+		key:        "/dst.txt",
+	}
+
+	// by default, different credentials stream the copy
+	input, err := source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Nil(input)
+
+	target.fileSystem.options = Options{AccessKeyID: "target", CopyStrategy: CopyStrategyTargetCredentials}
+	input, err = source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.Require().NotNil(input)
+	ts.Equal("SourceBucket%2Fsrc.txt", *input.CopySource)
+
+	// probing HEADs the source with the target's client
+	target.fileSystem.options = Options{AccessKeyID: "target", CopyStrategy: CopyStrategyProbe}
+	targetClient.On("HeadObject", &s3.HeadObjectInput{Bucket: aws.String("SourceBucket"), Key: aws.String("/src.txt")}).
+		Return(&s3.HeadObjectOutput{}, nil).Once()
+	input, err = source.getCopyObjectInput(target)
+	ts.NoError(err)
+	ts.NotNil(input)
+
+	targetClient.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(nil, awserr.NewRequestFailure(awserr.New("Forbidden", "Forbidden", nil), 403, "")).Once()
+	input, err = source.getCopyObjectInput(target)
+	ts.NoError(err, "a forbidden probe should fall back to streaming")
+	ts.Nil(input)
+
+	targetClient.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(nil, awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), 404, "")).Once()
+	_, err = source.getCopyObjectInput(target)
+	ts.ErrorIs(err, vfs.ErrNotExist)
+
+	target.fileSystem.options = Options{AccessKeyID: "target", CopyStrategy: "sometimes"}
+	_, err = source.getCopyObjectInput(target)
+	ts.EqualError(err, `unknown s3 CopyStrategy "sometimes"`)
+
+	targetClient.AssertExpectations(ts.T())
+	s3apiMock.AssertNotCalled(ts.T(), "HeadObject", mock.Anything)
+}
+
+func (ts *fileTestSuite) TestRequestPayer() {
+	client := &mocks.S3API{}
+	payerFs := &FileSystem{client: client, options: Options{AccessKeyID: "abc", RequestPayer: true}}
+	file, err := payerFs.NewFile("bucket", "/datasets/part-0001.csv")
+	ts.Require().NoError(err)
+
+	client.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return aws.StringValue(input.RequestPayer) == s3.RequestPayerRequester
+	})).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(5)}, nil)
+	client.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.StringValue(input.RequestPayer) == s3.RequestPayerRequester
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("a,b,c"))}, nil)
+	client.On("ListObjects", mock.MatchedBy(func(input *s3.ListObjectsInput) bool {
+		return aws.StringValue(input.RequestPayer) == s3.RequestPayerRequester
+	})).Return(&s3.ListObjectsOutput{IsTruncated: aws.Bool(false)}, nil)
+	client.On("DeleteObject", mock.MatchedBy(func(input *s3.DeleteObjectInput) bool {
+		return aws.StringValue(input.RequestPayer) == s3.RequestPayerRequester
+	})).Return(&s3.DeleteObjectOutput{}, nil)
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	contents, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("a,b,c", string(contents))
+	_, err = file.Location().List()
+	ts.NoError(err)
+	ts.NoError(file.Delete())
+	client.AssertExpectations(ts.T())
+
+	// copies from a requester-pays bucket are paid for by the requester
+	target := &File{fileSystem: &FileSystem{client: client, options: Options{AccessKeyID: "abc"}}, bucket: "mine", key: "/copy.csv"}
+	input, err := file.(*File).getCopyObjectInput(target)
+	ts.Require().NoError(err)
+	ts.Equal(s3.RequestPayerRequester, aws.StringValue(input.RequestPayer))
+	input, err = target.getCopyObjectInput(file.(*File))
+	ts.Require().NoError(err)
+	ts.Equal(s3.RequestPayerRequester, aws.StringValue(input.RequestPayer))
+
+	uploadInput, err := uploadInput(file.(*File))
+	ts.Require().NoError(err)
+	ts.Equal(s3.RequestPayerRequester, aws.StringValue(uploadInput.RequestPayer))
+}
+
+func (ts *fileTestSuite) TestSignedURL() {
+	ts.Implements((*vfs.URLSigner)(nil), &File{}, "Does not implement the vfs.URLSigner interface")
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+	})
+	ts.Require().NoError(err)
+	fs := NewFileSystem().WithClient(s3.New(sess))
+	file, err := fs.NewFile("bucket", "/some/path/file.txt")
+	ts.Require().NoError(err)
+
+	signed, err := utils.SignedURL(file, http.MethodGet, time.Hour)
+	ts.Require().NoError(err)
+	u, err := url.Parse(signed)
+	ts.Require().NoError(err)
+	ts.Equal("bucket.s3.amazonaws.com", u.Host)
+	ts.Equal("/some/path/file.txt", u.Path)
+	ts.Equal("3600", u.Query().Get("X-Amz-Expires"))
+	ts.NotEmpty(u.Query().Get("X-Amz-Signature"))
+
+	signed, err = file.(*File).SignedURL(http.MethodPut, time.Minute)
+	ts.Require().NoError(err)
+	u, err = url.Parse(signed)
+	ts.Require().NoError(err)
+	ts.Equal("60", u.Query().Get("X-Amz-Expires"))
+
+	version, err := file.(*File).AtVersion("v1")
+	ts.Require().NoError(err)
+	signed, err = version.SignedURL(http.MethodGet, time.Hour)
+	ts.Require().NoError(err)
+	ts.Contains(signed, "versionId=v1")
+	_, err = version.SignedURL(http.MethodPut, time.Hour)
+	ts.ErrorIs(err, ErrVersionReadOnly)
+
+	_, err = file.(*File).SignedURL(http.MethodDelete, time.Hour)
+	ts.ErrorIs(err, vfs.ErrSignedURLMethod)
+}
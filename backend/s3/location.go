@@ -6,14 +6,19 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 
 	"github.com/c2fo/vfs/v6"
 	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/options/delete"
 	"github.com/c2fo/vfs/v6/utils"
 )
 
+// maxDeleteObjects is the maximum number of keys of a DeleteObjects request.
+const maxDeleteObjects = 1000
+
 // Location implements the vfs.Location interface specific to S3 fs.
 type Location struct {
 	fileSystem *FileSystem
@@ -152,6 +157,84 @@ func (l *Location) DeleteFile(fileName string, opts ...options.DeleteOption) err
 	return file.Delete(opts...)
 }
 
+// DeleteFiles deletes the files of the given relative paths at the location with DeleteObjects requests of up to 1000
+// keys each, implementing vfs.BulkDeleter.  Like DeleteObjects, files that don't exist are not reported as failures.
+// With the delete.DeleteAllVersions option, every version of each file is deleted, permanently removing it.
+func (l *Location) DeleteFiles(relFilePaths []string, opts ...options.DeleteOption) error {
+	var deleteAllVersions bool
+	for _, o := range opts {
+		switch o.(type) {
+		case delete.DeleteAllVersions:
+			deleteAllVersions = true
+		default:
+		}
+	}
+
+	failures := map[string]error{}
+	paths := map[string]string{} // relative paths of keys, to report failures
+	objects := make([]*s3.ObjectIdentifier, 0, len(relFilePaths))
+	for _, p := range relFilePaths {
+		f, err := l.NewFile(p)
+		if err != nil {
+			failures[p] = err
+			continue
+		}
+
+		key := utils.RemoveLeadingSlash(f.(*File).key)
+		paths[key] = p
+		if !deleteAllVersions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+			continue
+		}
+
+		versions, err := f.(*File).Versions()
+		if err != nil {
+			failures[p] = err
+			continue
+		}
+		for i := range versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key), VersionId: aws.String(versions[i].VersionID)})
+		}
+	}
+
+	client, err := l.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(objects); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(objects) {
+			end = len(objects)
+		}
+		batch := objects[start:end]
+
+		input := &s3.DeleteObjectsInput{
+			Bucket:       aws.String(l.bucket),
+			Delete:       &s3.Delete{Objects: batch, Quiet: aws.Bool(true)},
+			RequestPayer: l.fileSystem.getOptions().requestPayer(),
+		}
+		if l.fileSystem.getOptions().BypassGovernanceRetention {
+			input.BypassGovernanceRetention = aws.Bool(true)
+		}
+		output, err := client.DeleteObjects(input)
+		if err != nil {
+			for _, object := range batch {
+				failures[paths[*object.Key]] = err
+			}
+			continue
+		}
+		for _, e := range output.Errors {
+			failures[paths[aws.StringValue(e.Key)]] = awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil)
+		}
+	}
+
+	if len(failures) > 0 {
+		return &vfs.DeleteFilesError{Errors: failures}
+	}
+	return nil
+}
+
 // FileSystem returns a vfs.FileSystem interface of the location's underlying file system.
 func (l *Location) FileSystem() vfs.FileSystem {
 	return l.fileSystem
@@ -198,7 +281,9 @@ func (l *Location) fullLocationList(input *s3.ListObjectsInput, prefix string) (
 }
 
 func (l *Location) getListObjectsInput() *s3.ListObjectsInput {
-	return new(s3.ListObjectsInput).SetBucket(l.bucket).SetDelimiter("/")
+	input := new(s3.ListObjectsInput).SetBucket(l.bucket).SetDelimiter("/")
+	input.RequestPayer = l.fileSystem.getOptions().requestPayer()
+	return input
 }
 
 func getNamesFromObjectSlice(objects []*s3.Object, locationPrefix string) []string {
@@ -1,15 +1,20 @@
 package s3
 
 import (
+	"errors"
+	"fmt"
 	"path"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/c2fo/vfs/v6"
 	"github.com/c2fo/vfs/v6/mocks"
 	"github.com/c2fo/vfs/v6/options/delete"
 	"github.com/c2fo/vfs/v6/utils"
@@ -320,6 +325,81 @@ func (lt *locationTestSuite) TestDeleteFileWithDeleteAllVersionsOption() {
 	lt.s3apiMock.AssertNumberOfCalls(lt.T(), "DeleteObject", 3)
 }
 
+func (lt *locationTestSuite) TestDeleteFiles() {
+	lt.Implements((*vfs.BulkDeleter)(nil), &Location{}, "Does not implement the vfs.BulkDeleter interface")
+
+	paths := make([]string, 0, 1500)
+	for i := 0; i < 1500; i++ {
+		paths = append(paths, fmt.Sprintf("file%d.txt", i))
+	}
+	paths = append(paths, "/bad/path.txt")
+
+	var batchSizes []int
+	lt.s3apiMock.On("DeleteObjects", mock.MatchedBy(func(in *s3.DeleteObjectsInput) bool {
+		return *in.Bucket == "bucket" && *in.Delete.Quiet
+	})).Run(func(args mock.Arguments) {
+		in := args.Get(0).(*s3.DeleteObjectsInput)
+		batchSizes = append(batchSizes, len(in.Delete.Objects))
+		lt.Equal("some/path/file0.txt", *in.Delete.Objects[0].Key, "keys should not have a leading slash")
+	}).Return(&s3.DeleteObjectsOutput{}, nil).Once()
+	lt.s3apiMock.On("DeleteObjects", mock.AnythingOfType("*s3.DeleteObjectsInput")).Run(func(args mock.Arguments) {
+		batchSizes = append(batchSizes, len(args.Get(0).(*s3.DeleteObjectsInput).Delete.Objects))
+	}).Return(&s3.DeleteObjectsOutput{
+		Errors: []*s3.Error{{Key: aws.String("some/path/file1234.txt"), Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")}},
+	}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/some/path/")
+	lt.Require().NoError(err)
+	err = utils.DeleteFiles(loc, paths)
+	lt.Equal([]int{1000, 500}, batchSizes)
+
+	var deleteErr *vfs.DeleteFilesError
+	lt.Require().ErrorAs(err, &deleteErr)
+	lt.Len(deleteErr.Errors, 2)
+	lt.EqualError(deleteErr.Errors["file1234.txt"], "AccessDenied: Access Denied")
+	lt.Contains(deleteErr.Errors, "/bad/path.txt")
+	lt.s3apiMock.AssertExpectations(lt.T())
+
+	// a failed request fails every key of its batch
+	lt.s3apiMock.On("DeleteObjects", mock.AnythingOfType("*s3.DeleteObjectsInput")).
+		Return(nil, errors.New("request failed")).Once()
+	err = utils.DeleteFiles(loc, []string{"a.txt", "b.txt"})
+	lt.Require().ErrorAs(err, &deleteErr)
+	lt.Equal(map[string]error{"a.txt": errors.New("request failed"), "b.txt": errors.New("request failed")}, deleteErr.Errors)
+}
+
+func (lt *locationTestSuite) TestDeleteFilesWithDeleteAllVersionsOption() {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t3 := t2.Add(time.Hour)
+	lt.s3apiMock.On("ListObjectVersions", mock.AnythingOfType("*s3.ListObjectVersionsInput")).
+		Return(&s3.ListObjectVersionsOutput{
+			Versions: []*s3.ObjectVersion{
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v1"), LastModified: &t1},
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v2"), LastModified: &t2},
+			},
+			DeleteMarkers: []*s3.DeleteMarkerEntry{
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v3"), LastModified: &t3},
+			},
+		}, nil).Once()
+	lt.s3apiMock.On("DeleteObjects", &s3.DeleteObjectsInput{
+		Bucket: aws.String("bucket"),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v3")},
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v2")},
+				{Key: aws.String("some/path/a.txt"), VersionId: aws.String("v1")},
+			},
+			Quiet: aws.Bool(true),
+		},
+	}).Return(&s3.DeleteObjectsOutput{}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/some/path/")
+	lt.Require().NoError(err)
+	lt.NoError(loc.(*Location).DeleteFiles([]string{"a.txt"}, delete.WithDeleteAllVersions()))
+	lt.s3apiMock.AssertExpectations(lt.T())
+}
+
 func TestLocation(t *testing.T) {
 	suite.Run(t, new(locationTestSuite))
 }
@@ -0,0 +1,270 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+const (
+	// maxCopyObjectSize is the size of the largest object S3 copies with a single CopyObject request.
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	// maxCopyParts is the maximum number of parts of a multipart upload.
+	maxCopyParts = 10000
+	// minCopyPartitionSize is the minimum size of every part of a multipart upload but the last.
+	minCopyPartitionSize = 5 * 1024 * 1024
+
+	defaultCopyPartitionSize = 256 * 1024 * 1024
+	defaultCopyConcurrency   = 5
+
+	errCodeInvalidRequest = "InvalidRequest"
+	errCodeEntityTooLarge = "EntityTooLarge"
+)
+
+// isCopyTooLarge reports whether err is the error S3 returns when CopyObject is asked to copy more than 5 GiB.
+func isCopyTooLarge(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	return awsErr.Code() == errCodeInvalidRequest || awsErr.Code() == errCodeEntityTooLarge
+}
+
+// multipartCopy natively copies an object too large for CopyObject, by copying ranges of it in parallel with
+// UploadPartCopy, with the part size and concurrency of the target's opts.  The multipart upload is created from input
+// so that ACL, server-side encryption, storage class and tags are the same as for a single CopyObject request.  Like
+// CopyObject, it keeps the source object's metadata and, unless the target's options set Tags, its tags.  If the
+// source object isn't larger than 5 GiB, copyErr, the error CopyObject failed with, is returned instead.
+func (f *File) multipartCopy(client s3iface.S3API, opts Options, input *s3.CopyObjectInput, copyErr error) error {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return err
+	}
+	size := aws.Int64Value(head.ContentLength)
+	if size <= maxCopyObjectSize {
+		return copyErr
+	}
+
+	createInput, err := f.createMultipartCopyInput(input, head)
+	if err != nil {
+		return err
+	}
+
+	upload, err := client.CreateMultipartUpload(createInput)
+	if err != nil {
+		return err
+	}
+
+	ranges := copyRanges(input, head.ETag, size, copyPartitionSize(size, opts.CopyPartitionSize))
+	parts, err := uploadPartCopies(client, upload.UploadId, ranges, opts.CopyConcurrency)
+	if err != nil {
+		// the error of the failed part is more useful than one from aborting the upload
+		_, _ = client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:       input.Bucket,
+			Key:          input.Key,
+			UploadId:     upload.UploadId,
+			RequestPayer: input.RequestPayer,
+		})
+		return err
+	}
+
+	_, err = client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          input.Bucket,
+		Key:             input.Key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		RequestPayer:    input.RequestPayer,
+	})
+	return err
+}
+
+// createMultipartCopyInput returns the input to create the multipart upload of a copy described by input, with the
+// metadata of the source object described by head.
+func (f *File) createMultipartCopyInput(input *s3.CopyObjectInput, head *s3.HeadObjectOutput) (*s3.CreateMultipartUploadInput, error) {
+	createInput := newMultipartUploadInput(input)
+	createInput.CacheControl = head.CacheControl
+	createInput.ContentDisposition = head.ContentDisposition
+	createInput.ContentEncoding = head.ContentEncoding
+	createInput.ContentLanguage = head.ContentLanguage
+	createInput.ContentType = head.ContentType
+	createInput.Metadata = head.Metadata
+	if expires, err := http.ParseTime(aws.StringValue(head.Expires)); err == nil {
+		createInput.Expires = &expires
+	}
+
+	// CopyObject keeps the source's tags unless they are replaced, a multipart upload has to set them explicitly
+	if createInput.Tagging == nil {
+		tags, err := f.Tags()
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) > 0 {
+			createInput.Tagging = aws.String(encodeTags(tags))
+		}
+	}
+
+	return createInput, nil
+}
+
+// newMultipartUploadInput returns the input to create a multipart upload to the target of input, with the same ACL,
+// server-side encryption, storage class, tags and Object Lock settings.
+func newMultipartUploadInput(input *s3.CopyObjectInput) *s3.CreateMultipartUploadInput {
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket:                    input.Bucket,
+		Key:                       input.Key,
+		ServerSideEncryption:      input.ServerSideEncryption,
+		SSEKMSKeyId:               input.SSEKMSKeyId,
+		SSEKMSEncryptionContext:   input.SSEKMSEncryptionContext,
+		BucketKeyEnabled:          input.BucketKeyEnabled,
+		SSECustomerAlgorithm:      input.SSECustomerAlgorithm,
+		SSECustomerKey:            input.SSECustomerKey,
+		StorageClass:              input.StorageClass,
+		Tagging:                   input.Tagging,
+		ObjectLockMode:            input.ObjectLockMode,
+		ObjectLockRetainUntilDate: input.ObjectLockRetainUntilDate,
+		ObjectLockLegalHoldStatus: input.ObjectLockLegalHoldStatus,
+		RequestPayer:              input.RequestPayer,
+	}
+	if aws.StringValue(input.ACL) != "" {
+		createInput.ACL = input.ACL
+	}
+	return createInput
+}
+
+// copyRange is a byte range of a source object copied to one part of a multipart upload.
+type copyRange struct {
+	// input describes the source and target of the copy, and their server-side encryption
+	input *s3.CopyObjectInput
+	// eTag is the source's ETag, so parts of different versions of it aren't mixed
+	eTag       *string
+	start, end int64
+}
+
+// copyRanges splits a source object of size bytes into ranges of partSize bytes to copy.  A last range shorter than
+// the minimum part size is joined to the previous one, or lengthened at its expense, so the ranges can be followed by
+// parts of other sources.
+func copyRanges(input *s3.CopyObjectInput, eTag *string, size, partSize int64) []copyRange {
+	ranges := make([]copyRange, 0, (size+partSize-1)/partSize)
+	for start := int64(0); start < size; start += partSize {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		ranges = append(ranges, copyRange{input: input, eTag: eTag, start: start, end: end})
+	}
+
+	if n := len(ranges); n > 1 && ranges[n-1].end-ranges[n-1].start+1 < minCopyPartitionSize {
+		if ranges[n-1].end-ranges[n-2].start+1 <= maxCopyObjectSize {
+			ranges[n-2].end = ranges[n-1].end
+			ranges = ranges[:n-1]
+		} else {
+			ranges[n-1].start = ranges[n-1].end + 1 - minCopyPartitionSize
+			ranges[n-2].end = ranges[n-1].start - 1
+		}
+	}
+	return ranges
+}
+
+// uploadPartCopies copies ranges to the parts of the multipart upload uploadID, in order, with concurrency
+// goroutines.  The completed parts are returned in order.
+func uploadPartCopies(client s3iface.S3API, uploadID *string, ranges []copyRange, concurrency int) ([]*s3.CompletedPart,
+	error) {
+	if concurrency <= 0 {
+		concurrency = defaultCopyConcurrency
+	}
+
+	partNumbers := make(chan int64)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		parts    = make([]*s3.CompletedPart, 0, len(ranges))
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range partNumbers {
+				if failed() {
+					continue
+				}
+
+				r := ranges[partNumber-1]
+				input := r.input
+				output, err := client.UploadPartCopy(&s3.UploadPartCopyInput{
+					Bucket:                         input.Bucket,
+					Key:                            input.Key,
+					UploadId:                       uploadID,
+					PartNumber:                     aws.Int64(partNumber),
+					CopySource:                     input.CopySource,
+					CopySourceRange:                aws.String(fmt.Sprintf("bytes=%d-%d", r.start, r.end)),
+					CopySourceIfMatch:              r.eTag, // fail rather than mix parts of different objects
+					CopySourceSSECustomerAlgorithm: input.CopySourceSSECustomerAlgorithm,
+					CopySourceSSECustomerKey:       input.CopySourceSSECustomerKey,
+					SSECustomerAlgorithm:           input.SSECustomerAlgorithm,
+					SSECustomerKey:                 input.SSECustomerKey,
+					RequestPayer:                   input.RequestPayer,
+				})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					parts = append(parts, &s3.CompletedPart{
+						ETag:       output.CopyPartResult.ETag,
+						PartNumber: aws.Int64(partNumber),
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for partNumber := int64(1); partNumber <= int64(len(ranges)); partNumber++ {
+		partNumbers <- partNumber
+	}
+	close(partNumbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return *parts[i].PartNumber < *parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+// copyPartitionSize returns the part size to copy an object of size bytes with, preferring partitionSize but staying
+// within the part size and part count limits of multipart uploads.
+func copyPartitionSize(size, partitionSize int64) int64 {
+	if partitionSize <= 0 {
+		partitionSize = defaultCopyPartitionSize
+	}
+	if partitionSize < minCopyPartitionSize {
+		partitionSize = minCopyPartitionSize
+	}
+	if partitionSize > maxCopyObjectSize {
+		partitionSize = maxCopyObjectSize
+	}
+	if minSize := (size + maxCopyParts - 1) / maxCopyParts; partitionSize < minSize {
+		partitionSize = minSize
+	}
+	return partitionSize
+}
@@ -0,0 +1,160 @@
+package s3
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/mocks"
+)
+
+const gib = 1024 * 1024 * 1024
+
+type multipartCopyTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	source *File
+	target *File
+}
+
+func (ts *multipartCopyTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	fs := &FileSystem{client: ts.client, options: Options{
+		AccessKeyID:       "abc",
+		ACL:               "bucket-owner-full-control",
+		CopyPartitionSize: 2 * gib,
+		CopyConcurrency:   2,
+	}}
+	source, err := fs.NewFile("bucket", "/some/big.file")
+	ts.Require().NoError(err)
+	ts.source = source.(*File)
+	target, err := fs.NewFile("other", "/copy/big.file")
+	ts.Require().NoError(err)
+	ts.target = target.(*File)
+}
+
+// copyTooLargeErr is the error S3 returns when CopyObject is asked to copy more than 5 GiB.
+var copyTooLargeErr = awserr.NewRequestFailure(awserr.New(errCodeInvalidRequest,
+	"The specified copy source is larger than the maximum allowable size for a copy source: 5368709120", nil), 400, "")
+
+func (ts *multipartCopyTestSuite) TestSmallObjectUsesCopyObject() {
+	ts.client.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(&s3.CopyObjectOutput{}, nil).Once()
+
+	ts.NoError(ts.source.CopyToFile(ts.target))
+	ts.client.AssertExpectations(ts.T())
+	ts.client.AssertNotCalled(ts.T(), "HeadObject", mock.Anything)
+	ts.client.AssertNotCalled(ts.T(), "CreateMultipartUpload", mock.Anything)
+}
+
+func (ts *multipartCopyTestSuite) TestInvalidRequestForSmallObject() {
+	invalidErr := awserr.NewRequestFailure(awserr.New(errCodeInvalidRequest, "This copy request is illegal", nil), 400, "")
+	ts.client.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(nil, invalidErr).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(maxCopyObjectSize)}, nil).Once()
+
+	ts.Equal(invalidErr, ts.source.CopyToFile(ts.target))
+	ts.client.AssertExpectations(ts.T())
+	ts.client.AssertNotCalled(ts.T(), "CreateMultipartUpload", mock.Anything)
+}
+
+func (ts *multipartCopyTestSuite) TestMultipartCopy() {
+	// the part size and concurrency are the target's options
+	ts.source.fileSystem = &FileSystem{client: ts.client, options: Options{AccessKeyID: "abc"}}
+
+	ts.client.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(nil, copyTooLargeErr).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(5*gib + 1),
+		ContentType:   aws.String("application/octet-stream"),
+		Metadata:      map[string]*string{"Owner": aws.String("me")},
+		ETag:          aws.String(`"etag"`),
+	}, nil).Once()
+	ts.client.On("GetObjectTagging", mock.AnythingOfType("*s3.GetObjectTaggingInput")).Return(&s3.GetObjectTaggingOutput{
+		TagSet: []*s3.Tag{{Key: aws.String("team"), Value: aws.String("data")}},
+	}, nil).Once()
+	ts.client.On("CreateMultipartUpload", &s3.CreateMultipartUploadInput{
+		Bucket:               aws.String("other"),
+		Key:                  aws.String("/copy/big.file"),
+		ACL:                  aws.String("bucket-owner-full-control"),
+		ServerSideEncryption: aws.String(sseAES256),
+		Tagging:              aws.String("team=data"),
+		ContentType:          aws.String("application/octet-stream"),
+		Metadata:             map[string]*string{"Owner": aws.String("me")},
+	}).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+
+	var mu sync.Mutex
+	ranges := map[int64]string{}
+	ts.client.On("UploadPartCopy", mock.MatchedBy(func(in *s3.UploadPartCopyInput) bool {
+		return aws.StringValue(in.CopySource) == "bucket%2Fsome%2Fbig.file" &&
+			aws.StringValue(in.UploadId) == "upload" &&
+			aws.StringValue(in.CopySourceIfMatch) == `"etag"`
+	})).Run(func(args mock.Arguments) {
+		in := args.Get(0).(*s3.UploadPartCopyInput)
+		mu.Lock()
+		defer mu.Unlock()
+		ranges[*in.PartNumber] = *in.CopySourceRange
+	}).Return(func(in *s3.UploadPartCopyInput) *s3.UploadPartCopyOutput {
+		return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: in.CopySourceRange}}
+	}, nil).Times(3)
+	ts.client.On("CompleteMultipartUpload", &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String("other"),
+		Key:      aws.String("/copy/big.file"),
+		UploadId: aws.String("upload"),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: []*s3.CompletedPart{
+			{ETag: aws.String("bytes=0-2147483647"), PartNumber: aws.Int64(1)},
+			{ETag: aws.String("bytes=2147483648-4294967295"), PartNumber: aws.Int64(2)},
+			{ETag: aws.String("bytes=4294967296-5368709120"), PartNumber: aws.Int64(3)},
+		}},
+	}).Return(&s3.CompleteMultipartUploadOutput{}, nil).Once()
+
+	ts.NoError(ts.source.CopyToFile(ts.target))
+	ts.Equal(map[int64]string{
+		1: "bytes=0-2147483647",
+		2: "bytes=2147483648-4294967295",
+		3: "bytes=4294967296-5368709120",
+	}, ranges)
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *multipartCopyTestSuite) TestMultipartCopyAbortsOnError() {
+	ts.target.fileSystem = &FileSystem{client: ts.client, options: Options{
+		AccessKeyID: "abc",
+		Tags:        map[string]string{"copied": "true"},
+	}}
+
+	ts.client.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(nil, copyTooLargeErr).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(6 * gib)}, nil).Once()
+	ts.client.On("CreateMultipartUpload", mock.MatchedBy(func(in *s3.CreateMultipartUploadInput) bool {
+		return aws.StringValue(in.Tagging) == "copied=true"
+	})).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.client.On("UploadPartCopy", mock.AnythingOfType("*s3.UploadPartCopyInput")).
+		Return(nil, errors.New("part failed"))
+	ts.client.On("AbortMultipartUpload", &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String("other"),
+		Key:      aws.String("/copy/big.file"),
+		UploadId: aws.String("upload"),
+	}).Return(&s3.AbortMultipartUploadOutput{}, nil).Once()
+
+	ts.EqualError(ts.source.CopyToFile(ts.target), "part failed")
+	ts.client.AssertExpectations(ts.T())
+	ts.client.AssertNotCalled(ts.T(), "GetObjectTagging", mock.Anything)
+	ts.client.AssertNotCalled(ts.T(), "CompleteMultipartUpload", mock.Anything)
+}
+
+func (ts *multipartCopyTestSuite) TestCopyPartitionSize() {
+	ts.Equal(int64(defaultCopyPartitionSize), copyPartitionSize(6*gib, 0))
+	ts.Equal(int64(minCopyPartitionSize), copyPartitionSize(6*gib, 1024))
+	ts.Equal(int64(maxCopyObjectSize), copyPartitionSize(6*gib, 10*gib))
+	// 5 TiB can't be copied in 10,000 parts of 256 MiB
+	ts.Equal(int64(5*1024*gib/maxCopyParts+1), copyPartitionSize(5*1024*gib, 0))
+}
+
+func TestMultipartCopy(t *testing.T) {
+	suite.Run(t, new(multipartCopyTestSuite))
+}
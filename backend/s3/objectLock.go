@@ -0,0 +1,178 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// errCodeNoSuchObjectLockConfiguration is returned when an object has no retention or legal hold set.
+const errCodeNoSuchObjectLockConfiguration = "NoSuchObjectLockConfiguration"
+
+// ObjectLock describes the Object Lock protection of an s3 object version.
+type ObjectLock struct {
+	// Mode is the retention mode, s3.ObjectLockRetentionModeGovernance or s3.ObjectLockRetentionModeCompliance, or
+	// empty if no retention is set.
+	Mode string
+	// RetainUntil is when the retention expires.  It is the zero time if no retention is set.
+	RetainUntil time.Time
+	// LegalHold is whether a legal hold is placed on the version.
+	LegalHold bool
+}
+
+// Locked returns whether the retention or the legal hold prevent the version from being deleted at time t.
+func (l ObjectLock) Locked(t time.Time) bool {
+	return l.LegalHold || (l.Mode != "" && l.RetainUntil.After(t))
+}
+
+// ObjectLockedError is returned by Delete when Object Lock retention or a legal hold prevents deleting a version of
+// an s3 object.
+type ObjectLockedError struct {
+	URI       string
+	VersionID string
+	Lock      ObjectLock
+	Err       error
+}
+
+// Error returns a string representation of the error, describing the protection preventing the delete.
+func (e *ObjectLockedError) Error() string {
+	var protection string
+	switch {
+	case e.Lock.LegalHold:
+		protection = "a legal hold"
+	default:
+		protection = fmt.Sprintf("%s retention until %s", e.Lock.Mode, e.Lock.RetainUntil.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("unable to delete version %s of %s, it is protected by %s: %s", e.VersionID, e.URI, protection,
+		e.Err)
+}
+
+// Unwrap returns the error returned by S3.
+func (e *ObjectLockedError) Unwrap() error {
+	return e.Err
+}
+
+// ObjectLock returns the retention and legal hold of the file's object, or of the version the file refers to.  The
+// bucket must have Object Lock enabled.
+func (f *File) ObjectLock() (*ObjectLock, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+	return f.getObjectLock(client, f.versionID)
+}
+
+// SetRetention sets the retention mode and retain-until date of the file's object, or of the version the file refers
+// to.  Shortening or removing GOVERNANCE retention requires Options.BypassGovernanceRetention.
+func (f *File) SetRetention(mode string, retainUntil time.Time) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectRetentionInput{
+		Bucket:       aws.String(f.bucket),
+		Key:          aws.String(f.key),
+		Retention:    &s3.ObjectLockRetention{Mode: aws.String(mode), RetainUntilDate: aws.Time(retainUntil)},
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
+	}
+	if f.versionID != "" {
+		input.VersionId = aws.String(f.versionID)
+	}
+	if f.fileSystem.getOptions().BypassGovernanceRetention {
+		input.BypassGovernanceRetention = aws.Bool(true)
+	}
+	_, err = client.PutObjectRetention(input)
+	return handleExistsError(err)
+}
+
+// SetLegalHold places or removes a legal hold on the file's object, or on the version the file refers to.
+func (f *File) SetLegalHold(hold bool) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	status := s3.ObjectLockLegalHoldStatusOff
+	if hold {
+		status = s3.ObjectLockLegalHoldStatusOn
+	}
+	input := &s3.PutObjectLegalHoldInput{
+		Bucket:       aws.String(f.bucket),
+		Key:          aws.String(f.key),
+		LegalHold:    &s3.ObjectLockLegalHold{Status: aws.String(status)},
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
+	}
+	if f.versionID != "" {
+		input.VersionId = aws.String(f.versionID)
+	}
+	_, err = client.PutObjectLegalHold(input)
+	return handleExistsError(err)
+}
+
+// getObjectLock returns the retention and legal hold of the given version of the file's object, or of the current
+// version if versionID is empty.
+func (f *File) getObjectLock(client s3iface.S3API, versionID string) (*ObjectLock, error) {
+	var version *string
+	if versionID != "" {
+		version = aws.String(versionID)
+	}
+
+	lock := &ObjectLock{}
+	retention, err := client.GetObjectRetention(&s3.GetObjectRetentionInput{
+		Bucket:       aws.String(f.bucket),
+		Key:          aws.String(f.key),
+		VersionId:    version,
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
+	})
+	switch {
+	case isNoObjectLockConfiguration(err):
+	case err != nil:
+		return nil, handleExistsError(err)
+	case retention.Retention != nil:
+		lock.Mode = aws.StringValue(retention.Retention.Mode)
+		lock.RetainUntil = aws.TimeValue(retention.Retention.RetainUntilDate)
+	}
+
+	legalHold, err := client.GetObjectLegalHold(&s3.GetObjectLegalHoldInput{
+		Bucket:       aws.String(f.bucket),
+		Key:          aws.String(f.key),
+		VersionId:    version,
+		RequestPayer: f.fileSystem.getOptions().requestPayer(),
+	})
+	switch {
+	case isNoObjectLockConfiguration(err):
+	case err != nil:
+		return nil, handleExistsError(err)
+	case legalHold.LegalHold != nil:
+		lock.LegalHold = aws.StringValue(legalHold.LegalHold.Status) == s3.ObjectLockLegalHoldStatusOn
+	}
+
+	return lock, nil
+}
+
+// deleteError returns an *ObjectLockedError if deleting the given version failed because of its Object Lock
+// protection, or err otherwise.
+func (f *File) deleteError(client s3iface.S3API, versionID string, err error) error {
+	var awsErr awserr.Error
+	if versionID == "" || !errors.As(err, &awsErr) || awsErr.Code() != "AccessDenied" {
+		return err
+	}
+
+	// S3 doesn't distinguish Object Lock denials from others, the version's protection tells them apart
+	lock, lockErr := f.getObjectLock(client, versionID)
+	if lockErr != nil || !lock.Locked(time.Now()) {
+		return err
+	}
+	return &ObjectLockedError{URI: f.URI(), VersionID: versionID, Lock: *lock, Err: err}
+}
+
+func isNoObjectLockConfiguration(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == errCodeNoSuchObjectLockConfiguration
+}
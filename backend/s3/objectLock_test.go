@@ -0,0 +1,209 @@
+package s3
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/mocks"
+	"github.com/c2fo/vfs/v6/options/delete"
+)
+
+type objectLockTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	fs     *FileSystem
+	file   *File
+}
+
+func (ts *objectLockTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.client, options: Options{AccessKeyID: "abc"}}
+	file, err := ts.fs.NewFile("bucket", "/audit/log.txt")
+	ts.Require().NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *objectLockTestSuite) TestUploadInput() {
+	ts.fs.options = Options{
+		ObjectLockMode:      s3.ObjectLockModeCompliance,
+		ObjectLockRetention: 24 * time.Hour,
+		ObjectLockLegalHold: true,
+	}
+	before := time.Now()
+	input, err := uploadInput(ts.file)
+	ts.Require().NoError(err)
+	ts.Equal("COMPLIANCE", *input.ObjectLockMode)
+	ts.WithinDuration(before.Add(24*time.Hour), *input.ObjectLockRetainUntilDate, time.Minute)
+	ts.Equal("ON", *input.ObjectLockLegalHoldStatus)
+
+	ts.fs.options = Options{ObjectLockMode: s3.ObjectLockModeGovernance}
+	_, err = uploadInput(ts.file)
+	ts.EqualError(err, "s3 ObjectLockMode and ObjectLockRetention must be set together")
+
+	ts.fs.options = Options{}
+	input, err = uploadInput(ts.file)
+	ts.Require().NoError(err)
+	ts.Nil(input.ObjectLockMode)
+	ts.Nil(input.ObjectLockRetainUntilDate)
+	ts.Nil(input.ObjectLockLegalHoldStatus)
+}
+
+func (ts *objectLockTestSuite) TestCopyObjectInput() {
+	target := &File{
+		fileSystem: &FileSystem{client: ts.client, options: Options{
+			AccessKeyID:         "abc",
+			ObjectLockMode:      s3.ObjectLockModeGovernance,
+			ObjectLockRetention: time.Hour,
+		}},
+		bucket: "bucket",
+		key:    "/audit/copy.txt",
+	}
+
+	input, err := ts.file.getCopyObjectInput(target)
+	ts.Require().NoError(err)
+	ts.Equal("GOVERNANCE", *input.ObjectLockMode)
+	ts.NotNil(input.ObjectLockRetainUntilDate)
+	ts.Nil(input.ObjectLockLegalHoldStatus)
+}
+
+func (ts *objectLockTestSuite) TestObjectLock() {
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.client.On("GetObjectRetention", &s3.GetObjectRetentionInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("/audit/log.txt"),
+	}).Return(&s3.GetObjectRetentionOutput{
+		Retention: &s3.ObjectLockRetention{Mode: aws.String("COMPLIANCE"), RetainUntilDate: &until},
+	}, nil).Once()
+	ts.client.On("GetObjectLegalHold", &s3.GetObjectLegalHoldInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("/audit/log.txt"),
+	}).Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no legal hold", nil)).Once()
+
+	lock, err := ts.file.ObjectLock()
+	ts.NoError(err)
+	ts.Equal(&ObjectLock{Mode: "COMPLIANCE", RetainUntil: until}, lock)
+	ts.True(lock.Locked(until.Add(-time.Second)))
+	ts.False(lock.Locked(until))
+
+	// versions are looked up by ID
+	version, err := ts.file.AtVersion("v1")
+	ts.Require().NoError(err)
+	ts.client.On("GetObjectRetention", mock.MatchedBy(func(in *s3.GetObjectRetentionInput) bool {
+		return aws.StringValue(in.VersionId) == "v1"
+	})).Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no retention", nil)).Once()
+	ts.client.On("GetObjectLegalHold", mock.MatchedBy(func(in *s3.GetObjectLegalHoldInput) bool {
+		return aws.StringValue(in.VersionId) == "v1"
+	})).Return(&s3.GetObjectLegalHoldOutput{
+		LegalHold: &s3.ObjectLockLegalHold{Status: aws.String("ON")},
+	}, nil).Once()
+
+	lock, err = version.ObjectLock()
+	ts.NoError(err)
+	ts.Equal(&ObjectLock{LegalHold: true}, lock)
+	ts.True(lock.Locked(time.Now()))
+
+	ts.client.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).
+		Return(nil, errors.New("retention failed")).Once()
+	_, err = ts.file.ObjectLock()
+	ts.EqualError(err, "retention failed")
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *objectLockTestSuite) TestSetRetentionAndLegalHold() {
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.fs.options = Options{AccessKeyID: "abc", BypassGovernanceRetention: true}
+	ts.client.On("PutObjectRetention", &s3.PutObjectRetentionInput{
+		Bucket:                    aws.String("bucket"),
+		Key:                       aws.String("/audit/log.txt"),
+		Retention:                 &s3.ObjectLockRetention{Mode: aws.String("GOVERNANCE"), RetainUntilDate: &until},
+		BypassGovernanceRetention: aws.Bool(true),
+	}).Return(&s3.PutObjectRetentionOutput{}, nil).Once()
+	ts.NoError(ts.file.SetRetention(s3.ObjectLockRetentionModeGovernance, until))
+
+	ts.client.On("PutObjectLegalHold", &s3.PutObjectLegalHoldInput{
+		Bucket:    aws.String("bucket"),
+		Key:       aws.String("/audit/log.txt"),
+		LegalHold: &s3.ObjectLockLegalHold{Status: aws.String("OFF")},
+	}).Return(&s3.PutObjectLegalHoldOutput{}, nil).Once()
+	ts.NoError(ts.file.SetLegalHold(false))
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *objectLockTestSuite) TestDeleteLockedVersion() {
+	until := time.Now().Add(time.Hour)
+	denied := awserr.New("AccessDenied", "Access Denied", nil)
+	version, err := ts.file.AtVersion("v1")
+	ts.Require().NoError(err)
+
+	ts.client.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(nil, denied).Once()
+	ts.client.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).
+		Return(&s3.GetObjectRetentionOutput{
+			Retention: &s3.ObjectLockRetention{Mode: aws.String("COMPLIANCE"), RetainUntilDate: &until},
+		}, nil).Once()
+	ts.client.On("GetObjectLegalHold", mock.AnythingOfType("*s3.GetObjectLegalHoldInput")).
+		Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no legal hold", nil)).Once()
+
+	err = version.Delete()
+	var lockedErr *ObjectLockedError
+	ts.Require().ErrorAs(err, &lockedErr)
+	ts.Equal("v1", lockedErr.VersionID)
+	ts.Equal("COMPLIANCE", lockedErr.Lock.Mode)
+	ts.ErrorIs(err, denied)
+	ts.Contains(err.Error(), "protected by COMPLIANCE retention until")
+
+	// denials not caused by a lock are returned as is
+	ts.client.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(nil, denied).Once()
+	ts.client.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).
+		Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no retention", nil)).Once()
+	ts.client.On("GetObjectLegalHold", mock.AnythingOfType("*s3.GetObjectLegalHoldInput")).
+		Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no legal hold", nil)).Once()
+	ts.Equal(denied, version.Delete())
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *objectLockTestSuite) TestDeleteAllVersionsLocked() {
+	ts.fs.options = Options{AccessKeyID: "abc", BypassGovernanceRetention: true}
+	denied := awserr.New("AccessDenied", "Access Denied", nil)
+
+	ts.client.On("DeleteObject", &s3.DeleteObjectInput{
+		Bucket:                    aws.String("bucket"),
+		Key:                       aws.String("/audit/log.txt"),
+		BypassGovernanceRetention: aws.Bool(true),
+	}).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	ts.client.On("ListObjectVersions", mock.AnythingOfType("*s3.ListObjectVersionsInput")).
+		Return(&s3.ListObjectVersionsOutput{
+			Versions: []*s3.ObjectVersion{{Key: aws.String("audit/log.txt"), VersionId: aws.String("v1")}},
+		}, nil).Once()
+	ts.client.On("DeleteObject", &s3.DeleteObjectInput{
+		Bucket:                    aws.String("bucket"),
+		Key:                       aws.String("/audit/log.txt"),
+		VersionId:                 aws.String("v1"),
+		BypassGovernanceRetention: aws.Bool(true),
+	}).Return(nil, denied).Once()
+	ts.client.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).
+		Return(nil, awserr.New(errCodeNoSuchObjectLockConfiguration, "no retention", nil)).Once()
+	ts.client.On("GetObjectLegalHold", mock.AnythingOfType("*s3.GetObjectLegalHoldInput")).
+		Return(&s3.GetObjectLegalHoldOutput{LegalHold: &s3.ObjectLockLegalHold{Status: aws.String("ON")}}, nil).Once()
+
+	err := ts.file.Delete(delete.WithDeleteAllVersions())
+	var lockedErr *ObjectLockedError
+	ts.Require().ErrorAs(err, &lockedErr)
+	ts.True(lockedErr.Lock.LegalHold)
+	ts.Contains(err.Error(), "protected by a legal hold")
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func TestObjectLock(t *testing.T) {
+	suite.Run(t, new(objectLockTestSuite))
+}
@@ -1,7 +1,12 @@
 package s3
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -10,10 +15,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/c2fo/vfs/v6"
 )
 
 // Options holds s3-specific options.  Currently only client options are used.
@@ -26,10 +34,204 @@ type Options struct {
 	ACL                         string `json:"acl,omitempty"`
 	ForcePathStyle              bool   `json:"forcePathStyle,omitempty"`
 	DisableServerSideEncryption bool   `json:"disableServerSideEncryption,omitempty"`
-	Retry                       request.Retryer
-	MaxRetries                  int
-	FileBufferSize              int   // Buffer size in bytes used with utils.TouchCopyBuffered
-	DownloadPartitionSize       int64 // Partition size in bytes used to multipart download large files using S3 Downloader
+	// ServerSideEncryption is the SSE algorithm used for writes and copies, "AES256" (default) or "aws:kms".  It is
+	// implied to be "aws:kms" when SSEKMSKeyID is set.
+	ServerSideEncryption    string            `json:"serverSideEncryption,omitempty"`
+	SSEKMSKeyID             string            `json:"sseKmsKeyId,omitempty"`             // KMS key ID or ARN
+	SSEKMSEncryptionContext map[string]string `json:"sseKmsEncryptionContext,omitempty"` // KMS encryption context
+	SSEKMSBucketKeyEnabled  bool              `json:"sseKmsBucketKeyEnabled,omitempty"`  // use an S3 Bucket Key for SSE-KMS
+	// SSECustomerKey is a base64-encoded 256-bit key used for SSE-C.  When set, it is sent with every write, read,
+	// HEAD and copy of the file system's objects and takes precedence over the other server-side encryption options.
+	SSECustomerKey string `json:"sseCustomerKey,omitempty"`
+	// StorageClass is the storage class of objects written or natively copied, ie "GLACIER_IR" or
+	// "INTELLIGENT_TIERING".  S3 uses "STANDARD" when empty.
+	StorageClass string `json:"storageClass,omitempty"`
+	// Tags are set on objects written or natively copied.  When empty, native copies keep the source object's tags.
+	Tags map[string]string `json:"tags,omitempty"`
+	// ObjectLockMode is the Object Lock retention mode, "GOVERNANCE" or "COMPLIANCE", of objects written or natively
+	// copied.  It must be set along with ObjectLockRetention.
+	ObjectLockMode string `json:"objectLockMode,omitempty"`
+	// ObjectLockRetention is how long objects written or natively copied are retained, from the time of the write.
+	ObjectLockRetention time.Duration `json:"objectLockRetention,omitempty"`
+	// ObjectLockLegalHold places a legal hold on objects written or natively copied.
+	ObjectLockLegalHold bool `json:"objectLockLegalHold,omitempty"`
+	// BypassGovernanceRetention allows deleting versions, and shortening retention, of objects locked in GOVERNANCE
+	// mode.  It requires the s3:BypassGovernanceRetention permission.
+	BypassGovernanceRetention bool `json:"bypassGovernanceRetention,omitempty"`
+	// CopyStrategy determines whether copies to this file system from one with different credentials are done
+	// natively.  See CopyStrategy.
+	CopyStrategy CopyStrategy `json:"copyStrategy,omitempty"`
+	// RequestPayer acknowledges that the requester pays for requests to requester-pays buckets.  It is sent with every
+	// read, HEAD, list, write, copy and delete of the file system's objects.
+	RequestPayer bool `json:"requestPayer,omitempty"`
+	// UseAccelerate sends requests to S3 Transfer Acceleration endpoints.  The buckets must have Transfer Acceleration
+	// enabled and their names can't contain periods.
+	UseAccelerate bool `json:"useAccelerate,omitempty"`
+	// UseDualStack sends requests to dual-stack endpoints, reachable over IPv4 and IPv6.
+	UseDualStack bool `json:"useDualStack,omitempty"`
+	// ChecksumAlgorithm, vfs.ChecksumCRC32C, vfs.ChecksumMD5 or vfs.ChecksumSHA256, enables integrity checks.  Writes
+	// send the checksum of their contents for S3 to verify, and reads of a whole file verify its contents against the
+	// checksum stored by S3.
+	ChecksumAlgorithm     vfs.ChecksumAlgorithm `json:"checksumAlgorithm,omitempty"`
+	Retry                 request.Retryer
+	MaxRetries            int
+	FileBufferSize        int   // Buffer size in bytes used with utils.TouchCopyBuffered
+	DownloadPartitionSize int64 // Partition size in bytes used to multipart download large files using S3 Downloader
+	CopyPartitionSize     int64 // Partition size in bytes used to multipart copy files larger than 5 GiB (default 256 MiB)
+	CopyConcurrency       int   // Number of parts copied in parallel when multipart copying (default 5)
+}
+
+// CopyStrategy determines how a file is copied to an s3 file whose file system uses different credentials.  Native
+// copies are always performed with the target's credentials.
+type CopyStrategy string
+
+const (
+	// CopyStrategyStream, the default, streams the object through the process unless the source and target use the
+	// same credentials.
+	CopyStrategyStream CopyStrategy = ""
+	// CopyStrategyTargetCredentials always copies natively.  The target's credentials must be allowed to read the
+	// source object.
+	CopyStrategyTargetCredentials CopyStrategy = "targetCredentials"
+	// CopyStrategyProbe copies natively if the target's credentials are allowed to HEAD the source object, and streams
+	// the object through the process otherwise.
+	CopyStrategyProbe CopyStrategy = "probe"
+)
+
+const (
+	sseAES256 = "AES256"
+	sseKMS    = "aws:kms"
+)
+
+// requestPayer returns the RequestPayer of requests made with the options, or nil if the requester doesn't pay.
+func (o Options) requestPayer() *string {
+	if !o.RequestPayer {
+		return nil
+	}
+	return aws.String(s3.RequestPayerRequester)
+}
+
+// sseCustomerKey returns the decoded SSE-C key, or nil if none is set.
+func (o Options) sseCustomerKey() ([]byte, error) {
+	if o.SSECustomerKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(o.SSECustomerKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode s3 SSECustomerKey: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("s3 SSECustomerKey must be a 256-bit key, got %d bits", len(key)*8)
+	}
+	return key, nil
+}
+
+// sseKMSEncryptionContext returns the SSE-KMS encryption context as base64-encoded JSON, as required by the API, or
+// an empty string if none is set.
+func (o Options) sseKMSEncryptionContext() (string, error) {
+	if len(o.SSEKMSEncryptionContext) == 0 {
+		return "", nil
+	}
+
+	ctx, err := json.Marshal(o.SSEKMSEncryptionContext)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ctx), nil
+}
+
+// tagging returns Tags encoded as a URL query string, as required by the API, or nil if no tags are set.
+func (o Options) tagging() *string {
+	if len(o.Tags) == 0 {
+		return nil
+	}
+	return aws.String(encodeTags(o.Tags))
+}
+
+// encodeTags returns tags encoded as a URL query string, sorted by key.
+func encodeTags(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// objectLockSettings holds the Object Lock parameters sent when writing or copying an object.  Nil fields are omitted
+// from the request.
+type objectLockSettings struct {
+	mode        *string
+	retainUntil *time.Time
+	legalHold   *string
+}
+
+// objectLockSettings returns the Object Lock parameters for writes and copies made at time now.
+func (o Options) objectLockSettings(now time.Time) (*objectLockSettings, error) {
+	settings := &objectLockSettings{}
+
+	if (o.ObjectLockMode == "") != (o.ObjectLockRetention == 0) {
+		return nil, errors.New("s3 ObjectLockMode and ObjectLockRetention must be set together")
+	}
+	if o.ObjectLockMode != "" {
+		settings.mode = aws.String(o.ObjectLockMode)
+		settings.retainUntil = aws.Time(now.Add(o.ObjectLockRetention))
+	}
+	if o.ObjectLockLegalHold {
+		settings.legalHold = aws.String(s3.ObjectLockLegalHoldStatusOn)
+	}
+
+	return settings, nil
+}
+
+// sseSettings holds the server-side encryption parameters sent when writing or copying an object.  Nil fields are
+// omitted from the request.
+type sseSettings struct {
+	algorithm         *string
+	kmsKeyID          *string
+	kmsContext        *string
+	bucketKeyEnabled  *bool
+	customerAlgorithm *string
+	customerKey       *string
+}
+
+// sseSettings returns the server-side encryption parameters for writes and copies.  An SSE-C key takes precedence over
+// SSE-S3 and SSE-KMS since S3 doesn't accept both.
+func (o Options) sseSettings() (*sseSettings, error) {
+	settings := &sseSettings{}
+
+	key, err := o.sseCustomerKey()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case key != nil:
+		settings.customerAlgorithm = aws.String(sseAES256)
+		settings.customerKey = aws.String(string(key))
+	case o.DisableServerSideEncryption:
+	case o.ServerSideEncryption == sseAES256, o.ServerSideEncryption == "" && o.SSEKMSKeyID == "":
+		settings.algorithm = aws.String(sseAES256)
+	default:
+		settings.algorithm = aws.String(sseKMS)
+		if o.ServerSideEncryption != "" {
+			settings.algorithm = aws.String(o.ServerSideEncryption)
+		}
+		if o.SSEKMSKeyID != "" {
+			settings.kmsKeyID = aws.String(o.SSEKMSKeyID)
+		}
+		kmsContext, err := o.sseKMSEncryptionContext()
+		if err != nil {
+			return nil, err
+		}
+		if kmsContext != "" {
+			settings.kmsContext = aws.String(kmsContext)
+		}
+		if o.SSEKMSBucketKeyEnabled {
+			settings.bucketKeyEnabled = aws.Bool(true)
+		}
+	}
+
+	return settings, nil
 }
 
 // getClient setup S3 client
@@ -53,6 +255,13 @@ func getClient(opt Options) (s3iface.S3API, error) {
 	// use specific endpoint, otherwise, will use aws "default endpoint resolver" based on region
 	awsConfig.WithEndpoint(opt.Endpoint)
 
+	if opt.UseAccelerate {
+		awsConfig.WithS3UseAccelerate(true)
+	}
+	if opt.UseDualStack {
+		awsConfig.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled
+	}
+
 	if opt.Retry != nil {
 		awsConfig.Retryer = opt.Retry
 	}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/suite"
 )
@@ -46,6 +47,35 @@ func (o *optionsTestSuite) TestGetClient() {
 	o.Equal("set-by-envvar", *client.(*s3.S3).Config.Region, "region is set by env var")
 }
 
+func (o *optionsTestSuite) TestGetClientEndpoints() {
+	tests := map[string]struct {
+		opts         Options
+		expectedHost string
+	}{
+		"default":    {Options{Region: "us-west-2"}, "bucket.s3.us-west-2.amazonaws.com"},
+		"accelerate": {Options{Region: "us-west-2", UseAccelerate: true}, "bucket.s3-accelerate.amazonaws.com"},
+		"dual-stack": {Options{Region: "us-west-2", UseDualStack: true}, "bucket.s3.dualstack.us-west-2.amazonaws.com"},
+		"accelerate dual-stack": {
+			Options{Region: "us-west-2", UseAccelerate: true, UseDualStack: true},
+			"bucket.s3-accelerate.dualstack.amazonaws.com",
+		},
+	}
+	for name, tt := range tests {
+		o.Run(name, func() {
+			client, err := getClient(tt.opts)
+			o.Require().NoError(err)
+			req, _ := client.(*s3.S3).GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("key")})
+			o.Require().NoError(req.Build())
+			o.Equal(tt.expectedHost, req.HTTPRequest.URL.Host)
+		})
+	}
+}
+
+func (o *optionsTestSuite) TestRequestPayer() {
+	o.Nil(Options{}.requestPayer())
+	o.Equal("requester", *Options{RequestPayer: true}.requestPayer())
+}
+
 func TestOptions(t *testing.T) {
 	suite.Run(t, new(optionsTestSuite))
 }
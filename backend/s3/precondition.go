@@ -0,0 +1,125 @@
+package s3
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+const (
+	// errCodeConditionalRequestConflict is returned by S3 when a conditional write races another write of the same key.
+	errCodeConditionalRequestConflict = "ConditionalRequestConflict"
+
+	// the operations of an upload that S3 evaluates conditions on
+	opPutObject               = "PutObject"
+	opCompleteMultipartUpload = "CompleteMultipartUpload"
+)
+
+// ETag returns the ETag of the file's object, to pass to IfMatch.
+func (f *File) ETag() (string, error) {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(head.ETag), nil
+}
+
+// IfMatch returns a File for the same object whose writes and deletes only succeed if the object's ETag is still eTag.
+// Otherwise a *vfs.PreconditionFailedError is returned.
+func (f *File) IfMatch(eTag string) *File {
+	file := f.withConditions()
+	file.ifMatch = eTag
+	file.ifNotExist = false
+	return file
+}
+
+// IfNotExist returns a File for the same object whose writes only succeed if the object doesn't exist.  Otherwise a
+// *vfs.PreconditionFailedError is returned.
+func (f *File) IfNotExist() *File {
+	file := f.withConditions()
+	file.ifNotExist = true
+	file.ifMatch = ""
+	return file
+}
+
+// withConditions returns a copy of the file, without its pending reads and writes, keeping its conditions.
+func (f *File) withConditions() *File {
+	return &File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		versionID:  f.versionID,
+		ifMatch:    f.ifMatch,
+		ifNotExist: f.ifNotExist,
+	}
+}
+
+// conditional returns whether the file's writes are subject to conditions.
+func (f *File) conditional() bool {
+	return f.ifMatch != "" || f.ifNotExist
+}
+
+// conditionHeaders returns a request option setting the file's conditions as request headers, since the SDK's inputs
+// have no fields for them.
+func (f *File) conditionHeaders() request.Option {
+	headers := make(map[string]string)
+	if f.ifMatch != "" {
+		headers["If-Match"] = f.ifMatch
+	}
+	if f.ifNotExist {
+		headers["If-None-Match"] = "*"
+	}
+	return request.WithSetRequestHeaders(headers)
+}
+
+// setUploadConditions makes an upload subject to the file's conditions.  S3 only evaluates conditions on PutObject and
+// CompleteMultipartUpload, so the headers are only sent with those requests, not with each part.
+func (f *File) setUploadConditions(uploader *s3manager.Uploader) {
+	if !f.conditional() {
+		return
+	}
+	headers := f.conditionHeaders()
+	uploader.RequestOptions = append(uploader.RequestOptions, func(r *request.Request) {
+		switch r.Operation.Name {
+		case opPutObject, opCompleteMultipartUpload:
+			r.ApplyOptions(headers)
+		}
+	})
+}
+
+// deleteObject deletes the object of input, subject to the file's IfMatch condition.
+func (f *File) deleteObject(client s3iface.S3API, input *s3.DeleteObjectInput) error {
+	if f.ifMatch == "" {
+		_, err := client.DeleteObject(input)
+		return err
+	}
+	req, _ := client.DeleteObjectRequest(input)
+	req.ApplyOptions(f.conditionHeaders())
+	return f.preconditionError(req.Send())
+}
+
+// preconditionError returns a *vfs.PreconditionFailedError if err is S3 rejecting a request because of the file's
+// conditions, otherwise err.
+func (f *File) preconditionError(err error) error {
+	cause := err
+	// failed multipart uploads wrap the error of CompleteMultipartUpload without unwrapping it
+	var uploadErr s3manager.MultiUploadFailure
+	if errors.As(err, &uploadErr) && uploadErr.OrigErr() != nil {
+		cause = uploadErr.OrigErr()
+	}
+
+	var reqErr awserr.RequestFailure
+	if errors.As(cause, &reqErr) &&
+		(reqErr.StatusCode() == http.StatusPreconditionFailed || reqErr.Code() == errCodeConditionalRequestConflict) {
+		return &vfs.PreconditionFailedError{URI: f.URI(), Err: err}
+	}
+	return err
+}
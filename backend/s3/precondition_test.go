@@ -0,0 +1,191 @@
+package s3
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/mocks"
+)
+
+type preconditionTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	fs     *FileSystem
+	file   *File
+}
+
+func (ts *preconditionTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.client, options: Options{}}
+	file, err := ts.fs.NewFile("bucket", "/jobs/state.json")
+	ts.Require().NoError(err)
+	ts.file = file.(*File)
+}
+
+// newRequest returns a request of operation for a mocked client, failing with err when it's sent if err isn't nil.
+func newRequest(operation string, err error) *request.Request {
+	req := &request.Request{
+		Operation:   &request.Operation{Name: operation},
+		HTTPRequest: &http.Request{Header: make(http.Header), URL: &url.URL{}},
+		Retryer:     client.NoOpRetryer{},
+	}
+	if err != nil {
+		req.Handlers.Send.PushBack(func(r *request.Request) { r.Error = err })
+	}
+	return req
+}
+
+func (ts *preconditionTestSuite) TestETag() {
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ETag: aws.String(`"abc123"`)}, nil).Once()
+
+	eTag, err := ts.file.ETag()
+	ts.NoError(err)
+	ts.Equal(`"abc123"`, eTag)
+
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(nil, awserr.New("NotFound", "not found", nil)).Once()
+	_, err = ts.file.ETag()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+}
+
+func (ts *preconditionTestSuite) TestConditions() {
+	conditional := ts.file.IfMatch(`"abc123"`)
+	ts.Equal(`"abc123"`, conditional.ifMatch)
+	ts.False(ts.file.conditional(), "the original file should be unconditional")
+
+	conditional = conditional.IfNotExist()
+	ts.True(conditional.ifNotExist)
+	ts.Empty(conditional.ifMatch, "IfNotExist and IfMatch are exclusive")
+}
+
+func (ts *preconditionTestSuite) TestWriteIfMatch() {
+	req := newRequest(opPutObject, nil)
+	ts.client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).Return(req, &s3.PutObjectOutput{}).Once()
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	file := ts.file.IfMatch(`"abc123"`)
+	_, err := file.Write([]byte("v2"))
+	ts.Require().NoError(err)
+	ts.NoError(file.Close())
+	ts.Equal(`"abc123"`, req.HTTPRequest.Header.Get("If-Match"))
+	ts.Empty(req.HTTPRequest.Header.Get("If-None-Match"))
+}
+
+func (ts *preconditionTestSuite) TestWriteIfNotExist() {
+	failed := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "At least one of the pre-conditions you specified did not hold",
+		nil), http.StatusPreconditionFailed, "request-id")
+	req := newRequest(opPutObject, failed)
+	ts.client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).Return(req, &s3.PutObjectOutput{}).Once()
+
+	file := ts.file.IfNotExist()
+	_, err := file.Write([]byte("worker-2"))
+	ts.Require().NoError(err)
+	err = file.Close()
+	ts.Equal("*", req.HTTPRequest.Header.Get("If-None-Match"))
+	ts.ErrorIs(err, vfs.ErrPreconditionFailed)
+	var preconditionErr *vfs.PreconditionFailedError
+	ts.Require().ErrorAs(err, &preconditionErr)
+	ts.Equal("s3://bucket/jobs/state.json", preconditionErr.URI)
+
+	// a concurrent conditional write of the same key also fails the condition
+	conflict := awserr.NewRequestFailure(awserr.New(errCodeConditionalRequestConflict, "conflict", nil),
+		http.StatusConflict, "request-id")
+	ts.client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(newRequest(opPutObject, conflict), &s3.PutObjectOutput{}).Once()
+	_, err = file.Write([]byte("worker-2"))
+	ts.Require().NoError(err)
+	ts.ErrorIs(file.Close(), vfs.ErrPreconditionFailed)
+}
+
+func (ts *preconditionTestSuite) TestMultipartUploadConditions() {
+	uploader := s3manager.NewUploaderWithClient(ts.client)
+	ts.file.IfNotExist().setUploadConditions(uploader)
+	ts.Equal(int64(s3manager.DefaultUploadPartSize), uploader.PartSize, "conditional uploads can still have many parts")
+
+	// the condition is only sent with the requests S3 evaluates it on
+	for operation, expected := range map[string]string{
+		opPutObject:               "*",
+		"CreateMultipartUpload":   "",
+		"UploadPart":              "",
+		opCompleteMultipartUpload: "*",
+	} {
+		req := newRequest(operation, nil)
+		req.ApplyOptions(uploader.RequestOptions...)
+		ts.Equal(expected, req.HTTPRequest.Header.Get("If-None-Match"), operation)
+	}
+
+	uploader = s3manager.NewUploaderWithClient(ts.client)
+	ts.file.setUploadConditions(uploader)
+	ts.Empty(uploader.RequestOptions, "unconditional uploads are unchanged")
+
+	failed := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "failed", nil), http.StatusPreconditionFailed,
+		"request-id")
+	err := ts.file.preconditionError(multiUploadFailure{awserr.New("MultipartUpload", "upload multipart failed", failed)})
+	ts.ErrorIs(err, vfs.ErrPreconditionFailed)
+}
+
+// awsError names the embedded error of multiUploadFailure, so it doesn't hide the Error method.
+type awsError = awserr.Error
+
+// multiUploadFailure is a failed multipart upload, like the ones returned by s3manager.Uploader.
+type multiUploadFailure struct {
+	awsError
+}
+
+func (multiUploadFailure) UploadID() string {
+	return "upload-id"
+}
+
+func (ts *preconditionTestSuite) TestDeleteIfMatch() {
+	req := newRequest("DeleteObject", nil)
+	ts.client.On("DeleteObjectRequest", mock.AnythingOfType("*s3.DeleteObjectInput")).
+		Return(req, &s3.DeleteObjectOutput{}).Once()
+
+	ts.NoError(ts.file.IfMatch(`"abc123"`).Delete())
+	ts.Equal(`"abc123"`, req.HTTPRequest.Header.Get("If-Match"))
+
+	failed := awserr.NewRequestFailure(awserr.New("PreconditionFailed", "failed", nil), http.StatusPreconditionFailed,
+		"request-id")
+	ts.client.On("DeleteObjectRequest", mock.AnythingOfType("*s3.DeleteObjectInput")).
+		Return(newRequest("DeleteObject", failed), &s3.DeleteObjectOutput{}).Once()
+	ts.ErrorIs(ts.file.IfMatch(`"stale"`).Delete(), vfs.ErrPreconditionFailed)
+
+	// unconditional deletes are unchanged
+	ts.client.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	ts.NoError(ts.file.Delete())
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *preconditionTestSuite) TestCopyToConditionalTarget() {
+	// the target's conditions can't be applied to CopyObject, so the copy is streamed
+	ts.client.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(2)}, nil)
+	ts.client.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("v1"))}, nil).Once()
+	req := newRequest(opPutObject, nil)
+	ts.client.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).Return(req, &s3.PutObjectOutput{}).Once()
+
+	target, err := ts.fs.NewFile("bucket", "/jobs/copy.json")
+	ts.Require().NoError(err)
+	ts.NoError(ts.file.CopyToFile(target.(*File).IfNotExist()))
+	ts.Equal("*", req.HTTPRequest.Header.Get("If-None-Match"))
+	ts.client.AssertNotCalled(ts.T(), "CopyObject", mock.Anything)
+}
+
+func TestPreconditionTestSuite(t *testing.T) {
+	suite.Run(t, new(preconditionTestSuite))
+}
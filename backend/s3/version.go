@@ -0,0 +1,116 @@
+package s3
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// ErrVersionReadOnly is returned when attempting to write to a File that refers to a specific object version.
+var ErrVersionReadOnly = errors.New("s3 file refers to a specific object version and can't be written")
+
+// ObjectVersion describes a version of an s3 object in a bucket with versioning enabled.
+type ObjectVersion struct {
+	VersionID      string
+	Size           uint64
+	LastModified   time.Time
+	IsLatest       bool
+	IsDeleteMarker bool
+}
+
+// Versions returns all versions of the file's object, including delete markers, newest first.  Buckets that have never
+// had versioning enabled return a single version with the ID "null".
+func (f *File) Versions() ([]ObjectVersion, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	key := utils.RemoveLeadingSlash(f.key)
+	input := new(s3.ListObjectVersionsInput).SetBucket(f.bucket).SetPrefix(key)
+	input.RequestPayer = f.fileSystem.getOptions().requestPayer()
+
+	versions := make([]ObjectVersion, 0)
+	for {
+		output, err := client.ListObjectVersions(input)
+		if err != nil {
+			return nil, err
+		}
+
+		// the prefix also matches other objects whose keys start with this one's, ie "file.txt.bak"
+		for _, v := range output.Versions {
+			if aws.StringValue(v.Key) != key {
+				continue
+			}
+			versions = append(versions, ObjectVersion{
+				VersionID:    aws.StringValue(v.VersionId),
+				Size:         uint64(aws.Int64Value(v.Size)),
+				LastModified: aws.TimeValue(v.LastModified),
+				IsLatest:     aws.BoolValue(v.IsLatest),
+			})
+		}
+		for _, m := range output.DeleteMarkers {
+			if aws.StringValue(m.Key) != key {
+				continue
+			}
+			versions = append(versions, ObjectVersion{
+				VersionID:      aws.StringValue(m.VersionId),
+				LastModified:   aws.TimeValue(m.LastModified),
+				IsLatest:       aws.BoolValue(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		input.SetKeyMarker(aws.StringValue(output.NextKeyMarker)).
+			SetVersionIdMarker(aws.StringValue(output.NextVersionIdMarker))
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+
+	return versions, nil
+}
+
+// AtVersion returns a File referring to the given version of the file's object.  Reads, Size, LastModified, Exists,
+// Tags and copies of the returned File use that version.  Delete permanently deletes only that version.  The returned
+// File can't be written or touched; ErrVersionReadOnly is returned instead.
+func (f *File) AtVersion(versionID string) (*File, error) {
+	if versionID == "" {
+		return nil, errors.New("non-empty string for versionID is required")
+	}
+
+	return &File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		versionID:  versionID,
+	}, nil
+}
+
+// VersionID returns the version ID the file refers to, or an empty string if it refers to the current version.
+func (f *File) VersionID() string {
+	return f.versionID
+}
+
+// RestoreVersion makes the given version the current version of the file's object by copying it over the current one.
+// Previous versions, including the one being replaced, are kept.
+func (f *File) RestoreVersion(versionID string) error {
+	if f.versionID != "" {
+		return ErrVersionReadOnly
+	}
+
+	version, err := f.AtVersion(versionID)
+	if err != nil {
+		return err
+	}
+	return version.CopyToFile(f)
+}
@@ -0,0 +1,129 @@
+package s3
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/mocks"
+)
+
+type versionTestSuite struct {
+	suite.Suite
+	client *mocks.S3API
+	file   *File
+}
+
+func (ts *versionTestSuite) SetupTest() {
+	ts.client = &mocks.S3API{}
+	fs := &FileSystem{client: ts.client, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/some/path/file.txt")
+	ts.Require().NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *versionTestSuite) TestVersions() {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t3 := t2.Add(time.Hour)
+
+	ts.client.On("ListObjectVersions", &s3.ListObjectVersionsInput{
+		Bucket: aws.String("bucket"),
+		Prefix: aws.String("some/path/file.txt"),
+	}).Return(&s3.ListObjectVersionsOutput{
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v1"), Size: aws.Int64(5), LastModified: &t1},
+			{Key: aws.String("some/path/file.txt.bak"), VersionId: aws.String("other"), Size: aws.Int64(1), LastModified: &t2},
+		},
+		IsTruncated:         aws.Bool(true),
+		NextKeyMarker:       aws.String("some/path/file.txt"),
+		NextVersionIdMarker: aws.String("v1"),
+	}, nil).Once()
+	ts.client.On("ListObjectVersions", &s3.ListObjectVersionsInput{
+		Bucket:          aws.String("bucket"),
+		Prefix:          aws.String("some/path/file.txt"),
+		KeyMarker:       aws.String("some/path/file.txt"),
+		VersionIdMarker: aws.String("v1"),
+	}).Return(&s3.ListObjectVersionsOutput{
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v2"), Size: aws.Int64(7), LastModified: &t2},
+		},
+		DeleteMarkers: []*s3.DeleteMarkerEntry{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v3"), LastModified: &t3, IsLatest: aws.Bool(true)},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	versions, err := ts.file.Versions()
+	ts.NoError(err)
+	ts.Equal([]ObjectVersion{
+		{VersionID: "v3", LastModified: t3, IsLatest: true, IsDeleteMarker: true},
+		{VersionID: "v2", Size: 7, LastModified: t2},
+		{VersionID: "v1", Size: 5, LastModified: t1},
+	}, versions)
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestAtVersion() {
+	_, err := ts.file.AtVersion("")
+	ts.Error(err)
+
+	version, err := ts.file.AtVersion("v1")
+	ts.Require().NoError(err)
+	ts.Equal("v1", version.VersionID())
+	ts.Empty(ts.file.VersionID())
+	ts.Equal(ts.file.URI(), version.URI())
+
+	ts.client.On("HeadObject", mock.MatchedBy(func(in *s3.HeadObjectInput) bool {
+		return aws.StringValue(in.VersionId) == "v1"
+	})).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(3)}, nil)
+	ts.client.On("GetObject", mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+		return aws.StringValue(in.VersionId) == "v1"
+	})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("old"))}, nil).Once()
+
+	data, err := io.ReadAll(version)
+	ts.NoError(err)
+	ts.Equal("old", string(data))
+	ts.NoError(version.Close())
+
+	// versions are read-only
+	_, err = version.Write([]byte("new"))
+	ts.ErrorIs(err, ErrVersionReadOnly)
+	ts.ErrorIs(version.Touch(), ErrVersionReadOnly)
+	ts.ErrorIs(ts.file.CopyToFile(version), ErrVersionReadOnly)
+
+	// deleting a version deletes only that version
+	ts.client.On("DeleteObject", &s3.DeleteObjectInput{
+		Bucket:    aws.String("bucket"),
+		Key:       aws.String("/some/path/file.txt"),
+		VersionId: aws.String("v1"),
+	}).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	ts.NoError(version.Delete())
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestRestoreVersion() {
+	ts.client.On("CopyObject", mock.MatchedBy(func(in *s3.CopyObjectInput) bool {
+		return aws.StringValue(in.CopySource) == "bucket%2Fsome%2Fpath%2Ffile.txt?versionId=v%2B1" &&
+			aws.StringValue(in.Key) == "/some/path/file.txt"
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+
+	ts.NoError(ts.file.RestoreVersion("v+1"))
+
+	version, err := ts.file.AtVersion("v1")
+	ts.Require().NoError(err)
+	ts.ErrorIs(version.RestoreVersion("v2"), ErrVersionReadOnly)
+
+	ts.client.AssertExpectations(ts.T())
+}
+
+func TestVersion(t *testing.T) {
+	suite.Run(t, new(versionTestSuite))
+}
@@ -0,0 +1,80 @@
+package tar
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression format of a tar archive.
+type Compression string
+
+const (
+	// CompressionNone is an uncompressed tar archive.
+	CompressionNone Compression = "none"
+	// CompressionGzip is a gzip-compressed tar archive, ie .tar.gz or .tgz
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd is a zstd-compressed tar archive, ie .tar.zst
+	CompressionZstd Compression = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// detectCompression determines the compression of a stream by peeking at its magic bytes.
+func detectCompression(r *bufio.Reader) Compression {
+	magic, _ := r.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(magic, zstdMagic):
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// newDecompressor returns a reader of the decompressed contents of r.
+func newDecompressor(r io.Reader, compression Compression) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported tar compression %q", compression)
+	}
+}
+
+// newCompressor returns a writer compressing its input to w.  Closing it flushes any compressed data but doesn't
+// close w.
+func newCompressor(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported tar compression %q", compression)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
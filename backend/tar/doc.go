@@ -0,0 +1,69 @@
+/*
+Package tar provides a vfs.FileSystem for browsing and reading tar archives stored on any vfs backend, and for writing
+new ones.
+
+Archives may be uncompressed, gzip-compressed (.tar.gz, .tgz) or zstd-compressed (.tar.zst).  When reading, the
+compression is detected from the archive's contents.
+
+# Reading
+
+NewFileSystem scans the archive once to build an index of its regular file entries, which are then exposed as
+read-only vfs.Files within vfs.Locations.  Reading an entry opens a new handle on the archive file so that several
+entries may be read at once.  For uncompressed archives the handle seeks directly to the entry's data; for compressed
+archives the archive is streamed from the start up to the entry.
+
+	import(
+	    "github.com/c2fo/vfs/v6/backend/tar"
+	    "github.com/c2fo/vfs/v6/vfssimple"
+	)
+
+	func DoSomething() error {
+	    archive, err := vfssimple.NewFile("sftp://user@host/outbound/partner.tar.gz")
+	    if err != nil {
+	        return err
+	    }
+
+	    fs, err := tar.NewFileSystem(archive)
+	    if err != nil {
+	        return err
+	    }
+
+	    loc, err := fs.NewLocation("", "/data/")
+	    if err != nil {
+	        return err
+	    }
+
+	    csvs, err := loc.ListByRegex(regexp.MustCompile(`\.csv$`))
+	    ...
+	}
+
+Entries of an archive opened for reading can't be written or touched; ErrReadOnly is returned.
+
+# Writing
+
+NewWriteFileSystem appends entries sequentially to a new archive in the given vfs.File, compressed as requested.  Since
+tar headers must contain the entry's size, which isn't known until a file is closed, each file is buffered in memory
+until it is closed and then appended to the archive.  Writing an entry therefore needs memory for its whole contents;
+entries too large for that should be written to the archive with archive/tar directly.  The archive is finalized and
+the target file closed when the FileSystem is closed.
+
+	func WriteBundle(target vfs.File) error {
+	    fs, err := tar.NewWriteFileSystem(target, tar.CompressionGzip)
+	    if err != nil {
+	        return err
+	    }
+
+	    f, err := fs.NewFile("", "/data/output.csv")
+	    ...
+	    _ = f.Close()
+
+	    // writes the tar footer, flushes compression and closes target
+	    return fs.Close()
+	}
+
+Entries of an archive opened for writing can't be read and each entry may only be written once.
+
+Entries can never be deleted from an archive, so Delete, DeleteFile, MoveToFile and MoveToLocation always return
+ErrDeleteNotSupported.  A tar FileSystem is not registered with the backend package since it requires an archive file.
+*/
+package tar
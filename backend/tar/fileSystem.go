@@ -0,0 +1,317 @@
+package tar
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/internal/archiveutil"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Scheme defines the file system type.
+const Scheme = "tar"
+const name = "tar archive"
+
+var (
+	// ErrReadOnly is returned when attempting to modify an archive opened for reading.
+	ErrReadOnly = errors.New("tar archive is opened for reading; entries cannot be written")
+	// ErrWriteOnly is returned when attempting to read from an archive opened for writing.
+	ErrWriteOnly = errors.New("tar archive is opened for writing; entries cannot be read")
+	// ErrDeleteNotSupported is returned when attempting to delete (or move) an archive entry.
+	ErrDeleteNotSupported = errors.New("entries cannot be deleted from a tar archive")
+)
+
+// entry describes a single regular file within the archive.
+type entry struct {
+	index    int   // position of the entry's header in the archive
+	offset   int64 // offset of the entry's data in an uncompressed archive, otherwise -1
+	size     uint64
+	modified time.Time
+}
+
+// FileSystem implements vfs.FileSystem for the entries of a tar archive stored in a vfs.File.
+type FileSystem struct {
+	archive     vfs.File
+	compression Compression
+	mu          sync.Mutex // guards writing entries and closing the archive
+	entries     *archiveutil.Index[*entry]
+	writer      *tar.Writer
+	compressor  io.WriteCloser
+	closed      bool
+}
+
+// NewFileSystem scans the tar archive stored in the given file, detecting its compression, and opens it for reading.
+func NewFileSystem(archive vfs.File) (*FileSystem, error) {
+	if archive == nil {
+		return nil, errors.New("non-nil archive vfs.File is required")
+	}
+
+	fs := &FileSystem{
+		archive: archive,
+		entries: archiveutil.NewIndex[*entry](),
+	}
+	if err := fs.scan(); err != nil {
+		return nil, fmt.Errorf("unable to read tar archive %s: %w", archive, err)
+	}
+	return fs, nil
+}
+
+// NewWriteFileSystem creates a FileSystem that appends entries to a new tar archive in the given file, compressed as
+// requested.  Files are buffered in memory and added to the archive as they are closed, and the archive is finalized by
+// FileSystem.Close.
+func NewWriteFileSystem(archive vfs.File, compression Compression) (*FileSystem, error) {
+	if archive == nil {
+		return nil, errors.New("non-nil archive vfs.File is required")
+	}
+
+	compressor, err := newCompressor(archive, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSystem{
+		archive:     archive,
+		compression: compression,
+		entries:     archiveutil.NewIndex[*entry](),
+		writer:      tar.NewWriter(compressor),
+		compressor:  compressor,
+	}, nil
+}
+
+// Retry will return the default no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile returns a tar File for the entry at the given absolute path within the archive.  Volume is ignored.
+func (fs *FileSystem) NewFile(volume, absFilePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil tar.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(absFilePath); err != nil {
+		return nil, err
+	}
+	return &File{
+		fileSystem: fs,
+		name:       path.Clean(absFilePath),
+	}, nil
+}
+
+// NewLocation returns a tar Location for the directory at the given absolute path within the archive.  Volume is
+// ignored.
+func (fs *FileSystem) NewLocation(volume, absLocPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil tar.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(absLocPath); err != nil {
+		return nil, err
+	}
+	return &Location{
+		fileSystem: fs,
+		name:       utils.EnsureTrailingSlash(path.Clean(absLocPath)),
+	}, nil
+}
+
+// Name returns "tar archive"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "tar" as the initial part of a file URI ie: tar://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// Archive returns the vfs.File containing the tar archive.
+func (fs *FileSystem) Archive() vfs.File {
+	return fs.archive
+}
+
+// Compression returns the compression of the archive.
+func (fs *FileSystem) Compression() Compression {
+	return fs.compression
+}
+
+// Close finalizes the archive when opened for writing, then closes the archive file.
+func (fs *FileSystem) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.closed {
+		return nil
+	}
+	fs.closed = true
+
+	if fs.writer != nil {
+		if err := fs.writer.Close(); err != nil {
+			return err
+		}
+		if err := fs.compressor.Close(); err != nil {
+			return err
+		}
+	}
+	return fs.archive.Close()
+}
+
+// scan reads every header of the archive, indexing its regular files.
+func (fs *FileSystem) scan() (err error) {
+	// the archive is closed whether or not it can be read
+	defer func() {
+		if cerr := fs.archive.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	br := bufio.NewReader(fs.archive)
+	fs.compression = detectCompression(br)
+
+	dr, err := newDecompressor(br, fs.compression)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dr.Close() }()
+
+	cr := &countingReader{r: dr}
+	tr := tar.NewReader(cr)
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		offset := int64(-1)
+		if fs.compression == CompressionNone {
+			offset = cr.n
+		}
+		// later entries of the same name replace earlier ones, as when extracting
+		fs.entries.Set(path.Clean("/"+hdr.Name), &entry{
+			index:    i,
+			offset:   offset,
+			size:     uint64(hdr.Size),
+			modified: hdr.ModTime,
+		})
+	}
+	return nil
+}
+
+// openEntry returns a reader of the entry's contents starting at offset, using a new handle on the archive file.
+func (fs *FileSystem) openEntry(e *entry, offset int64) (io.ReadCloser, error) {
+	archive, err := fs.archive.Location().NewFile(fs.archive.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// uncompressed archives can seek straight to the entry's data
+	if e.offset >= 0 {
+		if _, err := archive.Seek(e.offset+offset, io.SeekStart); err != nil {
+			_ = archive.Close()
+			return nil, err
+		}
+		return &entryReader{
+			Reader:  io.LimitReader(archive, int64(e.size)-offset),
+			closers: []io.Closer{archive},
+		}, nil
+	}
+
+	dr, err := newDecompressor(archive, fs.compression)
+	if err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+	er := &entryReader{closers: []io.Closer{dr, archive}}
+
+	tr := tar.NewReader(dr)
+	for i := 0; i <= e.index; i++ {
+		if _, err := tr.Next(); err != nil {
+			_ = er.Close()
+			return nil, err
+		}
+	}
+	if _, err := io.CopyN(io.Discard, tr, offset); err != nil && !errors.Is(err, io.EOF) {
+		_ = er.Close()
+		return nil, err
+	}
+	er.Reader = tr
+	return er, nil
+}
+
+// addEntry appends the contents of data to the archive as a new entry.
+func (fs *FileSystem) addEntry(absFilePath string, data []byte) error {
+	if fs.writer == nil {
+		return ErrReadOnly
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.closed {
+		return errors.New("tar archive is closed")
+	}
+	if _, ok := fs.entries.Get(absFilePath); ok {
+		return fmt.Errorf("tar entry %s has already been written", absFilePath)
+	}
+
+	modified := time.Now()
+	err := fs.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     utils.RemoveLeadingSlash(absFilePath),
+		Size:     int64(len(data)),
+		Mode:     0644,
+		ModTime:  modified,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fs.writer.Write(data); err != nil {
+		return err
+	}
+
+	fs.entries.Set(absFilePath, &entry{
+		index:    fs.entries.Len(),
+		offset:   -1,
+		size:     uint64(len(data)),
+		modified: modified,
+	})
+	return nil
+}
+
+// countingReader counts the bytes read from r, used to determine entry offsets in uncompressed archives.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// entryReader reads an entry's contents, closing the decompressor and archive handle behind it on Close.
+type entryReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close implements io.Closer
+func (r *entryReader) Close() error {
+	var firstErr error
+	for _, c := range r.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
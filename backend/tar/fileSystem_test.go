@@ -0,0 +1,205 @@
+package tar
+
+import (
+	"archive/tar"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+}
+
+// writeArchive writes a tar archive containing the given entries (name -> contents) to file.  Names ending in a
+// slash are written as directories.
+func writeArchive(file vfs.File, compression Compression, entries map[string]string) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw, err := newCompressor(file, compression)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(cw)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(entries[name])), ModTime: time.Now()}
+		if name[len(name)-1] == '/' {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw, entries[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := cw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+var testEntries = map[string]string{
+	"readme.txt":              "read me",
+	"reports/":                "",
+	"reports/daily.csv":       "a,b,c\n1,2,3\n",
+	"reports/weekly.csv":      "x,y,z\n",
+	"reports/2024/annual.txt": "annual",
+}
+
+func (ts *fileSystemTestSuite) TestNewFileSystem() {
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		archive, err := mem.NewFileSystem().NewFile("", "/bundle.tar")
+		ts.Require().NoError(err)
+		ts.Require().NoError(writeArchive(archive, compression, testEntries))
+
+		fs, err := NewFileSystem(archive)
+		ts.Require().NoError(err, compression)
+		ts.Equal(compression, fs.Compression())
+		ts.Equal(4, fs.entries.Len(), "directory entries are skipped")
+		ts.Equal(Scheme, fs.Scheme())
+		ts.Equal("tar archive", fs.Name())
+		ts.Equal(archive, fs.Archive())
+
+		for name := range testEntries {
+			e, ok := fs.entries.Get(utils.EnsureLeadingSlash(name))
+			if !ok {
+				continue
+			}
+			if compression == CompressionNone {
+				ts.GreaterOrEqual(e.offset, int64(512), name)
+			} else {
+				ts.Equal(int64(-1), e.offset, name)
+			}
+		}
+
+		file, err := fs.NewFile("", "/reports/daily.csv")
+		ts.Require().NoError(err)
+		data, err := io.ReadAll(file)
+		ts.NoError(err)
+		ts.Equal(testEntries["reports/daily.csv"], string(data), compression)
+		ts.NoError(file.Close())
+	}
+}
+
+func (ts *fileSystemTestSuite) TestNewFileSystem_Error() {
+	_, err := NewFileSystem(nil)
+	ts.EqualError(err, "non-nil archive vfs.File is required")
+
+	notTar, err := mem.NewFileSystem().NewFile("", "/not.tar.gz")
+	ts.Require().NoError(err)
+	_, err = notTar.Write([]byte{0x1f, 0x8b, 0x00, 0x01})
+	ts.NoError(err)
+	ts.NoError(notTar.Close())
+	_, err = NewFileSystem(notTar)
+	ts.Error(err)
+
+	// the archive is closed when it can't be scanned
+	closed := &closeCountingFile{File: notTar}
+	_, err = NewFileSystem(closed)
+	ts.Error(err)
+	ts.Equal(1, closed.closes)
+
+	notHeader, err := mem.NewFileSystem().NewFile("", "/not.tar")
+	ts.Require().NoError(err)
+	_, err = notHeader.Write([]byte("not a tar header"))
+	ts.NoError(err)
+	ts.NoError(notHeader.Close())
+	closed = &closeCountingFile{File: notHeader}
+	_, err = NewFileSystem(closed)
+	ts.Error(err)
+	ts.Equal(1, closed.closes)
+}
+
+// closeCountingFile counts the times a vfs.File is closed.
+type closeCountingFile struct {
+	vfs.File
+	closes int
+}
+
+func (f *closeCountingFile) Close() error {
+	f.closes++
+	return f.File.Close()
+}
+
+func (ts *fileSystemTestSuite) TestNewFileAndLocation() {
+	archive, err := mem.NewFileSystem().NewFile("", "/bundle.tar")
+	ts.Require().NoError(err)
+	ts.Require().NoError(writeArchive(archive, CompressionNone, testEntries))
+	fs, err := NewFileSystem(archive)
+	ts.Require().NoError(err)
+
+	file, err := fs.NewFile("", "/reports/./daily.csv")
+	ts.NoError(err)
+	ts.Equal("/reports/daily.csv", file.Path())
+	ts.Equal("tar:///reports/daily.csv", file.URI())
+	_, err = fs.NewFile("", "reports/daily.csv")
+	ts.EqualError(err, utils.ErrBadAbsFilePath)
+
+	loc, err := fs.NewLocation("", "/reports/")
+	ts.NoError(err)
+	ts.Equal("tar:///reports/", loc.URI())
+	_, err = fs.NewLocation("", "/reports")
+	ts.EqualError(err, utils.ErrBadAbsLocationPath)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewFile("", "/file.txt")
+	ts.EqualError(err, "non-nil tar.FileSystem pointer is required")
+	_, err = nilFs.NewLocation("", "/")
+	ts.EqualError(err, "non-nil tar.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestWriteFileSystem() {
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		target, err := mem.NewFileSystem().NewFile("", "/out/new.tar")
+		ts.Require().NoError(err)
+
+		wfs, err := NewWriteFileSystem(target, compression)
+		ts.Require().NoError(err)
+		for name, contents := range map[string]string{"/a.txt": "aaa", "/dir/b.txt": "bbb"} {
+			f, err := wfs.NewFile("", name)
+			ts.Require().NoError(err)
+			_, err = f.Write([]byte(contents))
+			ts.NoError(err)
+			ts.NoError(f.Close())
+		}
+		ts.NoError(wfs.Close())
+		ts.NoError(wfs.Close(), "closing twice is a no-op")
+
+		// read back the archive
+		rfs, err := NewFileSystem(target)
+		ts.Require().NoError(err)
+		ts.Equal(compression, rfs.Compression())
+		f, err := rfs.NewFile("", "/dir/b.txt")
+		ts.Require().NoError(err)
+		data, err := io.ReadAll(f)
+		ts.NoError(err)
+		ts.Equal("bbb", string(data), compression)
+	}
+
+	_, err := NewWriteFileSystem(nil, CompressionNone)
+	ts.Error(err)
+	target, err := mem.NewFileSystem().NewFile("", "/out/new.tar.bz2")
+	ts.Require().NoError(err)
+	_, err = NewWriteFileSystem(target, Compression("bzip2"))
+	ts.EqualError(err, `unsupported tar compression "bzip2"`)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
@@ -0,0 +1,196 @@
+package tar
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type fileTestSuite struct {
+	suite.Suite
+	memFs *mem.FileSystem
+}
+
+func (ts *fileTestSuite) SetupTest() {
+	ts.memFs = mem.NewFileSystem()
+}
+
+func (ts *fileTestSuite) newFileSystem(compression Compression) *FileSystem {
+	archive, err := ts.memFs.NewFile("", "/bundle-"+string(compression)+".tar")
+	ts.Require().NoError(err)
+	ts.Require().NoError(writeArchive(archive, compression, map[string]string{
+		"a.txt":             "first entry",
+		"reports/daily.csv": "a,b,c\n1,2,3\n",
+		"big.txt":           strings.Repeat("0123456789", 10000),
+	}))
+	fs, err := NewFileSystem(archive)
+	ts.Require().NoError(err)
+	return fs
+}
+
+func (ts *fileTestSuite) TestRead() {
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		fs := ts.newFileSystem(compression)
+		file, err := fs.NewFile("", "/reports/daily.csv")
+		ts.Require().NoError(err)
+
+		data, err := io.ReadAll(file)
+		ts.NoError(err)
+		ts.Equal("a,b,c\n1,2,3\n", string(data), compression)
+		ts.NoError(file.Close())
+
+		size, err := file.Size()
+		ts.NoError(err)
+		ts.Equal(uint64(12), size)
+
+		modified, err := file.LastModified()
+		ts.NoError(err)
+		ts.NotNil(modified)
+
+		// several entries may be read at once
+		a, err := fs.NewFile("", "/a.txt")
+		ts.Require().NoError(err)
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(a, buf)
+		ts.NoError(err)
+		_, err = io.ReadFull(file, buf)
+		ts.NoError(err)
+		ts.Equal("a,b,c", string(buf))
+		rest, err := io.ReadAll(a)
+		ts.NoError(err)
+		ts.Equal(" entry", string(rest))
+		ts.NoError(a.Close())
+		ts.NoError(file.Close())
+	}
+}
+
+func (ts *fileTestSuite) TestRead_NotExist() {
+	fs := ts.newFileSystem(CompressionNone)
+	missing, err := fs.NewFile("", "/missing.txt")
+	ts.Require().NoError(err)
+
+	_, err = missing.Read(make([]byte, 1))
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = missing.Size()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = missing.LastModified()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	exists, err := missing.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileTestSuite) TestSeek() {
+	for _, compression := range []Compression{CompressionNone, CompressionZstd} {
+		fs := ts.newFileSystem(compression)
+		file, err := fs.NewFile("", "/big.txt")
+		ts.Require().NoError(err)
+
+		pos, err := file.Seek(-5, io.SeekEnd)
+		ts.NoError(err)
+		ts.Equal(int64(99995), pos)
+		data, err := io.ReadAll(file)
+		ts.NoError(err)
+		ts.Equal("56789", string(data), compression)
+
+		pos, err = file.Seek(12, io.SeekStart)
+		ts.NoError(err)
+		ts.Equal(int64(12), pos)
+		buf := make([]byte, 3)
+		_, err = io.ReadFull(file, buf)
+		ts.NoError(err)
+		ts.Equal("234", string(buf), compression)
+
+		pos, err = file.Seek(1, io.SeekCurrent)
+		ts.NoError(err)
+		ts.Equal(int64(16), pos)
+		_, err = io.ReadFull(file, buf)
+		ts.NoError(err)
+		ts.Equal("678", string(buf), compression)
+
+		_, err = file.Seek(-1, io.SeekStart)
+		ts.ErrorIs(err, vfs.ErrSeekInvalidOffset)
+		_, err = file.Seek(0, 3)
+		ts.ErrorIs(err, vfs.ErrSeekInvalidWhence)
+		ts.NoError(file.Close())
+	}
+}
+
+func (ts *fileTestSuite) TestReadOnly() {
+	fs := ts.newFileSystem(CompressionGzip)
+	file, err := fs.NewFile("", "/reports/daily.csv")
+	ts.Require().NoError(err)
+
+	_, err = file.Write([]byte("data"))
+	ts.ErrorIs(err, ErrReadOnly)
+	ts.ErrorIs(file.Touch(), ErrReadOnly)
+	ts.ErrorIs(file.Delete(), ErrDeleteNotSupported)
+
+	target, err := ts.memFs.NewFile("", "/target.csv")
+	ts.Require().NoError(err)
+	ts.ErrorIs(file.MoveToFile(target), ErrDeleteNotSupported)
+	_, err = file.MoveToLocation(target.Location())
+	ts.ErrorIs(err, ErrDeleteNotSupported)
+}
+
+func (ts *fileTestSuite) TestCopyToLocation() {
+	fs := ts.newFileSystem(CompressionGzip)
+	file, err := fs.NewFile("", "/reports/daily.csv")
+	ts.Require().NoError(err)
+
+	loc, err := ts.memFs.NewLocation("", "/extracted/")
+	ts.Require().NoError(err)
+	copied, err := file.CopyToLocation(loc)
+	ts.Require().NoError(err)
+	ts.Equal("mem:///extracted/daily.csv", copied.URI())
+
+	data, err := io.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("a,b,c\n1,2,3\n", string(data))
+}
+
+func (ts *fileTestSuite) TestWriteMode() {
+	target, err := ts.memFs.NewFile("", "/out.tar.zst")
+	ts.Require().NoError(err)
+	wfs, err := NewWriteFileSystem(target, CompressionZstd)
+	ts.Require().NoError(err)
+
+	file, err := wfs.NewFile("", "/dir/new.txt")
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte("new contents"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(12), size)
+
+	_, err = file.Read(make([]byte, 1))
+	ts.ErrorIs(err, ErrWriteOnly)
+
+	_, err = file.Write([]byte("again"))
+	ts.NoError(err)
+	ts.EqualError(file.Close(), "tar entry /dir/new.txt has already been written")
+
+	empty, err := wfs.NewFile("", "/empty.txt")
+	ts.Require().NoError(err)
+	ts.NoError(empty.Touch())
+
+	ts.NoError(wfs.Close())
+
+	late, err := wfs.NewFile("", "/late.txt")
+	ts.Require().NoError(err)
+	ts.EqualError(late.Touch(), "tar archive is closed")
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,128 @@
+package tar
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Location implements the vfs.Location interface for a directory within a tar archive.
+type Location struct {
+	fileSystem *FileSystem
+	name       string
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
+
+// List returns the base names of all entries directly within the location.
+func (l *Location) List() ([]string, error) {
+	return l.fileSystem.entries.List(l.Path(), func(string) bool { return true }), nil
+}
+
+// ListByPrefix returns the base names of all entries directly within the location whose names start with prefix.
+// Relative prefixes such as "some/dir/prefix" are allowed.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidatePrefix(prefix); err != nil {
+		return []string{}, err
+	}
+
+	dir := l.Path()
+	// if prefix has a dir component, use it's location and basename of prefix
+	if d := path.Dir(prefix); d != "." {
+		dir = utils.EnsureTrailingSlash(path.Join(dir, d))
+		prefix = path.Base(prefix)
+	}
+
+	return l.fileSystem.entries.List(dir, func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	}), nil
+}
+
+// ListByRegex returns the base names of all entries directly within the location that match regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.fileSystem.entries.List(l.Path(), regex.MatchString), nil
+}
+
+// Volume returns "" since tar archives have no volume.
+func (l *Location) Volume() string {
+	return ""
+}
+
+// Path returns the absolute path of the location within the archive, ie /some/path/to/
+func (l *Location) Path() string {
+	return utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(l.name))
+}
+
+// Exists returns true if any entry exists within the location.  The root location of an archive always exists.
+func (l *Location) Exists() (bool, error) {
+	return l.Path() == "/" || l.fileSystem.entries.HasPrefix(l.Path()), nil
+}
+
+// NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
+// relativePath argument, returning the resulting location.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	if l == nil {
+		return nil, errors.New("non-nil tar.Location pointer is required")
+	}
+
+	// make a copy of the original location first, then ChangeDir, leaving the original location as-is
+	newLocation := &Location{}
+	*newLocation = *l
+	err := newLocation.ChangeDir(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir takes a relative path, and modifies the underlying Location's path.
+func (l *Location) ChangeDir(relativePath string) error {
+	if l == nil {
+		return errors.New("non-nil tar.Location pointer is required")
+	}
+	if relativePath == "" {
+		return errors.New("non-empty string relativePath is required")
+	}
+	if err := utils.ValidateRelativeLocationPath(relativePath); err != nil {
+		return err
+	}
+	l.name = utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(path.Join(l.name, relativePath)))
+	return nil
+}
+
+// FileSystem returns the tar FileSystem of the location.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns a tar File for the entry at the given path relative to the location.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	if l == nil {
+		return nil, errors.New("non-nil tar.Location pointer is required")
+	}
+	if relFilePath == "" {
+		return nil, errors.New("non-empty string filePath is required")
+	}
+	if err := utils.ValidateRelativeFilePath(relFilePath); err != nil {
+		return nil, err
+	}
+	return l.fileSystem.NewFile("", utils.EnsureLeadingSlash(path.Join(l.name, relFilePath)))
+}
+
+// DeleteFile always returns ErrDeleteNotSupported since entries cannot be removed from a tar archive.
+func (l *Location) DeleteFile(_ string, _ ...options.DeleteOption) error {
+	return ErrDeleteNotSupported
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
@@ -0,0 +1,102 @@
+package tar
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type locationTestSuite struct {
+	suite.Suite
+	fs *FileSystem
+}
+
+func (ts *locationTestSuite) SetupTest() {
+	archive, err := mem.NewFileSystem().NewFile("", "/bundle.tar.gz")
+	ts.Require().NoError(err)
+	ts.Require().NoError(writeArchive(archive, CompressionGzip, map[string]string{
+		"readme.txt":              "read me",
+		"reports/daily.csv":       "a,b,c\n",
+		"reports/daily.txt":       "abc",
+		"reports/weekly.csv":      "x,y,z\n",
+		"reports/2024/annual.txt": "annual",
+	}))
+	ts.fs, err = NewFileSystem(archive)
+	ts.Require().NoError(err)
+}
+
+func (ts *locationTestSuite) TestList() {
+	loc, err := ts.fs.NewLocation("", "/reports/")
+	ts.Require().NoError(err)
+
+	list, err := loc.List()
+	ts.NoError(err)
+	ts.Equal([]string{"daily.csv", "daily.txt", "weekly.csv"}, list)
+
+	list, err = loc.ListByPrefix("daily")
+	ts.NoError(err)
+	ts.Equal([]string{"daily.csv", "daily.txt"}, list)
+
+	list, err = loc.ListByPrefix("2024/ann")
+	ts.NoError(err)
+	ts.Equal([]string{"annual.txt"}, list)
+
+	_, err = loc.ListByPrefix("")
+	ts.Error(err)
+
+	list, err = loc.ListByRegex(regexp.MustCompile(`\.csv$`))
+	ts.NoError(err)
+	ts.Equal([]string{"daily.csv", "weekly.csv"}, list)
+
+	missing, err := loc.NewLocation("missing/")
+	ts.Require().NoError(err)
+	list, err = missing.List()
+	ts.NoError(err)
+	ts.Equal([]string{}, list)
+}
+
+func (ts *locationTestSuite) TestExists() {
+	for path, expected := range map[string]bool{
+		"/":              true,
+		"/reports/":      true,
+		"/reports/2024/": true,
+		"/report/":       false,
+	} {
+		loc, err := ts.fs.NewLocation("", path)
+		ts.Require().NoError(err)
+		exists, err := loc.Exists()
+		ts.NoError(err)
+		ts.Equal(expected, exists, path)
+	}
+}
+
+func (ts *locationTestSuite) TestNewLocationAndNewFile() {
+	loc, err := ts.fs.NewLocation("", "/reports/")
+	ts.Require().NoError(err)
+
+	newLoc, err := loc.NewLocation("2024/")
+	ts.NoError(err)
+	ts.Equal("/reports/2024/", newLoc.Path())
+	ts.Equal("/reports/", loc.Path())
+	ts.Equal("", newLoc.Volume())
+	ts.Equal(ts.fs, newLoc.FileSystem())
+
+	file, err := newLoc.NewFile("../daily.csv")
+	ts.NoError(err)
+	ts.Equal("/reports/daily.csv", file.Path())
+
+	ts.NoError(newLoc.ChangeDir("../../"))
+	ts.Equal("/", newLoc.Path())
+	ts.Error(newLoc.ChangeDir("/abs/"))
+
+	_, err = loc.NewFile("")
+	ts.Error(err)
+	ts.ErrorIs(loc.DeleteFile("daily.csv"), ErrDeleteNotSupported)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
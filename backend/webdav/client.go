@@ -0,0 +1,175 @@
+package webdav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getcontentlength/><D:getlastmodified/></D:prop></D:propfind>`
+
+// resource holds the properties of a file or collection returned by PROPFIND.
+type resource struct {
+	path         string
+	isCollection bool
+	size         uint64
+	modified     time.Time
+}
+
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ContentLength string `xml:"DAV: getcontentlength"`
+				LastModified  string `xml:"DAV: getlastmodified"`
+			} `xml:"DAV: prop"`
+			Status string `xml:"DAV: status"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// url returns the http(s) URL of a path on the server identified by authority.
+func (fs *FileSystem) url(authority utils.Authority, p string) *url.URL {
+	return fs.newClient().URL(authority, p)
+}
+
+// do sends a WebDAV request for the path p, adding authentication and any headers.
+func (fs *FileSystem) do(method string, authority utils.Authority, p string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	return fs.newClient().Do(method, authority, fs.url(authority, p), body, headers)
+}
+
+// doAndClose sends a WebDAV request, discarding and closing the response body, and returns an error unless the
+// response status is one of expected.
+func (fs *FileSystem) doAndClose(method string, authority utils.Authority, p string, body io.Reader, headers map[string]string, expected ...int) (int, error) {
+	return fs.newClient().DoAndClose(method, authority, fs.url(authority, p), body, headers, expected...)
+}
+
+// propfind returns the properties of the resource at p and, for depth "1", of its immediate children.
+func (fs *FileSystem) propfind(authority utils.Authority, p, depth string) ([]resource, error) {
+	resp, err := fs.do("PROPFIND", authority, p, strings.NewReader(propfindBody), map[string]string{
+		"Depth":        depth,
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := fs.newClient().CheckStatus(resp, "PROPFIND", p, http.StatusMultiStatus); err != nil {
+		return nil, err
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("webdav PROPFIND %s: unable to parse response: %w", p, err)
+	}
+
+	resources := make([]resource, 0, len(ms.Responses))
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			return nil, err
+		}
+		res := resource{path: href.Path}
+		for _, ps := range r.Propstats {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			if ps.Prop.ResourceType.Collection != nil {
+				res.isCollection = true
+			}
+			if ps.Prop.ContentLength != "" {
+				if size, err := strconv.ParseUint(ps.Prop.ContentLength, 10, 64); err == nil {
+					res.size = size
+				}
+			}
+			if ps.Prop.LastModified != "" {
+				if t, err := http.ParseTime(ps.Prop.LastModified); err == nil {
+					res.modified = t
+				}
+			}
+		}
+		resources = append(resources, res)
+	}
+
+	return resources, nil
+}
+
+// stat returns the properties of the resource at p.
+func (fs *FileSystem) stat(authority utils.Authority, p string) (*resource, error) {
+	resources, err := fs.propfind(authority, p, "0")
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, vfs.ErrNotExist
+	}
+	return &resources[0], nil
+}
+
+// list returns the names of the files (not collections) directly within the collection at dir.  A non-existent
+// collection results in an empty list.
+func (fs *FileSystem) list(authority utils.Authority, dir string) ([]string, error) {
+	resources, err := fs.propfind(authority, dir, "1")
+	if err != nil {
+		if errors.Is(err, vfs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, r := range resources {
+		if r.isCollection || utils.EnsureTrailingSlash(path.Dir(path.Clean(r.path))) != dir {
+			continue
+		}
+		names = append(names, path.Base(r.path))
+	}
+	return names, nil
+}
+
+// mkdirAll creates the collection at dir and any missing parents.
+func (fs *FileSystem) mkdirAll(authority utils.Authority, dir string) error {
+	if dir == "/" {
+		return nil
+	}
+	if r, err := fs.stat(authority, dir); err == nil && r.isCollection {
+		return nil
+	}
+
+	if err := fs.mkdirAll(authority, utils.EnsureTrailingSlash(path.Dir(path.Clean(dir)))); err != nil {
+		return err
+	}
+
+	// 405 Method Not Allowed is returned when the collection already exists
+	_, err := fs.doAndClose("MKCOL", authority, dir, nil, nil, http.StatusCreated, http.StatusMethodNotAllowed)
+	return err
+}
+
+// transfer natively copies or moves (per method) the file at src to dst on the same server.
+func (fs *FileSystem) transfer(method string, authority utils.Authority, src, dst string) error {
+	if err := fs.mkdirAll(authority, utils.EnsureTrailingSlash(path.Dir(dst))); err != nil {
+		return err
+	}
+
+	_, err := fs.doAndClose(method, authority, src, nil, map[string]string{
+		"Destination": fs.url(authority, dst).String(),
+		"Overwrite":   "T",
+	}, http.StatusCreated, http.StatusNoContent)
+	return err
+}
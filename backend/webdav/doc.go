@@ -0,0 +1,83 @@
+/*
+Package webdav - WebDAV VFS implementation.
+
+# Usage
+
+Rely on github.com/c2fo/vfs/v6/backend
+
+	import(
+	    "github.com/c2fo/vfs/v6/backend"
+	    "github.com/c2fo/vfs/v6/backend/webdav"
+	)
+
+	func UseFs() error {
+	    fs := backend.Backend(webdav.Scheme)    // WebDAV over http
+	    fs = backend.Backend(webdav.SecureScheme) // WebDAV over https
+	    ...
+	}
+
+Or call directly:
+
+	import "github.com/c2fo/vfs/v6/backend/webdav"
+
+	func DoSomething() {
+	    fs := webdav.NewSecureFileSystem()
+
+	    location, err := fs.NewLocation("myuser@dav.acme.com", "/some/path/")
+	    if err != nil {
+	        #handle error
+	    }
+	    ...
+	}
+
+webdav can be augmented with some implementation-specific methods.  Backend returns vfs.Filesystem interface so it
+would have to be cast as webdav.Filesystem to use them.
+
+These methods are chainable:
+(*FileSystem) WithClient(client *http.Client) *FileSystem
+(*FileSystem) WithOptions(opts vfs.Options) *FileSystem
+
+	func DoSomething() {
+	    fs := backend.Backend(webdav.SecureScheme).(*webdav.FileSystem)
+
+	    fs = fs.WithOptions(
+	        webdav.Options{
+	            Password:   "s3cr3t",
+	            HTTPClient: &http.Client{Timeout: time.Minute},
+	        },
+	    )
+	    ...
+	}
+
+# Protocol
+
+The webdav backend speaks WebDAV (RFC 4918) directly over net/http:
+
+  - Exists, Size and LastModified use PROPFIND with a depth of 0
+  - Read uses GET, with a Range header after a Seek
+  - Write streams a PUT request until Close, creating any missing parent collections with MKCOL first
+  - Delete uses DELETE
+  - CopyToFile and MoveToFile use the native COPY and MOVE methods when the target is on the same server (same scheme,
+    authority and options), otherwise the contents are streamed between files
+  - Location List, ListByPrefix and ListByRegex use PROPFIND with a depth of 1, skipping collections
+
+# Authentication
+
+Since user is part of the URI authority section (Volume), auth is handled similarly to the ftp and sftp backends.
+
+## USERNAME
+
+User may only be set in the URI authority section (Volume in vfs parlance), ie "webdavs://someuser@dav.acme.com/path/".
+If a username is provided, requests are sent with HTTP basic authentication.
+
+## PASSWORD
+
+Passwords may be passed via Options.Password or via the environmental variable *VFS_WEBDAV_PASSWORD*.  Options.Password,
+if set, overrides the env var.
+
+## BEARER TOKEN
+
+A bearer token may be passed via Options.BearerToken or via the environmental variable *VFS_WEBDAV_TOKEN*.  When set,
+it is used instead of basic authentication.
+*/
+package webdav
@@ -0,0 +1,304 @@
+package webdav
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// File implements vfs.File interface for WebDAV fs.
+type File struct {
+	fileSystem *FileSystem
+	authority  utils.Authority
+	path       string
+	cursorPos  int64
+	reader     io.ReadCloser
+	writer     *io.PipeWriter
+	writeErr   chan error
+}
+
+// LastModified returns the getlastmodified property of the file.
+func (f *File) LastModified() (*time.Time, error) {
+	r, err := f.stat()
+	if err != nil {
+		return nil, err
+	}
+	return &r.modified, nil
+}
+
+// Name returns the base name of the file path.
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the absolute path of the file, ie /some/path/to/file.txt
+func (f *File) Path() string {
+	return utils.EnsureLeadingSlash(f.path)
+}
+
+// Exists returns whether the file exists on the WebDAV server, using PROPFIND.
+func (f *File) Exists() (bool, error) {
+	_, err := f.stat()
+	if err != nil {
+		if errors.Is(err, vfs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Size returns the getcontentlength property of the file.
+func (f *File) Size() (uint64, error) {
+	r, err := f.stat()
+	if err != nil {
+		return 0, err
+	}
+	return r.size, nil
+}
+
+// Location returns a vfs.Location at the location of the file.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       utils.EnsureTrailingSlash(path.Dir(f.path)),
+		Authority:  f.authority,
+	}
+}
+
+// CopyToFile puts the contents of File into the targetFile passed.  Uses the WebDAV COPY method if the target file is
+// on the same server, otherwise uses io.CopyBuffer.
+func (f *File) CopyToFile(file vfs.File) error {
+	// validate seek is at 0,0 before doing copy
+	if f.cursorPos != 0 {
+		return vfs.CopyToNotPossible
+	}
+
+	if f.isSameServer(file) {
+		return f.fileSystem.transfer("COPY", f.authority, f.Path(), file.Path())
+	}
+
+	if err := utils.TouchCopyBuffered(file, f, 0); err != nil {
+		return err
+	}
+	// Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// CopyToLocation creates a copy of *File, using the file's current name as the new file's name at the given location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.CopyToFile(newFile)
+}
+
+// MoveToFile puts the contents of File into the targetFile passed.  Uses the WebDAV MOVE method if the target file is
+// on the same server, otherwise the file is copied, then deleted.
+func (f *File) MoveToFile(file vfs.File) error {
+	// validate seek is at 0,0 before doing move
+	if f.cursorPos != 0 {
+		return vfs.CopyToNotPossible
+	}
+
+	if f.isSameServer(file) {
+		return f.fileSystem.transfer("MOVE", f.authority, f.Path(), file.Path())
+	}
+
+	if err := f.CopyToFile(file); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// MoveToLocation moves the file to a file of the same name at the given location.
+func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.MoveToFile(newFile)
+}
+
+// Delete removes the file from the WebDAV server with the DELETE method.
+func (f *File) Delete(_ ...options.DeleteOption) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	_, err := f.fileSystem.doAndClose(http.MethodDelete, f.authority, f.Path(), nil, nil,
+		http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+// Close closes any open GET request and completes any PUT request started by Write.
+func (f *File) Close() error {
+	f.cursorPos = 0
+
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.writer != nil {
+		_ = f.writer.Close()
+		err := <-f.writeErr
+		f.writer = nil
+		f.writeErr = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read implements io.Reader, streaming the file's contents with GET (using a Range header when not at the start of
+// the file).
+func (f *File) Read(p []byte) (int, error) {
+	r, err := f.getReader()
+	if err != nil {
+		return 0, err
+	}
+
+	read, err := r.Read(p)
+	f.cursorPos += int64(read)
+	return read, err
+}
+
+// Seek implements io.Seeker.  The next Read after a Seek issues a new GET starting at the new offset.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	length, err := f.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	switch whence {
+	default:
+		return 0, vfs.ErrSeekInvalidWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.cursorPos
+	case io.SeekEnd:
+		offset += int64(length)
+	}
+	if offset < 0 {
+		return 0, vfs.ErrSeekInvalidOffset
+	}
+	f.cursorPos = offset
+
+	// invalidate reader
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return f.cursorPos, nil
+}
+
+// Write implements io.Writer.  The first Write starts a PUT request which streams all subsequent writes to the server
+// until Close is called.  Missing parent collections are created first.
+func (f *File) Write(data []byte) (int, error) {
+	if f.writer == nil {
+		if err := f.fileSystem.mkdirAll(f.authority, utils.EnsureTrailingSlash(path.Dir(f.path))); err != nil {
+			return 0, err
+		}
+
+		pr, pw := io.Pipe()
+		f.writer = pw
+		f.writeErr = make(chan error, 1)
+		go func() {
+			_, err := f.fileSystem.doAndClose(http.MethodPut, f.authority, f.Path(), pr, nil,
+				http.StatusOK, http.StatusCreated, http.StatusNoContent)
+			// unblock any pending Write if the request failed before consuming the body
+			_ = pr.CloseWithError(err)
+			f.writeErr <- err
+		}()
+	}
+
+	written, err := f.writer.Write(data)
+	f.cursorPos += int64(written)
+	return written, err
+}
+
+// Touch creates a zero-length file on the vfs.File if no File exists.  Update File's last modified timestamp.
+// Returns error if unable to touch File.
+func (f *File) Touch() error {
+	exists, err := f.Exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := f.Write([]byte{}); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+
+	// file already exists so update its last modified date
+	return utils.UpdateLastModifiedByMoving(f)
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+func (f *File) stat() (*resource, error) {
+	r, err := f.fileSystem.stat(f.authority, f.Path())
+	if err != nil {
+		return nil, err
+	}
+	if r.isCollection {
+		return nil, vfs.ErrNotExist
+	}
+	return r, nil
+}
+
+// isSameServer returns true if target is a WebDAV file on the same server, reachable with the same credentials.
+func (f *File) isSameServer(target vfs.File) bool {
+	tf, ok := target.(*File)
+	if !ok {
+		return false
+	}
+	return tf.fileSystem.secure == f.fileSystem.secure &&
+		tf.authority.String() == f.authority.String() &&
+		tf.fileSystem.getOptions() == f.fileSystem.getOptions()
+}
+
+func (f *File) getReader() (io.ReadCloser, error) {
+	if f.reader != nil {
+		return f.reader, nil
+	}
+
+	r, err := f.fileSystem.newClient().GetReader(f.authority, f.fileSystem.url(f.authority, f.Path()), f.cursorPos)
+	if err != nil {
+		return nil, err
+	}
+
+	f.reader = r
+	return f.reader, nil
+}
@@ -0,0 +1,154 @@
+package webdav
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend"
+	"github.com/c2fo/vfs/v6/backend/internal/httpclient"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+const (
+	// Scheme defines the filesystem type for WebDAV over plain HTTP.
+	Scheme = "webdav"
+	// SecureScheme defines the filesystem type for WebDAV over HTTPS.
+	SecureScheme = "webdavs"
+)
+
+const name = "WebDAV"
+
+// FileSystem implements vfs.Filesystem for the WebDAV filesystem.
+type FileSystem struct {
+	options vfs.Options
+	client  *http.Client
+	secure  bool
+}
+
+// Retry will return the default no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile function returns the WebDAV implementation of vfs.File.
+func (fs *FileSystem) NewFile(authority, filePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil webdav.FileSystem pointer is required")
+	}
+	if filePath == "" {
+		return nil, errors.New("non-empty string for path is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(filePath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		fileSystem: fs,
+		authority:  auth,
+		path:       path.Clean(filePath),
+	}, nil
+}
+
+// NewLocation function returns the WebDAV implementation of vfs.Location.
+func (fs *FileSystem) NewLocation(authority, locPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil webdav.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(locPath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{
+		fileSystem: fs,
+		path:       utils.EnsureTrailingSlash(path.Clean(locPath)),
+		Authority:  auth,
+	}, nil
+}
+
+// Name returns "WebDAV"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "webdav" or "webdavs" as the initial part of a file URI ie: webdav://
+func (fs *FileSystem) Scheme() string {
+	if fs.secure {
+		return SecureScheme
+	}
+	return Scheme
+}
+
+// Client returns the underlying http client, creating it, if necessary
+func (fs *FileSystem) Client() *http.Client {
+	if fs.client == nil {
+		fs.client = http.DefaultClient
+		if opts, ok := fs.options.(Options); ok && opts.HTTPClient != nil {
+			fs.client = opts.HTTPClient
+		}
+	}
+	return fs.client
+}
+
+// WithOptions sets options for client and returns the filesystem (chainable)
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	// only set options if vfs.Options is webdav.Options
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		// we set client to nil to ensure that a new client is created using the new options when Client() is called
+		fs.client = nil
+	}
+	return fs
+}
+
+// WithClient passes in an http client and returns the filesystem (chainable)
+func (fs *FileSystem) WithClient(client *http.Client) *FileSystem {
+	fs.client = client
+	return fs
+}
+
+// NewFileSystem initializer for a FileSystem using WebDAV over plain HTTP.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+// NewSecureFileSystem initializer for a FileSystem using WebDAV over HTTPS.
+func NewSecureFileSystem() *FileSystem {
+	return &FileSystem{secure: true}
+}
+
+// newClient returns the client of the file system's requests.
+func (fs *FileSystem) newClient() *httpclient.Client {
+	opts := fs.getOptions()
+	return &httpclient.Client{
+		Name:        "webdav",
+		Secure:      fs.secure,
+		HTTPClient:  fs.Client(),
+		Password:    httpclient.Fetch(opts.Password, envPassword),
+		BearerToken: httpclient.Fetch(opts.BearerToken, envToken),
+	}
+}
+
+func (fs *FileSystem) getOptions() Options {
+	if opts, ok := fs.options.(Options); ok {
+		return opts
+	}
+	return Options{}
+}
+
+func init() {
+	// registers default Filesystems
+	backend.Register(Scheme, NewFileSystem())
+	backend.Register(SecureScheme, NewSecureFileSystem())
+}
@@ -0,0 +1,112 @@
+package webdav
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/webdav"
+
+	"github.com/c2fo/vfs/v6/backend"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// newTestServer starts an in-process WebDAV server backed by memory, returning the server and its authority.
+func newTestServer() (*httptest.Server, string) {
+	server := httptest.NewServer(&webdav.Handler{
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+type fileSystemTestSuite struct {
+	suite.Suite
+}
+
+func (ts *fileSystemTestSuite) TestRegistered() {
+	ts.Equal(Scheme, backend.Backend(Scheme).Scheme())
+	ts.Equal(SecureScheme, backend.Backend(SecureScheme).Scheme())
+	ts.Equal("WebDAV", NewFileSystem().Name())
+}
+
+func (ts *fileSystemTestSuite) TestNewFile() {
+	fs := NewFileSystem()
+	file, err := fs.NewFile("user@host.com:8080", "/path/../to/file.txt")
+	ts.NoError(err)
+	ts.Equal("/to/file.txt", file.Path())
+	ts.Equal("webdav://user@host.com:8080/to/file.txt", file.URI())
+
+	file, err = NewSecureFileSystem().NewFile("host.com", "/file.txt")
+	ts.NoError(err)
+	ts.Equal("webdavs://host.com/file.txt", file.URI())
+
+	_, err = fs.NewFile("host.com", "relative.txt")
+	ts.EqualError(err, utils.ErrBadAbsFilePath)
+	_, err = fs.NewFile("host.com", "")
+	ts.Error(err)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewFile("host.com", "/file.txt")
+	ts.EqualError(err, "non-nil webdav.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation() {
+	fs := NewFileSystem()
+	loc, err := fs.NewLocation("host.com", "/some/path/")
+	ts.NoError(err)
+	ts.Equal("webdav://host.com/some/path/", loc.URI())
+
+	_, err = fs.NewLocation("host.com", "/some/path")
+	ts.EqualError(err, utils.ErrBadAbsLocationPath)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewLocation("host.com", "/")
+	ts.EqualError(err, "non-nil webdav.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestClientAndOptions() {
+	fs := NewFileSystem()
+	ts.Equal(http.DefaultClient, fs.Client())
+
+	client := &http.Client{}
+	fs.WithOptions(Options{HTTPClient: client})
+	ts.Equal(client, fs.Client())
+
+	other := &http.Client{}
+	fs.WithClient(other)
+	ts.Equal(other, fs.Client())
+}
+
+func (ts *fileSystemTestSuite) TestAuthentication() {
+	var gotUser, gotPass, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotPass, _ = r.BasicAuth()
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	fs := NewFileSystem().WithOptions(Options{Password: "s3cr3t"})
+	file, err := fs.NewFile("bob@"+host, "/file.txt")
+	ts.Require().NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	ts.Equal("bob", gotUser)
+	ts.Equal("s3cr3t", gotPass)
+
+	fs = NewFileSystem().WithOptions(Options{BearerToken: "tok"})
+	file, err = fs.NewFile("bob@"+host, "/file.txt")
+	ts.Require().NoError(err)
+	_, err = file.Exists()
+	ts.NoError(err)
+	ts.Equal("Bearer tok", gotAuth)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
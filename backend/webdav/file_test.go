@@ -0,0 +1,213 @@
+package webdav
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type fileTestSuite struct {
+	suite.Suite
+	server    *httptest.Server
+	authority string
+	fs        *FileSystem
+}
+
+func (ts *fileTestSuite) SetupTest() {
+	ts.server, ts.authority = newTestServer()
+	ts.fs = NewFileSystem()
+}
+
+func (ts *fileTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *fileTestSuite) writeFile(p, contents string) vfs.File {
+	file, err := ts.fs.NewFile(ts.authority, p)
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.Require().NoError(err)
+	ts.Require().NoError(file.Close())
+	return file
+}
+
+func (ts *fileTestSuite) TestWriteAndRead() {
+	file := ts.writeFile("/new/dirs/file.txt", "hello world")
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(11), size)
+
+	modified, err := file.LastModified()
+	ts.NoError(err)
+	ts.WithinDuration(time.Now(), *modified, time.Minute)
+
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+	ts.NoError(file.Close())
+
+	// overwrite
+	ts.writeFile("/new/dirs/file.txt", "bye")
+	data, err = io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("bye", string(data))
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestNotExist() {
+	file, err := ts.fs.NewFile(ts.authority, "/missing.txt")
+	ts.Require().NoError(err)
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	_, err = file.Size()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = file.Read(make([]byte, 1))
+	ts.ErrorIs(err, vfs.ErrNotExist)
+
+	// a collection is not a file
+	ts.writeFile("/dir/file.txt", "x")
+	dir, err := ts.fs.NewFile(ts.authority, "/dir")
+	ts.Require().NoError(err)
+	exists, err = dir.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileTestSuite) TestSeek() {
+	file := ts.writeFile("/file.txt", "0123456789")
+
+	pos, err := file.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	ts.Equal(int64(6), pos)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("6789", string(data))
+
+	pos, err = file.Seek(-3, io.SeekEnd)
+	ts.NoError(err)
+	ts.Equal(int64(7), pos)
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("78", string(buf))
+
+	pos, err = file.Seek(-5, io.SeekCurrent)
+	ts.NoError(err)
+	ts.Equal(int64(4), pos)
+	_, err = io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("45", string(buf))
+
+	_, err = file.Seek(0, io.SeekEnd)
+	ts.NoError(err)
+	data, err = io.ReadAll(file)
+	ts.NoError(err)
+	ts.Empty(data)
+
+	_, err = file.Seek(-20, io.SeekCurrent)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidOffset)
+	_, err = file.Seek(0, 5)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidWhence)
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestCopyAndMoveNative() {
+	src := ts.writeFile("/src/file.txt", "contents")
+
+	dstLoc, err := ts.fs.NewLocation(ts.authority, "/dst/nested/")
+	ts.Require().NoError(err)
+	copied, err := src.CopyToLocation(dstLoc)
+	ts.Require().NoError(err)
+	data, err := io.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("contents", string(data))
+	ts.NoError(copied.Close())
+
+	moveTarget, err := ts.fs.NewFile(ts.authority, "/moved/renamed.txt")
+	ts.Require().NoError(err)
+	ts.NoError(src.MoveToFile(moveTarget))
+	exists, err := src.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	exists, err = moveTarget.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	moved, err := moveTarget.MoveToLocation(dstLoc)
+	ts.Require().NoError(err)
+	ts.Equal("/dst/nested/renamed.txt", moved.Path())
+}
+
+func (ts *fileTestSuite) TestCopyAcrossBackends() {
+	src := ts.writeFile("/file.txt", "across backends")
+
+	memFile, err := mem.NewFileSystem().NewFile("", "/copy.txt")
+	ts.Require().NoError(err)
+	ts.NoError(src.CopyToFile(memFile))
+	data, err := io.ReadAll(memFile)
+	ts.NoError(err)
+	ts.Equal("across backends", string(data))
+	ts.NoError(memFile.Close())
+
+	// and back onto another server (not native)
+	otherServer, otherAuthority := newTestServer()
+	defer otherServer.Close()
+	target, err := ts.fs.NewFile(otherAuthority, "/copy.txt")
+	ts.Require().NoError(err)
+	ts.NoError(memFile.CopyToFile(target))
+	data, err = io.ReadAll(target)
+	ts.NoError(err)
+	ts.Equal("across backends", string(data))
+
+	_, err = src.Seek(1, io.SeekStart)
+	ts.NoError(err)
+	ts.ErrorIs(src.CopyToFile(target), vfs.CopyToNotPossible)
+}
+
+func (ts *fileTestSuite) TestTouchAndDelete() {
+	file, err := ts.fs.NewFile(ts.authority, "/touched/file.txt")
+	ts.Require().NoError(err)
+	ts.NoError(file.Touch())
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Zero(size)
+
+	// touching an existing file keeps its contents
+	ts.writeFile("/touched/file.txt", "keep")
+	ts.NoError(file.Touch())
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("keep", string(data))
+
+	ts.NoError(file.Delete())
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	ts.ErrorIs(file.Delete(), vfs.ErrNotExist)
+}
+
+func (ts *fileTestSuite) TestNameAndLocation() {
+	file, err := ts.fs.NewFile(ts.authority, "/some/path/file.txt")
+	ts.Require().NoError(err)
+	ts.Equal("file.txt", file.Name())
+	ts.Equal("/some/path/", file.Location().Path())
+	ts.Equal("webdav://"+ts.authority+"/some/path/file.txt", file.String())
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
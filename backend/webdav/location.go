@@ -0,0 +1,164 @@
+package webdav
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Location implements the vfs.Location interface specific to WebDAV fs.
+type Location struct {
+	fileSystem *FileSystem
+	path       string
+	Authority  utils.Authority
+}
+
+// List calls PROPFIND with a depth of 1 to list all files in the location's collection.
+func (l *Location) List() ([]string, error) {
+	return l.fileSystem.list(l.Authority, l.Path())
+}
+
+// ListByPrefix lists the files of the location's collection, modified relatively by the prefix arg, that start with
+// the prefix.
+//   - Returns ([]string{}, nil) in the case of a non-existent directory/prefix/location.
+//   - "relative" prefixes are allowed, ie, listByPrefix from "/some/path/" with prefix "to/somepattern" is the same as
+//     location "/some/path/to/" with prefix of "somepattern"
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := utils.ValidatePrefix(prefix); err != nil {
+		return []string{}, err
+	}
+
+	dir := l.Path()
+	// if prefix has a dir component, use it's location and basename of prefix
+	if d := path.Dir(prefix); d != "." {
+		dir = utils.EnsureTrailingSlash(path.Join(dir, d))
+		prefix = path.Base(prefix)
+	}
+
+	names, err := l.fileSystem.list(l.Authority, dir)
+	if err != nil {
+		return []string{}, err
+	}
+
+	filtered := make([]string, 0)
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// ListByRegex retrieves the filenames of all the files at the location's current path, then filters out all those
+// that don't match the given regex. The resource considerations of List() apply here as well.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	names, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]string, 0)
+	for _, name := range names {
+		if regex.MatchString(name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+// Volume returns the Authority the location is contained in.
+func (l *Location) Volume() string {
+	return l.Authority.String()
+}
+
+// Path returns the path the location references in most WebDAV calls.
+func (l *Location) Path() string {
+	return utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(l.path))
+}
+
+// Exists returns true if the location's collection exists on the WebDAV server.
+func (l *Location) Exists() (bool, error) {
+	r, err := l.fileSystem.stat(l.Authority, l.Path())
+	if err != nil {
+		if errors.Is(err, vfs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return r.isCollection, nil
+}
+
+// NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
+// relativePath argument, returning the resulting location.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	if l == nil {
+		return nil, errors.New("non-nil webdav.Location pointer is required")
+	}
+
+	// make a copy of the original location first, then ChangeDir, leaving the original location as-is
+	newLocation := &Location{}
+	*newLocation = *l
+	err := newLocation.ChangeDir(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir takes a relative path, and modifies the underlying Location's path.
+func (l *Location) ChangeDir(relativePath string) error {
+	if l == nil {
+		return errors.New("non-nil webdav.Location pointer is required")
+	}
+	if err := utils.ValidateRelativeLocationPath(relativePath); err != nil {
+		return err
+	}
+	l.path = utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(path.Join(l.path, relativePath)))
+	return nil
+}
+
+// NewFile uses the properties of the calling location to generate a vfs.File (backed by a webdav.File). The filePath
+// argument is expected to be a relative path to the location's current path.
+func (l *Location) NewFile(filePath string) (vfs.File, error) {
+	if l == nil {
+		return nil, errors.New("non-nil webdav.Location pointer is required")
+	}
+	if err := utils.ValidateRelativeFilePath(filePath); err != nil {
+		return nil, err
+	}
+	return &File{
+		fileSystem: l.fileSystem,
+		authority:  l.Authority,
+		path:       utils.EnsureLeadingSlash(path.Join(l.path, filePath)),
+	}, nil
+}
+
+// DeleteFile removes the file at fileName path.
+func (l *Location) DeleteFile(fileName string, opts ...options.DeleteOption) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return file.Delete(opts...)
+}
+
+// FileSystem returns a vfs.fileSystem interface of the location's underlying fileSystem.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
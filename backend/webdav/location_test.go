@@ -0,0 +1,107 @@
+package webdav
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+)
+
+type locationTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	fs     *FileSystem
+	loc    vfs.Location
+}
+
+func (ts *locationTestSuite) SetupTest() {
+	var authority string
+	ts.server, authority = newTestServer()
+	ts.fs = NewFileSystem()
+
+	var err error
+	ts.loc, err = ts.fs.NewLocation(authority, "/some/path/")
+	ts.Require().NoError(err)
+
+	for _, name := range []string{"a.txt", "b.csv", "ab.txt", "sub/c.txt"} {
+		f, err := ts.loc.NewFile(name)
+		ts.Require().NoError(err)
+		ts.Require().NoError(f.Touch())
+	}
+}
+
+func (ts *locationTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *locationTestSuite) TestList() {
+	list, err := ts.loc.List()
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.txt", "ab.txt", "b.csv"}, list, "collections are not listed")
+
+	list, err = ts.loc.ListByPrefix("a")
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.txt", "ab.txt"}, list)
+
+	list, err = ts.loc.ListByPrefix("sub/c")
+	ts.NoError(err)
+	ts.Equal([]string{"c.txt"}, list)
+
+	list, err = ts.loc.ListByRegex(regexp.MustCompile(`\.csv$`))
+	ts.NoError(err)
+	ts.Equal([]string{"b.csv"}, list)
+
+	missing, err := ts.loc.NewLocation("missing/")
+	ts.Require().NoError(err)
+	list, err = missing.List()
+	ts.NoError(err)
+	ts.Equal([]string{}, list)
+}
+
+func (ts *locationTestSuite) TestExists() {
+	exists, err := ts.loc.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	missing, err := ts.loc.NewLocation("missing/")
+	ts.Require().NoError(err)
+	exists, err = missing.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *locationTestSuite) TestNewLocationAndChangeDir() {
+	newLoc, err := ts.loc.NewLocation("../other/")
+	ts.NoError(err)
+	ts.Equal("/some/other/", newLoc.Path())
+	ts.Equal("/some/path/", ts.loc.Path())
+
+	ts.NoError(newLoc.ChangeDir("deeper/"))
+	ts.Equal("/some/other/deeper/", newLoc.Path())
+	ts.Error(newLoc.ChangeDir("/abs/"))
+	ts.Equal(ts.fs, newLoc.FileSystem())
+	ts.Equal(ts.loc.Volume(), newLoc.Volume())
+}
+
+func (ts *locationTestSuite) TestNewFileAndDeleteFile() {
+	file, err := ts.loc.NewFile("sub/../a.txt")
+	ts.NoError(err)
+	ts.Equal("/some/path/a.txt", file.Path())
+
+	_, err = ts.loc.NewFile("/abs.txt")
+	ts.Error(err)
+
+	ts.NoError(ts.loc.DeleteFile("a.txt"))
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+
+	ts.ErrorIs(ts.loc.DeleteFile("a.txt"), vfs.ErrNotExist)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
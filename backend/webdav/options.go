@@ -0,0 +1,17 @@
+package webdav
+
+import (
+	"net/http"
+)
+
+// Options struct implements the vfs.Options interface, providing optional parameters for creating a webdav filesystem.
+type Options struct {
+	Password    string       `json:"password,omitempty"`    // env var VFS_WEBDAV_PASSWORD
+	BearerToken string       `json:"bearerToken,omitempty"` // env var VFS_WEBDAV_TOKEN
+	HTTPClient  *http.Client `json:"-"`
+}
+
+const (
+	envPassword = "VFS_WEBDAV_PASSWORD" //nolint:gosec
+	envToken    = "VFS_WEBDAV_TOKEN"    //nolint:gosec
+)
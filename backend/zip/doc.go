@@ -0,0 +1,76 @@
+/*
+Package zip provides a vfs.FileSystem for browsing, reading and building zip archives stored on any vfs backend.
+
+A zip FileSystem is created from a vfs.File containing (or that will contain) the archive.  Each entry in the archive is
+exposed as a vfs.File and directories within the archive as vfs.Locations, so existing vfs code can process zip bundles
+in place without first copying and extracting them locally.
+
+# Reading
+
+NewFileSystem opens an existing archive for reading.  If the archive vfs.File implements io.ReaderAt it is used
+directly, otherwise random access is emulated with Seek and Read on the archive file.  Sequential reads are not
+re-seeked, so streaming backends like s3 only issue a new ranged request when the zip reader jumps to a different part
+of the archive.
+
+	import(
+	    "github.com/c2fo/vfs/v6/backend/zip"
+	    "github.com/c2fo/vfs/v6/vfssimple"
+	)
+
+	func DoSomething() error {
+	    archive, err := vfssimple.NewFile("s3://mybucket/bundles/bundle.zip")
+	    if err != nil {
+	        return err
+	    }
+
+	    fs, err := zip.NewFileSystem(archive)
+	    if err != nil {
+	        return err
+	    }
+	    defer fs.Close()
+
+	    loc, err := fs.NewLocation("", "/reports/")
+	    if err != nil {
+	        return err
+	    }
+
+	    names, err := loc.List()
+	    ...
+	}
+
+Entries of an archive opened for reading can't be written, touched, moved or deleted; ErrReadOnly is returned.
+
+# Writing
+
+NewWriteFileSystem builds a new archive in the given vfs.File.  Each file written is added to the archive as a
+(deflate-compressed) entry, streamed to the archive as it's written, so entries aren't held in memory.  Only one entry
+can be streamed at a time: files written while another is still open are buffered in memory and added when they're
+closed, after the open one.  The archive is finalized and the target file closed when the FileSystem is closed.
+
+	func WriteBundle(target vfs.File) error {
+	    fs, err := zip.NewWriteFileSystem(target)
+	    if err != nil {
+	        return err
+	    }
+
+	    f, err := fs.NewFile("", "/data/output.csv")
+	    if err != nil {
+	        return err
+	    }
+	    if _, err := f.Write([]byte("a,b,c\n")); err != nil {
+	        return err
+	    }
+	    if err := f.Close(); err != nil {
+	        return err
+	    }
+
+	    // writes the zip central directory and closes target
+	    return fs.Close()
+	}
+
+Entries of an archive opened for writing can't be read and each entry may only be written once.
+
+Entries can never be deleted from an archive, so Delete, DeleteFile, MoveToFile and MoveToLocation always return
+ErrDeleteNotSupported.  A zip FileSystem is not registered with the backend package since it requires an archive file.
+*/
+package zip
@@ -0,0 +1,258 @@
+package zip
+
+import (
+	"errors"
+	"io"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend"
+	"github.com/c2fo/vfs/v6/backend/internal/archiveutil"
+	"github.com/c2fo/vfs/v6/options"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// File implements the vfs.File interface for an entry within a zip archive.
+type File struct {
+	fileSystem  *FileSystem
+	name        string
+	cursorPos   int64
+	reader      io.ReadCloser
+	writeBuffer archiveutil.WriteBuffer
+	streaming   bool // the file's entry is being written straight to the archive
+}
+
+// Close closes any open entry reader and resets the cursor.  For archives opened for writing, the file's entry is
+// finished, or anything buffered for it added to the archive as a new entry.
+func (f *File) Close() error {
+	f.cursorPos = 0
+
+	if f.reader != nil {
+		if err := f.reader.Close(); err != nil {
+			return err
+		}
+		f.reader = nil
+	}
+
+	if f.streaming {
+		f.streaming = false
+		return f.fileSystem.endEntry()
+	}
+	return f.writeBuffer.Flush(func(data []byte) error {
+		return f.fileSystem.addEntry(f.name, data)
+	})
+}
+
+// Read implements io.Reader, decompressing the entry's contents.
+func (f *File) Read(p []byte) (int, error) {
+	r, err := f.getReader()
+	if err != nil {
+		return 0, err
+	}
+
+	read, err := r.Read(p)
+	f.cursorPos += int64(read)
+	return read, err
+}
+
+// Seek implements io.Seeker.  Since compressed entries can't be randomly accessed, the next Read after a Seek
+// decompresses and discards the entry's contents up to the new offset.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.fileSystem.writer != nil {
+		return 0, ErrWriteOnly
+	}
+
+	length, err := f.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	switch whence {
+	default:
+		return 0, vfs.ErrSeekInvalidWhence
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.cursorPos
+	case io.SeekEnd:
+		offset += int64(length)
+	}
+	if offset < 0 {
+		return 0, vfs.ErrSeekInvalidOffset
+	}
+
+	if offset != f.cursorPos && f.reader != nil {
+		if err := f.reader.Close(); err != nil {
+			return 0, err
+		}
+		f.reader = nil
+	}
+	f.cursorPos = offset
+
+	return f.cursorPos, nil
+}
+
+// Write implements io.Writer, writing data straight to the file's entry in the archive.  While another file's entry is
+// being written, data is buffered until Close adds it to the archive instead.  Returns ErrReadOnly for archives opened
+// for reading.
+func (f *File) Write(p []byte) (int, error) {
+	if f.fileSystem.writer == nil {
+		return 0, ErrReadOnly
+	}
+	if !f.streaming && !f.writeBuffer.Written() {
+		started, err := f.fileSystem.startEntry(f.name)
+		if err != nil {
+			return 0, err
+		}
+		f.streaming = started
+	}
+
+	var written int
+	var err error
+	if f.streaming {
+		written, err = f.fileSystem.writeEntry(p)
+	} else {
+		written, err = f.writeBuffer.Write(p)
+	}
+	f.cursorPos += int64(written)
+	return written, err
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+// Exists returns true if the entry exists in the archive.
+func (f *File) Exists() (bool, error) {
+	_, ok := f.fileSystem.entries.Get(f.name)
+	return ok, nil
+}
+
+// Location returns the zip Location of the directory containing the entry.
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		name:       utils.EnsureTrailingSlash(path.Dir(f.name)),
+	}
+}
+
+// CopyToLocation copies the entry's contents to a file of the same name at the given location.
+func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile, f.CopyToFile(newFile)
+}
+
+// CopyToFile copies the entry's contents to the given file.
+func (f *File) CopyToFile(file vfs.File) error {
+	if err := backend.ValidateCopySeekPosition(f); err != nil {
+		return err
+	}
+
+	if err := utils.TouchCopyBuffered(file, f, 0); err != nil {
+		return err
+	}
+	// Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// MoveToLocation always returns ErrDeleteNotSupported since entries cannot be removed from a zip archive.
+func (f *File) MoveToLocation(_ vfs.Location) (vfs.File, error) {
+	return nil, ErrDeleteNotSupported
+}
+
+// MoveToFile always returns ErrDeleteNotSupported since entries cannot be removed from a zip archive.
+func (f *File) MoveToFile(_ vfs.File) error {
+	return ErrDeleteNotSupported
+}
+
+// Delete always returns ErrDeleteNotSupported since entries cannot be removed from a zip archive.
+func (f *File) Delete(_ ...options.DeleteOption) error {
+	return ErrDeleteNotSupported
+}
+
+// LastModified returns the modification time recorded for the entry.
+func (f *File) LastModified() (*time.Time, error) {
+	e, ok := f.fileSystem.entries.Get(f.name)
+	if !ok {
+		return nil, vfs.ErrNotExist
+	}
+	modified := e.modified
+	return &modified, nil
+}
+
+// Size returns the uncompressed size of the entry.
+func (f *File) Size() (uint64, error) {
+	e, ok := f.fileSystem.entries.Get(f.name)
+	if !ok {
+		return 0, vfs.ErrNotExist
+	}
+	return e.size, nil
+}
+
+// Path returns the absolute path of the entry within the archive, ie /some/path/to/file.txt
+func (f *File) Path() string {
+	return f.name
+}
+
+// Name returns the base name of the entry.
+func (f *File) Name() string {
+	return path.Base(f.name)
+}
+
+// Touch adds an empty entry to an archive opened for writing if it hasn't already been written.  Returns ErrReadOnly
+// for archives opened for reading.
+func (f *File) Touch() error {
+	if f.fileSystem.writer == nil {
+		return ErrReadOnly
+	}
+	if exists, _ := f.Exists(); exists {
+		return nil
+	}
+	if _, err := f.Write([]byte{}); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+func (f *File) getReader() (io.ReadCloser, error) {
+	if f.reader != nil {
+		return f.reader, nil
+	}
+	if f.fileSystem.writer != nil {
+		return nil, ErrWriteOnly
+	}
+
+	e, ok := f.fileSystem.entries.Get(f.name)
+	if !ok {
+		return nil, vfs.ErrNotExist
+	}
+
+	rc, err := e.zipFile.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	// discard up to the current cursor position after a Seek
+	if f.cursorPos > 0 {
+		if _, err := io.CopyN(io.Discard, rc, f.cursorPos); err != nil && !errors.Is(err, io.EOF) {
+			_ = rc.Close()
+			return nil, err
+		}
+	}
+
+	f.reader = rc
+	return f.reader, nil
+}
@@ -0,0 +1,321 @@
+package zip
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/internal/archiveutil"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+// Scheme defines the file system type.
+const Scheme = "zip"
+const name = "zip archive"
+
+var (
+	// ErrReadOnly is returned when attempting to modify an archive opened for reading.
+	ErrReadOnly = errors.New("zip archive is opened for reading; entries cannot be written")
+	// ErrWriteOnly is returned when attempting to read from an archive opened for writing.
+	ErrWriteOnly = errors.New("zip archive is opened for writing; entries cannot be read")
+	// ErrDeleteNotSupported is returned when attempting to delete (or move) an archive entry.
+	ErrDeleteNotSupported = errors.New("entries cannot be deleted from a zip archive")
+)
+
+// entry describes a single file within the archive.
+type entry struct {
+	zipFile  *zip.File // nil for entries added by a writer
+	size     uint64
+	modified time.Time
+}
+
+// openEntry is the entry being written to the archive by a file that hasn't been closed yet.
+type openEntry struct {
+	name     string
+	writer   io.Writer
+	size     uint64
+	modified time.Time
+}
+
+// pendingEntry is an entry whose contents were buffered while another entry was being written.
+type pendingEntry struct {
+	name     string
+	data     []byte
+	modified time.Time
+}
+
+// FileSystem implements vfs.FileSystem for the entries of a zip archive stored in a vfs.File.
+type FileSystem struct {
+	archive vfs.File
+	mu      sync.Mutex // guards writing entries and closing the archive
+	entries *archiveutil.Index[*entry]
+	writer  *zip.Writer
+	open    *openEntry
+	pending []pendingEntry
+	closed  bool
+}
+
+// NewFileSystem opens the zip archive stored in the given file for reading.
+func NewFileSystem(archive vfs.File) (*FileSystem, error) {
+	if archive == nil {
+		return nil, errors.New("non-nil archive vfs.File is required")
+	}
+
+	size, err := archive.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	readerAt, ok := archive.(io.ReaderAt)
+	if !ok {
+		readerAt = &fileReaderAt{file: archive, pos: -1}
+	}
+
+	zr, err := zip.NewReader(readerAt, int64(size))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read zip archive %s: %w", archive, err)
+	}
+
+	fs := &FileSystem{
+		archive: archive,
+		entries: archiveutil.NewIndex[*entry](),
+	}
+	for _, zf := range zr.File {
+		// skip explicit directory entries, directories are derived from file paths
+		if strings.HasSuffix(zf.Name, "/") {
+			continue
+		}
+		fs.entries.Set(path.Clean("/"+zf.Name), &entry{
+			zipFile:  zf,
+			size:     zf.UncompressedSize64,
+			modified: zf.Modified,
+		})
+	}
+
+	return fs, nil
+}
+
+// NewWriteFileSystem creates a FileSystem that builds a new zip archive in the given file.  A file's entry is written
+// to the archive as the file is written, unless another file's entry is still being written, and the archive is
+// finalized by FileSystem.Close.
+func NewWriteFileSystem(archive vfs.File) (*FileSystem, error) {
+	if archive == nil {
+		return nil, errors.New("non-nil archive vfs.File is required")
+	}
+	return &FileSystem{
+		archive: archive,
+		entries: archiveutil.NewIndex[*entry](),
+		writer:  zip.NewWriter(archive),
+	}, nil
+}
+
+// Retry will return the default no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile returns a zip File for the entry at the given absolute path within the archive.  Volume is ignored.
+func (fs *FileSystem) NewFile(volume, absFilePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil zip.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(absFilePath); err != nil {
+		return nil, err
+	}
+	return &File{
+		fileSystem: fs,
+		name:       path.Clean(absFilePath),
+	}, nil
+}
+
+// NewLocation returns a zip Location for the directory at the given absolute path within the archive.  Volume is
+// ignored.
+func (fs *FileSystem) NewLocation(volume, absLocPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil zip.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(absLocPath); err != nil {
+		return nil, err
+	}
+	return &Location{
+		fileSystem: fs,
+		name:       utils.EnsureTrailingSlash(path.Clean(absLocPath)),
+	}, nil
+}
+
+// Name returns "zip archive"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "zip" as the initial part of a file URI ie: zip://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// Archive returns the vfs.File containing the zip archive.
+func (fs *FileSystem) Archive() vfs.File {
+	return fs.archive
+}
+
+// Close finalizes the archive when opened for writing, then closes the archive file.
+func (fs *FileSystem) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.closed {
+		return nil
+	}
+	fs.closed = true
+
+	if fs.writer != nil {
+		// the entry of a file that wasn't closed keeps what was written to it
+		if fs.open != nil {
+			fs.entries.Set(fs.open.name, &entry{size: fs.open.size, modified: fs.open.modified})
+			fs.open = nil
+		}
+		if err := fs.writePending(); err != nil {
+			return err
+		}
+		if err := fs.writer.Close(); err != nil {
+			return err
+		}
+	}
+	return fs.archive.Close()
+}
+
+// checkNewEntry returns an error if an entry can't be added at absFilePath.  fs.mu must be held.
+func (fs *FileSystem) checkNewEntry(absFilePath string) error {
+	if fs.closed {
+		return errors.New("zip archive is closed")
+	}
+	if _, ok := fs.entries.Get(absFilePath); ok || (fs.open != nil && fs.open.name == absFilePath) {
+		return fmt.Errorf("zip entry %s has already been written", absFilePath)
+	}
+	return nil
+}
+
+// createEntry starts a new entry in the archive.  fs.mu must be held.
+func (fs *FileSystem) createEntry(absFilePath string, modified time.Time) (io.Writer, error) {
+	return fs.writer.CreateHeader(&zip.FileHeader{
+		Name:     utils.RemoveLeadingSlash(absFilePath),
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+}
+
+// startEntry starts writing the entry at absFilePath straight to the archive, returning false if another file's entry
+// is being written, in which case the file's contents are buffered until it's closed.
+func (fs *FileSystem) startEntry(absFilePath string) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.checkNewEntry(absFilePath); err != nil {
+		return false, err
+	}
+	if fs.open != nil {
+		return false, nil
+	}
+
+	modified := time.Now()
+	w, err := fs.createEntry(absFilePath, modified)
+	if err != nil {
+		return false, err
+	}
+	fs.open = &openEntry{name: absFilePath, writer: w, modified: modified}
+	return true, nil
+}
+
+// writeEntry writes p to the entry being written.
+func (fs *FileSystem) writeEntry(p []byte) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.open == nil {
+		return 0, errors.New("zip archive is closed")
+	}
+	written, err := fs.open.writer.Write(p)
+	fs.open.size += uint64(written)
+	return written, err
+}
+
+// endEntry finishes the entry being written, then adds the entries buffered in the meantime.
+func (fs *FileSystem) endEntry() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.open == nil {
+		return errors.New("zip archive is closed")
+	}
+	fs.entries.Set(fs.open.name, &entry{size: fs.open.size, modified: fs.open.modified})
+	fs.open = nil
+	return fs.writePending()
+}
+
+// addEntry adds data to the archive as a new entry, once the entry being written, if any, is finished.
+func (fs *FileSystem) addEntry(absFilePath string, data []byte) error {
+	if fs.writer == nil {
+		return ErrReadOnly
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.checkNewEntry(absFilePath); err != nil {
+		return err
+	}
+
+	modified := time.Now()
+	fs.entries.Set(absFilePath, &entry{size: uint64(len(data)), modified: modified})
+	fs.pending = append(fs.pending, pendingEntry{name: absFilePath, data: data, modified: modified})
+	if fs.open != nil {
+		return nil
+	}
+	return fs.writePending()
+}
+
+// writePending writes the buffered entries to the archive, in the order their files were closed.  fs.mu must be held.
+func (fs *FileSystem) writePending() error {
+	for len(fs.pending) > 0 {
+		pending := fs.pending[0]
+		w, err := fs.createEntry(pending.name, pending.modified)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(pending.data); err != nil {
+			return err
+		}
+		fs.pending = fs.pending[1:]
+	}
+	return nil
+}
+
+// fileReaderAt emulates io.ReaderAt on a vfs.File using Seek and Read.  Reads continuing from the end of the previous
+// read are not re-seeked to avoid needless requests on streaming backends.
+type fileReaderAt struct {
+	mu   sync.Mutex
+	file vfs.File
+	pos  int64
+}
+
+// ReadAt implements io.ReaderAt
+func (r *fileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off != r.pos {
+		pos, err := r.file.Seek(off, io.SeekStart)
+		if err != nil {
+			return 0, err
+		}
+		r.pos = pos
+	}
+
+	n, err := io.ReadFull(r.file, p)
+	r.pos += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,149 @@
+package zip
+
+import (
+	"archive/zip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+	"github.com/c2fo/vfs/v6/utils"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	archive vfs.File
+	fs      *FileSystem
+}
+
+// writeArchive writes a zip archive containing the given entries (name -> contents) to file.
+func writeArchive(file vfs.File, entries map[string]string) error {
+	zw := zip.NewWriter(file)
+	for name, contents := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, contents); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	var err error
+	ts.archive, err = mem.NewFileSystem().NewFile("", "/bundles/bundle.zip")
+	ts.Require().NoError(err)
+	ts.Require().NoError(writeArchive(ts.archive, map[string]string{
+		"readme.txt":              "read me",
+		"reports/":                "",
+		"reports/daily.csv":       "a,b,c\n1,2,3\n",
+		"reports/weekly.csv":      "x,y,z\n",
+		"reports/2024/annual.txt": "annual",
+	}))
+
+	ts.fs, err = NewFileSystem(ts.archive)
+	ts.Require().NoError(err)
+}
+
+func (ts *fileSystemTestSuite) TestNewFileSystem() {
+	ts.Equal(Scheme, ts.fs.Scheme())
+	ts.Equal("zip archive", ts.fs.Name())
+	ts.Equal(ts.archive, ts.fs.Archive())
+	ts.Equal(4, ts.fs.entries.Len(), "directory entries are skipped")
+
+	_, err := NewFileSystem(nil)
+	ts.EqualError(err, "non-nil archive vfs.File is required")
+
+	notZip, err := mem.NewFileSystem().NewFile("", "/not.zip")
+	ts.Require().NoError(err)
+	_, err = notZip.Write([]byte("this is not a zip archive"))
+	ts.NoError(err)
+	ts.NoError(notZip.Close())
+	_, err = NewFileSystem(notZip)
+	ts.ErrorIs(err, zip.ErrFormat)
+}
+
+func (ts *fileSystemTestSuite) TestNewFile() {
+	file, err := ts.fs.NewFile("", "/reports/../reports/daily.csv")
+	ts.NoError(err)
+	ts.Equal("/reports/daily.csv", file.Path())
+	ts.Equal("zip:///reports/daily.csv", file.URI())
+
+	_, err = ts.fs.NewFile("", "reports/daily.csv")
+	ts.EqualError(err, utils.ErrBadAbsFilePath)
+
+	var nilFs *FileSystem
+	_, err = nilFs.NewFile("", "/file.txt")
+	ts.EqualError(err, "non-nil zip.FileSystem pointer is required")
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation() {
+	loc, err := ts.fs.NewLocation("", "/reports/")
+	ts.NoError(err)
+	ts.Equal("/reports/", loc.Path())
+	ts.Equal("zip:///reports/", loc.URI())
+
+	_, err = ts.fs.NewLocation("", "/reports")
+	ts.EqualError(err, utils.ErrBadAbsLocationPath)
+}
+
+func (ts *fileSystemTestSuite) TestWriteFileSystem() {
+	target, err := mem.NewFileSystem().NewFile("", "/out/new.zip")
+	ts.Require().NoError(err)
+
+	wfs, err := NewWriteFileSystem(target)
+	ts.Require().NoError(err)
+	for name, contents := range map[string]string{"/a.txt": "aaa", "/dir/b.txt": "bbb"} {
+		f, err := wfs.NewFile("", name)
+		ts.Require().NoError(err)
+		_, err = f.Write([]byte(contents))
+		ts.NoError(err)
+		ts.NoError(f.Close())
+	}
+
+	list := wfs.entries.List("/", func(string) bool { return true })
+	ts.Equal([]string{"a.txt"}, list)
+
+	ts.NoError(wfs.Close())
+	ts.NoError(wfs.Close(), "closing twice is a no-op")
+
+	// read back the archive
+	rfs, err := NewFileSystem(target)
+	ts.Require().NoError(err)
+	f, err := rfs.NewFile("", "/dir/b.txt")
+	ts.Require().NoError(err)
+	data, err := io.ReadAll(f)
+	ts.NoError(err)
+	ts.Equal("bbb", string(data))
+
+	_, err = NewWriteFileSystem(nil)
+	ts.EqualError(err, "non-nil archive vfs.File is required")
+}
+
+func (ts *fileSystemTestSuite) TestFileReaderAt() {
+	ra := &fileReaderAt{file: ts.archive, pos: -1}
+	size, err := ts.archive.Size()
+	ts.Require().NoError(err)
+
+	buf := make([]byte, 4)
+	n, err := ra.ReadAt(buf, 0)
+	ts.NoError(err)
+	ts.Equal(4, n)
+	ts.Equal([]byte("PK\x03\x04"), buf, "zip local file header signature")
+	ts.Equal(int64(4), ra.pos)
+
+	n, err = ra.ReadAt(buf, int64(size)-2)
+	ts.ErrorIs(err, io.EOF)
+	ts.Equal(2, n)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
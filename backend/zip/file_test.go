@@ -0,0 +1,216 @@
+package zip
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/backend/mem"
+)
+
+type fileTestSuite struct {
+	suite.Suite
+	memFs *mem.FileSystem
+	fs    *FileSystem
+}
+
+func (ts *fileTestSuite) SetupTest() {
+	ts.memFs = mem.NewFileSystem()
+	archive, err := ts.memFs.NewFile("", "/bundle.zip")
+	ts.Require().NoError(err)
+	ts.Require().NoError(writeArchive(archive, map[string]string{
+		"reports/daily.csv": "a,b,c\n1,2,3\n",
+		"big.txt":           strings.Repeat("0123456789", 10000),
+	}))
+	ts.fs, err = NewFileSystem(archive)
+	ts.Require().NoError(err)
+}
+
+func (ts *fileTestSuite) TestRead() {
+	file, err := ts.fs.NewFile("", "/reports/daily.csv")
+	ts.Require().NoError(err)
+
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("a,b,c\n1,2,3\n", string(data))
+	ts.NoError(file.Close())
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(12), size)
+
+	modified, err := file.LastModified()
+	ts.NoError(err)
+	ts.NotNil(modified)
+
+	missing, err := ts.fs.NewFile("", "/missing.txt")
+	ts.Require().NoError(err)
+	_, err = missing.Read(make([]byte, 1))
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = missing.Size()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	_, err = missing.LastModified()
+	ts.ErrorIs(err, vfs.ErrNotExist)
+	exists, err := missing.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileTestSuite) TestSeek() {
+	file, err := ts.fs.NewFile("", "/big.txt")
+	ts.Require().NoError(err)
+
+	pos, err := file.Seek(-5, io.SeekEnd)
+	ts.NoError(err)
+	ts.Equal(int64(99995), pos)
+	data, err := io.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("56789", string(data))
+
+	pos, err = file.Seek(12, io.SeekStart)
+	ts.NoError(err)
+	ts.Equal(int64(12), pos)
+	buf := make([]byte, 3)
+	_, err = io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("234", string(buf))
+
+	pos, err = file.Seek(1, io.SeekCurrent)
+	ts.NoError(err)
+	ts.Equal(int64(16), pos)
+	_, err = io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("678", string(buf))
+
+	_, err = file.Seek(-1, io.SeekStart)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidOffset)
+	_, err = file.Seek(0, 3)
+	ts.ErrorIs(err, vfs.ErrSeekInvalidWhence)
+	ts.NoError(file.Close())
+}
+
+func (ts *fileTestSuite) TestReadOnly() {
+	file, err := ts.fs.NewFile("", "/reports/daily.csv")
+	ts.Require().NoError(err)
+
+	_, err = file.Write([]byte("data"))
+	ts.ErrorIs(err, ErrReadOnly)
+	ts.ErrorIs(file.Touch(), ErrReadOnly)
+	ts.ErrorIs(file.Delete(), ErrDeleteNotSupported)
+
+	target, err := ts.memFs.NewFile("", "/target.csv")
+	ts.Require().NoError(err)
+	ts.ErrorIs(file.MoveToFile(target), ErrDeleteNotSupported)
+	_, err = file.MoveToLocation(target.Location())
+	ts.ErrorIs(err, ErrDeleteNotSupported)
+}
+
+func (ts *fileTestSuite) TestCopyToLocation() {
+	file, err := ts.fs.NewFile("", "/reports/daily.csv")
+	ts.Require().NoError(err)
+
+	loc, err := ts.memFs.NewLocation("", "/extracted/")
+	ts.Require().NoError(err)
+	copied, err := file.CopyToLocation(loc)
+	ts.Require().NoError(err)
+	ts.Equal("mem:///extracted/daily.csv", copied.URI())
+
+	data, err := io.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("a,b,c\n1,2,3\n", string(data))
+
+	// copy is only possible from the start of the file
+	_, err = file.Seek(1, io.SeekStart)
+	ts.NoError(err)
+	ts.ErrorIs(file.CopyToFile(copied), vfs.CopyToNotPossible)
+}
+
+func (ts *fileTestSuite) TestWriteMode() {
+	target, err := ts.memFs.NewFile("", "/out.zip")
+	ts.Require().NoError(err)
+	wfs, err := NewWriteFileSystem(target)
+	ts.Require().NoError(err)
+
+	file, err := wfs.NewFile("", "/dir/new.txt")
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte("new contents"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(12), size)
+
+	_, err = file.Read(make([]byte, 1))
+	ts.ErrorIs(err, ErrWriteOnly)
+	_, err = file.Seek(0, io.SeekStart)
+	ts.ErrorIs(err, ErrWriteOnly)
+
+	// entries may only be written once
+	_, err = file.Write([]byte("again"))
+	ts.EqualError(err, "zip entry /dir/new.txt has already been written")
+
+	empty, err := wfs.NewFile("", "/empty.txt")
+	ts.Require().NoError(err)
+	ts.NoError(empty.Touch())
+	ts.NoError(empty.Touch(), "touching an existing entry is a no-op")
+
+	ts.NoError(wfs.Close())
+
+	late, err := wfs.NewFile("", "/late.txt")
+	ts.Require().NoError(err)
+	ts.EqualError(late.Touch(), "zip archive is closed")
+}
+
+func (ts *fileTestSuite) TestWriteInterleaved() {
+	target, err := ts.memFs.NewFile("", "/interleaved.zip")
+	ts.Require().NoError(err)
+	wfs, err := NewWriteFileSystem(target)
+	ts.Require().NoError(err)
+
+	// the first file's entry is streamed to the archive, the others are buffered until it's finished
+	first, err := wfs.NewFile("", "/first.txt")
+	ts.Require().NoError(err)
+	second, err := wfs.NewFile("", "/second.txt")
+	ts.Require().NoError(err)
+	third, err := wfs.NewFile("", "/third.txt")
+	ts.Require().NoError(err)
+
+	_, err = first.Write([]byte("one "))
+	ts.NoError(err)
+	_, err = second.Write([]byte("two"))
+	ts.NoError(err)
+	ts.NoError(second.Close())
+	_, err = first.Write([]byte("done"))
+	ts.NoError(err)
+	_, err = third.Write([]byte("three"))
+	ts.NoError(err)
+	ts.NoError(first.Close())
+	ts.NoError(third.Close())
+
+	dupe, err := wfs.NewFile("", "/second.txt")
+	ts.Require().NoError(err)
+	_, err = dupe.Write([]byte("again"))
+	ts.EqualError(err, "zip entry /second.txt has already been written")
+	ts.Require().NoError(wfs.Close())
+
+	rfs, err := NewFileSystem(target)
+	ts.Require().NoError(err)
+	for name, expected := range map[string]string{"/first.txt": "one done", "/second.txt": "two", "/third.txt": "three"} {
+		f, err := rfs.NewFile("", name)
+		ts.Require().NoError(err)
+		contents, err := io.ReadAll(f)
+		ts.NoError(err)
+		ts.Equal(expected, string(contents), name)
+	}
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -1,5 +1,11 @@
 package vfs
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Error is a type that allows for error constants below
 type Error string
 
@@ -18,4 +24,65 @@ const (
 
 	// ErrSeekInvalidWhence - Whence is invalid.  Must be one of the following: 0 (io.SeekStart), 1 (io.SeekCurrent), or 2 (io.SeekEnd)
 	ErrSeekInvalidWhence = Error("seek: invalid whence")
+
+	// ErrSignedURLNotSupported - The file's file system can't produce signed URLs
+	ErrSignedURLNotSupported = Error("signed URLs are not supported by this file system")
+
+	// ErrSignedURLMethod - Signed URLs can only be produced for GET and PUT
+	ErrSignedURLMethod = Error("signed URLs are only supported for the GET and PUT methods")
+
+	// ErrChecksumAlgorithm - Checksum algorithm is unknown or unsupported by the file system
+	ErrChecksumAlgorithm = Error("unsupported checksum algorithm")
+
+	// ErrChecksumMismatch - Contents read or written don't match the checksum stored by the file system
+	ErrChecksumMismatch = Error("checksum mismatch: contents do not match the stored checksum")
+
+	// ErrPreconditionFailed - A conditional write or delete was rejected because the file changed or already exists
+	ErrPreconditionFailed = Error("precondition failed")
+
+	// ErrConcatenateNoSources - Concatenation requires at least one source file
+	ErrConcatenateNoSources = Error("concatenate: at least one source file is required")
 )
+
+// PreconditionFailedError is returned by conditional writes and deletes rejected by the file system because the file
+// changed since its version was read, or already exists.  It matches ErrPreconditionFailed with errors.Is.
+type PreconditionFailedError struct {
+	URI string
+	Err error
+}
+
+// Error returns a string representation of the error
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("%s for %s: %s", ErrPreconditionFailed, e.URI, e.Err)
+}
+
+// Unwrap returns the error returned by the file system
+func (e *PreconditionFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPreconditionFailed
+func (e *PreconditionFailedError) Is(target error) bool {
+	return target == ErrPreconditionFailed
+}
+
+// DeleteFilesError is returned by bulk deletes that couldn't delete some of the files.  Errors maps the relative path of
+// each of those files to the reason it wasn't deleted.
+type DeleteFilesError struct {
+	Errors map[string]error
+}
+
+// Error returns a string representation of the error, listing every file that couldn't be deleted
+func (e *DeleteFilesError) Error() string {
+	paths := make([]string, 0, len(e.Errors))
+	for p := range e.Errors {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	failures := make([]string, len(paths))
+	for i, p := range paths {
+		failures[i] = fmt.Sprintf("%s: %s", p, e.Errors[p])
+	}
+	return fmt.Sprintf("unable to delete %d file(s): %s", len(paths), strings.Join(failures, "; "))
+}
@@ -13,6 +13,7 @@ require (
 	github.com/fatih/color v1.16.0
 	github.com/fsouza/fake-gcs-server v1.47.7
 	github.com/jlaffaye/ftp v0.2.0
+	github.com/klauspost/compress v1.17.4
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/pkg/errors v0.9.1
 	github.com/pkg/sftp v1.13.6
@@ -1,16 +1,23 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // MD5 is used for integrity checks, not security
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"io"
 	"net/url"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/c2fo/vfs/v6"
+	"github.com/c2fo/vfs/v6/options"
 )
 
 const (
@@ -223,3 +230,161 @@ func UpdateLastModifiedByMoving(file vfs.File) error {
 	}
 	return nil
 }
+
+// DeleteFiles deletes the files of the given relative paths at location.  If location implements vfs.BulkDeleter,
+// its DeleteFiles method is used, otherwise each file is deleted with location.DeleteFile.  Files that couldn't be
+// deleted are reported in a *vfs.DeleteFilesError.
+func DeleteFiles(location vfs.Location, relFilePaths []string, opts ...options.DeleteOption) error {
+	if bulk, ok := location.(vfs.BulkDeleter); ok {
+		return bulk.DeleteFiles(relFilePaths, opts...)
+	}
+
+	failures := map[string]error{}
+	for _, p := range relFilePaths {
+		if err := location.DeleteFile(p, opts...); err != nil {
+			failures[p] = err
+		}
+	}
+	if len(failures) > 0 {
+		return &vfs.DeleteFilesError{Errors: failures}
+	}
+	return nil
+}
+
+// DeleteFilesByPrefix deletes the files listed by location.ListByPrefix(prefix) with DeleteFiles.
+func DeleteFilesByPrefix(location vfs.Location, prefix string, opts ...options.DeleteOption) error {
+	names, err := location.ListByPrefix(prefix)
+	if err != nil {
+		return err
+	}
+
+	// ListByPrefix returns names relative to the prefix's directory
+	if dir := path.Dir(prefix); dir != "." {
+		for i := range names {
+			names[i] = path.Join(dir, names[i])
+		}
+	}
+	return DeleteFiles(location, names, opts...)
+}
+
+// SignedURL returns a URL to download (http.MethodGet) or upload (http.MethodPut) file, valid for expiry, if file
+// implements vfs.URLSigner.  Otherwise, an error wrapping vfs.ErrSignedURLNotSupported is returned.
+func SignedURL(file vfs.File, method string, expiry time.Duration) (string, error) {
+	if signer, ok := file.(vfs.URLSigner); ok {
+		return signer.SignedURL(method, expiry)
+	}
+	return "", fmt.Errorf("%w: %s", vfs.ErrSignedURLNotSupported, file.Location().FileSystem().Name())
+}
+
+// Concatenate replaces the contents of target with the contents of sources, in order.  If target implements
+// vfs.Concatenator, its ConcatenateFrom method is used, otherwise the sources are streamed with ConcatenateStreamed.
+func Concatenate(target vfs.File, sources ...vfs.File) error {
+	if concatenator, ok := target.(vfs.Concatenator); ok {
+		return concatenator.ConcatenateFrom(sources...)
+	}
+	return ConcatenateStreamed(target, sources...)
+}
+
+// ConcatenateStreamed replaces the contents of target with the contents of sources, in order, reading each source from
+// its beginning and writing it to target.  The sources and target are closed afterwards.  target can't be one of the
+// sources, since it's overwritten.
+func ConcatenateStreamed(target vfs.File, sources ...vfs.File) error {
+	if len(sources) == 0 {
+		return vfs.ErrConcatenateNoSources
+	}
+	for _, source := range sources {
+		if source.URI() == target.URI() {
+			return fmt.Errorf("concatenate: %s can't be streamed onto itself", target.URI())
+		}
+	}
+
+	buffer := make([]byte, TouchCopyMinBufferSize)
+	var size int64
+	for _, source := range sources {
+		if _, err := source.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		n, err := io.CopyBuffer(target, source, buffer)
+		if err != nil {
+			return err
+		}
+		size += n
+		if err := source.Close(); err != nil {
+			return err
+		}
+	}
+
+	// make sure the target is written even if the sources are empty
+	if size == 0 {
+		if _, err := target.Write([]byte{}); err != nil {
+			return err
+		}
+	}
+	return target.Close()
+}
+
+// NewChecksumHash returns a hash.Hash computing the checksum for algorithm, or vfs.ErrChecksumAlgorithm if algorithm is
+// unknown.
+func NewChecksumHash(algorithm vfs.ChecksumAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case vfs.ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case vfs.ChecksumMD5:
+		return md5.New(), nil //nolint:gosec // MD5 is used for integrity checks, not security
+	case vfs.ChecksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", vfs.ErrChecksumAlgorithm, algorithm)
+	}
+}
+
+// ComputeChecksum returns the checksum for algorithm of the contents of file, streamed from the beginning of the file.
+// The file is closed afterwards.
+func ComputeChecksum(file vfs.File, algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	h, err := NewChecksumHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Checksum returns the checksum for algorithm of the contents of file.  If file implements vfs.Checksummer, its
+// Checksum is used, otherwise the checksum is computed by streaming the file with ComputeChecksum.
+func Checksum(file vfs.File, algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	if checksummer, ok := file.(vfs.Checksummer); ok {
+		return checksummer.Checksum(algorithm)
+	}
+	return ComputeChecksum(file, algorithm)
+}
+
+// NewChecksumReader wraps reader, hashing everything read from it with h.  When reader is fully consumed, the checksum
+// is compared to expected and vfs.ErrChecksumMismatch is returned in place of io.EOF if they differ.
+func NewChecksumReader(reader io.ReadCloser, h hash.Hash, expected []byte) io.ReadCloser {
+	return &checksumReader{ReadCloser: reader, hash: h, expected: expected}
+}
+
+type checksumReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected []byte
+}
+
+// Read reads from the underlying reader, verifying the checksum once it's fully consumed
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && !bytes.Equal(r.hash.Sum(nil), r.expected) {
+		return n, vfs.ErrChecksumMismatch
+	}
+	return n, err
+}
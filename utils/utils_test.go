@@ -1,17 +1,25 @@
 package utils_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/c2fo/vfs/v6"
 	_os "github.com/c2fo/vfs/v6/backend/os"
 	"github.com/c2fo/vfs/v6/mocks"
+	"github.com/c2fo/vfs/v6/options"
 	"github.com/c2fo/vfs/v6/utils"
 )
 
@@ -868,6 +876,177 @@ func (s *utilsTest) TestTouchCopyBufferedNonDefaultBufferSize() {
 
 }
 
+// bulkDeleterLocation is a mock Location implementing vfs.BulkDeleter
+type bulkDeleterLocation struct {
+	*mocks.Location
+	deleted []string
+}
+
+func (l *bulkDeleterLocation) DeleteFiles(relFilePaths []string, _ ...options.DeleteOption) error {
+	l.deleted = append(l.deleted, relFilePaths...)
+	return nil
+}
+
+func (s *utilsTest) TestDeleteFiles() {
+	// locations without a bulk delete delete each file
+	location := &mocks.Location{}
+	location.On("DeleteFile", "a.txt").Return(nil).Once()
+	location.On("DeleteFile", "b.txt").Return(vfs.ErrNotExist).Once()
+	location.On("DeleteFile", "c.txt").Return(nil).Once()
+	err := utils.DeleteFiles(location, []string{"a.txt", "b.txt", "c.txt"})
+	s.Equal(&vfs.DeleteFilesError{Errors: map[string]error{"b.txt": vfs.ErrNotExist}}, err)
+	s.EqualError(err, "unable to delete 1 file(s): b.txt: file does not exist")
+	location.AssertExpectations(s.T())
+
+	// bulk deleters are used when implemented
+	bulk := &bulkDeleterLocation{Location: &mocks.Location{}}
+	s.NoError(utils.DeleteFiles(bulk, []string{"a.txt", "b.txt"}))
+	s.Equal([]string{"a.txt", "b.txt"}, bulk.deleted)
+	bulk.AssertNotCalled(s.T(), "DeleteFile", mock.Anything)
+}
+
+func (s *utilsTest) TestDeleteFilesByPrefix() {
+	location := &mocks.Location{}
+	location.On("ListByPrefix", "logs/2024-").Return([]string{"2024-01.log", "2024-02.log"}, nil).Once()
+	location.On("DeleteFile", "logs/2024-01.log").Return(nil).Once()
+	location.On("DeleteFile", "logs/2024-02.log").Return(nil).Once()
+	s.NoError(utils.DeleteFilesByPrefix(location, "logs/2024-"))
+	location.AssertExpectations(s.T())
+
+	bulk := &bulkDeleterLocation{Location: &mocks.Location{}}
+	bulk.On("ListByPrefix", "file").Return([]string{"file1.txt", "file2.txt"}, nil).Once()
+	s.NoError(utils.DeleteFilesByPrefix(bulk, "file"))
+	s.Equal([]string{"file1.txt", "file2.txt"}, bulk.deleted)
+
+	location = &mocks.Location{}
+	location.On("ListByPrefix", "file").Return(nil, errors.New("list failed")).Once()
+	s.EqualError(utils.DeleteFilesByPrefix(location, "file"), "list failed")
+	location.AssertNotCalled(s.T(), "DeleteFile", mock.Anything)
+}
+
+// signerFile is a mock File implementing vfs.URLSigner
+type signerFile struct {
+	*mocks.File
+}
+
+func (f *signerFile) SignedURL(method string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("https://signed/?method=%s&expiry=%s", method, expiry), nil
+}
+
+func (s *utilsTest) TestSignedURL() {
+	signed, err := utils.SignedURL(&signerFile{File: &mocks.File{}}, http.MethodPut, time.Minute)
+	s.NoError(err)
+	s.Equal("https://signed/?method=PUT&expiry=1m0s", signed)
+
+	osfs := &_os.FileSystem{}
+	file, err := osfs.NewFile("", "/some/file.txt")
+	s.Require().NoError(err)
+	_, err = utils.SignedURL(file, http.MethodGet, time.Minute)
+	s.ErrorIs(err, vfs.ErrSignedURLNotSupported)
+	s.EqualError(err, "signed URLs are not supported by this file system: os")
+}
+
+type checksummerFile struct {
+	*mocks.File
+}
+
+func (f *checksummerFile) Checksum(algorithm vfs.ChecksumAlgorithm) ([]byte, error) {
+	return []byte(algorithm), nil
+}
+
+func (s *utilsTest) TestChecksum() {
+	sum, err := utils.Checksum(&checksummerFile{File: &mocks.File{}}, vfs.ChecksumMD5)
+	s.NoError(err)
+	s.Equal([]byte("MD5"), sum, "a vfs.Checksummer's stored checksum should be used")
+
+	tests := map[vfs.ChecksumAlgorithm]string{
+		vfs.ChecksumCRC32C: "9a71bb4c",
+		vfs.ChecksumMD5:    "5d41402abc4b2a76b9719d911017c592",
+		vfs.ChecksumSHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}
+	for algorithm, expected := range tests {
+		file := &mocks.File{}
+		file.On("Seek", int64(0), io.SeekStart).Return(int64(0), nil).Once()
+		file.On("Read", mock.Anything).Run(func(args mock.Arguments) {
+			copy(args.Get(0).([]byte), "hello")
+		}).Return(5, io.EOF).Once()
+		file.On("Close").Return(nil).Once()
+
+		sum, err := utils.Checksum(file, algorithm)
+		s.NoError(err, algorithm)
+		s.Equal(expected, hex.EncodeToString(sum), algorithm)
+		file.AssertExpectations(s.T())
+	}
+
+	_, err = utils.Checksum(&mocks.File{}, "CRC64")
+	s.ErrorIs(err, vfs.ErrChecksumAlgorithm)
+	s.EqualError(err, `unsupported checksum algorithm: "CRC64"`)
+}
+
+// concatenatorFile is a mock File implementing vfs.Concatenator
+type concatenatorFile struct {
+	*mocks.File
+	sources []vfs.File
+}
+
+func (f *concatenatorFile) ConcatenateFrom(sources ...vfs.File) error {
+	f.sources = sources
+	return nil
+}
+
+func (s *utilsTest) TestConcatenate() {
+	target := &concatenatorFile{File: &mocks.File{}}
+	source := &mocks.File{}
+	s.NoError(utils.Concatenate(target, source, source))
+	s.Len(target.sources, 2, "a vfs.Concatenator should concatenate natively")
+
+	osfs := &_os.FileSystem{}
+	dir := s.T().TempDir()
+	var sources []vfs.File
+	for i, contents := range []string{"shard 1,", "", "shard 3"} {
+		file, err := osfs.NewFile("", path.Join(dir, fmt.Sprintf("shard-%d.txt", i)))
+		s.Require().NoError(err)
+		_, err = file.Write([]byte(contents))
+		s.Require().NoError(err)
+		s.Require().NoError(file.Close())
+		sources = append(sources, file)
+	}
+	// the cursor of a source doesn't matter
+	_, err := sources[0].Seek(3, io.SeekStart)
+	s.Require().NoError(err)
+
+	concatenated, err := osfs.NewFile("", path.Join(dir, "all.txt"))
+	s.Require().NoError(err)
+	s.NoError(utils.Concatenate(concatenated, sources...))
+	contents, err := io.ReadAll(concatenated)
+	s.NoError(err)
+	s.Equal("shard 1,shard 3", string(contents))
+
+	s.ErrorIs(utils.Concatenate(concatenated), vfs.ErrConcatenateNoSources)
+	s.Error(utils.Concatenate(concatenated, sources[0], concatenated), "a file can't be streamed onto itself")
+
+	empty, err := osfs.NewFile("", path.Join(dir, "empty.txt"))
+	s.Require().NoError(err)
+	s.NoError(utils.Concatenate(empty, sources[1]))
+	exists, err := empty.Exists()
+	s.NoError(err)
+	s.True(exists, "concatenating empty sources should create the target")
+}
+
+func (s *utilsTest) TestNewChecksumReader() {
+	h, err := utils.NewChecksumHash(vfs.ChecksumSHA256)
+	s.Require().NoError(err)
+	expected := sha256.Sum256([]byte("hello"))
+	contents, err := io.ReadAll(utils.NewChecksumReader(io.NopCloser(strings.NewReader("hello")), h, expected[:]))
+	s.NoError(err)
+	s.Equal("hello", string(contents))
+
+	h, err = utils.NewChecksumHash(vfs.ChecksumSHA256)
+	s.Require().NoError(err)
+	_, err = io.ReadAll(utils.NewChecksumReader(io.NopCloser(strings.NewReader("jello")), h, expected[:]))
+	s.ErrorIs(err, vfs.ErrChecksumMismatch)
+}
+
 func TestUtils(t *testing.T) {
 	suite.Run(t, new(utilsTest))
 }
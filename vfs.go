@@ -224,6 +224,57 @@ type File interface {
 	URI() string
 }
 
+// BulkDeleter is an optional interface implemented by Locations whose file system can delete many files with fewer
+// requests than one per file.  See utils.DeleteFiles for deleting files from any Location.
+type BulkDeleter interface {
+	// DeleteFiles deletes the files of the given relative paths at the location.
+	//
+	//   * Accepts relative file paths.
+	//   * Files that couldn't be deleted are reported in a *DeleteFilesError.  Other files are deleted regardless.
+	DeleteFiles(relFilePaths []string, deleteOpts ...options.DeleteOption) error
+}
+
+// URLSigner is an optional interface implemented by Files whose file system can produce signed URLs, granting
+// time-limited access to the file without credentials.  See utils.SignedURL for signing URLs of any File.
+type URLSigner interface {
+	// SignedURL returns a URL to download (http.MethodGet) or upload (http.MethodPut) the file, valid for expiry.
+	//
+	//   * Any other method returns ErrSignedURLMethod.
+	SignedURL(method string, expiry time.Duration) (string, error)
+}
+
+// ChecksumAlgorithm names a hash used to verify the integrity of a file's contents.
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumCRC32C is the CRC-32 checksum using the Castagnoli polynomial, encoded big-endian.
+	ChecksumCRC32C ChecksumAlgorithm = "CRC32C"
+	// ChecksumMD5 is the MD5 digest.
+	ChecksumMD5 ChecksumAlgorithm = "MD5"
+	// ChecksumSHA256 is the SHA-256 digest.
+	ChecksumSHA256 ChecksumAlgorithm = "SHA256"
+)
+
+// Checksummer is an optional interface implemented by Files whose file system stores checksums of their contents.
+// See utils.Checksum for checksumming any File.
+type Checksummer interface {
+	// Checksum returns the raw checksum of the file's contents for algorithm.  The file system's stored checksum is
+	// returned when it has one for algorithm, otherwise the checksum is computed by streaming the file.
+	//
+	//   * Unknown algorithms return ErrChecksumAlgorithm.
+	Checksum(algorithm ChecksumAlgorithm) ([]byte, error)
+}
+
+// Concatenator is an optional interface implemented by Files whose file system can concatenate files server-side,
+// without downloading and uploading their contents.  See utils.Concatenate for concatenating files of any file system.
+type Concatenator interface {
+	// ConcatenateFrom replaces the file's contents with the contents of sources, in order.
+	//
+	//   * Sources that can't be concatenated natively, such as files of other file systems, are streamed instead.
+	//   * No sources returns ErrConcatenateNoSources.
+	ConcatenateFrom(sources ...File) error
+}
+
 // Options are structs that contain various options specific to the file system
 type Options interface{}
 
@@ -23,5 +23,9 @@ But may also be use the full scheme uri:
 Copy a file from Google Cloud Storage to Amazon S3
 
 	vfscp gs://googlebucket/some/path/photo.jpg s3://awsS3bucket/path/to/photo.jpg
+
+Copy a file published over HTTPS to Amazon S3
+
+	vfscp https://downloads.acme.com/exports/daily.csv s3://awsS3bucket/path/to/daily.csv
 */
 package main
@@ -3,6 +3,7 @@ Package vfssimple provides a basic and easy to use set of functions to any suppo
   - Local OS:             file:///some/path/to/file.txt
   - Amazon S3:            s3://mybucket/path/to/file.txt
   - Google Cloud Storage: gs://mybucket/path/to/file.txt
+  - HTTP(S) (read-only):  https://host.com/path/to/file.txt
 
 # Usage
 
@@ -10,6 +10,7 @@ import (
 	"github.com/c2fo/vfs/v6/backend"
 	_ "github.com/c2fo/vfs/v6/backend/all" // register all backends
 	"github.com/c2fo/vfs/v6/backend/azure"
+	"github.com/c2fo/vfs/v6/backend/http"
 	"github.com/c2fo/vfs/v6/backend/mem"
 	"github.com/c2fo/vfs/v6/backend/os"
 )
@@ -77,6 +78,9 @@ func parseURI(uri string) (scheme, authority, path string, err error) {
 	path = u.Path
 	if azure.IsValidURI(u) {
 		authority, path, err = azure.ParsePath(path)
+	} else if (scheme == http.Scheme || scheme == http.SecureScheme) && u.RawQuery != "" {
+		// http files keep their query string, ie the signature of a pre-signed URL
+		path += "?" + u.RawQuery
 	}
 
 	if u.User.String() != "" {
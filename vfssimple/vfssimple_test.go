@@ -152,6 +152,14 @@ func (s *vfssimplesuite) TestParseURI() {
 			authority: "user@host.com:22",
 			path:      "/path/to/file.txt",
 		},
+		{
+			uri:       "https://host.com/path/to/file.txt?X-Signature=abc%2F123&expires=1",
+			err:       nil,
+			message:   "valid https uri, with query string",
+			scheme:    "https",
+			authority: "host.com",
+			path:      "/path/to/file.txt?X-Signature=abc%2F123&expires=1",
+		},
 	}
 
 	for _, test := range tests {